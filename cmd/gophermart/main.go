@@ -3,6 +3,7 @@ package main
 import (
 	"graduation/internal/app"
 	"log"
+	_ "time/tzdata"
 )
 
 func main() {
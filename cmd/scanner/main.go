@@ -0,0 +1,140 @@
+// Command scanner checks tickets at the door without network. It reads
+// ticket tokens from stdin, one per line as a QR reader types them, checks
+// each against an event bundle exported from /api/event/bundle/{id} and
+// appends accepted scans to a log that is later uploaded to
+// POST /api/event/checkins/{id}.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"graduation/internal/bundle"
+	"graduation/internal/ticket"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	bundlePath := flag.String("bundle", "event.bundle", "event bundle file")
+	keysPath := flag.String("keys", "ticket-keys.json", "pinned key set from /.well-known/ticket-keys")
+	keysURL := flag.String("keys-url", "", "URL of /.well-known/ticket-keys, fetched once into -keys while online")
+	logPath := flag.String("log", "checkins.log", "check-in log file")
+	flag.Parse()
+
+	if err := run(*bundlePath, *keysPath, *keysURL, *logPath); err != nil {
+		log.Panic(err.Error())
+	}
+}
+
+// loadKeys reads the pinned key set. When the file is missing it is fetched
+// from keysURL and saved, so later runs work without network. Without pinned
+// keys the scanner refuses to run.
+func loadKeys(keysPath, keysURL string) (ticket.JWKS, error) {
+	var pinned ticket.JWKS
+
+	data, err := os.ReadFile(keysPath)
+	if errors.Is(err, os.ErrNotExist) && keysURL != "" {
+		data, err = fetchKeys(keysURL)
+		if err != nil {
+			return pinned, fmt.Errorf("cannot fetch keys: %w", err)
+		}
+		if err := os.WriteFile(keysPath, data, 0644); err != nil {
+			return pinned, fmt.Errorf("cannot save keys: %w", err)
+		}
+	}
+	if err != nil {
+		return pinned, fmt.Errorf("cannot read keys, pass -keys or -keys-url: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &pinned); err != nil {
+		return pinned, fmt.Errorf("cannot parse keys: %w", err)
+	}
+	if len(pinned.Keys) == 0 {
+		return pinned, bundle.ErrNoKeys
+	}
+
+	return pinned, nil
+}
+
+func fetchKeys(keysURL string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(keysURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+}
+
+func run(bundlePath, keysPath, keysURL, logPath string) error {
+	pinned, err := loadKeys(keysPath, keysURL)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return fmt.Errorf("cannot read bundle: %w", err)
+	}
+
+	b, err := bundle.Parse(strings.TrimSpace(string(data)), pinned)
+	if err != nil {
+		return fmt.Errorf("cannot parse bundle: %w", err)
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open log: %w", err)
+	}
+	defer logFile.Close()
+
+	scanner, err := bundle.NewScanner(b, logFile)
+	if err != nil {
+		return fmt.Errorf("cannot init scanner: %w", err)
+	}
+
+	if err := scanner.Replay(logFile); err != nil {
+		return fmt.Errorf("cannot replay log: %w", err)
+	}
+
+	fmt.Printf("event %d, bundle version %d, %d tickets\n", b.EventID, b.Version, len(b.Tickets)+len(b.Redeemed))
+
+	input := bufio.NewScanner(os.Stdin)
+	input.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for input.Scan() {
+		token := strings.TrimSpace(input.Text())
+		if token == "" {
+			continue
+		}
+
+		tick, err := scanner.Check(token, time.Now())
+		switch {
+		case err == nil:
+			fmt.Printf("OK      user %d\n", tick.UserID)
+		case errors.Is(err, bundle.ErrRedeemed):
+			fmt.Println("REJECT  already checked in")
+		case errors.Is(err, bundle.ErrRevoked):
+			fmt.Println("REJECT  ticket cancelled")
+		case errors.Is(err, bundle.ErrWrongEvent):
+			fmt.Println("REJECT  ticket for another event")
+		case errors.Is(err, bundle.ErrUnknown), errors.Is(err, bundle.ErrInvalid):
+			fmt.Println("REJECT  ticket not valid")
+		default:
+			return err
+		}
+	}
+
+	return input.Err()
+}
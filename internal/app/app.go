@@ -4,10 +4,16 @@ import (
 	"fmt"
 	"graduation/internal/config"
 	"graduation/internal/handlers"
+	"graduation/internal/hasher"
+	"graduation/internal/imaging"
 	"graduation/internal/logger"
 	"graduation/internal/notification"
+	"graduation/internal/ostorage"
+	"graduation/internal/qr"
 	"graduation/internal/router"
 	"graduation/internal/storage"
+	"graduation/internal/storage/memory"
+	"graduation/internal/templates"
 	"graduation/internal/ticket"
 
 	"github.com/go-chi/chi/v5"
@@ -22,6 +28,24 @@ type App struct {
 	notification *notification.Notification
 }
 
+// initStorage opens the storage of conf.DatabaseBackend. The memory one needs
+// no database and forgets everything on exit, it is meant for demos.
+func initStorage(conf *config.Storage) (storage.Storage, error) {
+	switch conf.DatabaseBackend {
+	case storage.BackendPostgres:
+		return storage.InitStorage(conf)
+	case storage.BackendMemory:
+		ost, err := ostorage.Init(&conf.ObjectStorage)
+		if err != nil {
+			return nil, fmt.Errorf("cannot connection object storage: %w", err)
+		}
+
+		return memory.New(ost), nil
+	}
+
+	return nil, fmt.Errorf("unknown database backend %q", conf.DatabaseBackend)
+}
+
 func newApp() (*App, error) {
 	conf, err := config.LoadServerConfigure()
 	if err != nil {
@@ -32,18 +56,61 @@ func newApp() (*App, error) {
 		return nil, fmt.Errorf("cannot init logger: %w", err)
 	}
 
-	storage, err := storage.InitStorage(&conf.Storage)
+	storage, err := initStorage(&conf.Storage)
 	if err != nil {
 		return nil, fmt.Errorf("cannot init storage: %w", err)
 	}
 
-	tick := ticket.Init(&conf.TicketKey)
+	tick, err := ticket.Init(&conf.TicketKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init ticket: %w", err)
+	}
 
 	router := router.CreateRouter()
 
-	handler := handlers.Init(storage, tick, conf.TokenSecretKey, conf.TokenEXP)
+	hash, err := hasher.Init(&conf.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init hasher: %w", err)
+	}
+
+	qr, err := qr.Init(&conf.QRCode)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init qr: %w", err)
+	}
+
+	tmpl, err := templates.Init(&conf.Templates)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init templates: %w", err)
+	}
+
+	images, err := imaging.Init(&conf.Images)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init imaging: %w", err)
+	}
+
+	location, err := conf.EventLocation()
+	if err != nil {
+		return nil, fmt.Errorf("cannot init events: %w", err)
+	}
+
+	notification := notification.Init(storage, &conf.SMTP, &conf.Channels, qr, tmpl, conf.BaseURL, location)
 
-	notification := notification.Init(storage, &conf.SMTP)
+	handler := handlers.Init(handlers.Deps{
+		Storage:        storage,
+		Ticket:         tick,
+		Hasher:         hash,
+		QR:             qr,
+		Templates:      tmpl,
+		Images:         images,
+		BaseURL:        conf.BaseURL,
+		Location:       location,
+		Webhook:        conf.WebhookEnabled,
+		WebhookHosts:   conf.WebhookHosts(),
+		TokenSecretKey: conf.TokenSecretKey,
+		TokenEXP:       conf.TokenEXP,
+		RefreshEXP:     conf.Refresh.TokenEXP,
+		Notification:   notification,
+	})
 
 	logger.Info("Running server: address:%s port:%d", conf.Host, conf.Port)
 
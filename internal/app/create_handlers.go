@@ -3,6 +3,7 @@ package app
 import (
 	"graduation/internal/authorization"
 	"graduation/internal/compression"
+	"graduation/internal/entity"
 	"graduation/internal/logger"
 	"net/http"
 
@@ -16,34 +17,126 @@ func (a *App) createMiddlewareHandlers() {
 
 func (a *App) createHandlers() {
 	a.router.Route("/api/event", func(r chi.Router) {
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
 			Post("/creat", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.EventCreat(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Post("/series", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.SeriesCreat(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Patch("/series/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.SeriesUpdate(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Post("/series/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.SeriesCancel(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
 			Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.EventGet(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Get("/{id}/ics", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventICS(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Get("/{id}/reminders", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.RemindersGet(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Put("/{id}/reminders", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventRemindersSet(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Post("/{id}/images", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventImagesUpload(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Put("/{id}/images/order", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventImagesOrder(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Put("/{id}/images/cover", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventImageCover(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Delete("/{id}/images/{filename}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventImageDell(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Patch("/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.EventUpdate(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
 			Post("/dell/{id}", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.EventDell(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
 			Post("/close/{id}", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.EventClose(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
 			Get("/valid/{id}", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.ValidTicket(w, r)
 			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Post("/checkin/{token}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.Checkin(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Get("/checkins/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.CheckinCount(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Post("/checkins/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.CheckinMerge(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Get("/bundle/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.TicketBundle(w, r)
+			})
 	})
 
 	a.router.Route("/api", func(r chi.Router) {
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
 			Get("/events", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.EventsGet(w, r)
 			})
@@ -58,30 +151,134 @@ func (a *App) createHandlers() {
 			a.handler.Login(w, r)
 		})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.Post("/refresh", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.Refresh(w, r)
+		})
+
+		r.Get("/verify", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.VerifyMail(w, r)
+		})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Post("/verify/resend", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.VerifyResend(w, r)
+			})
+
+		r.Post("/password/forgot", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.PasswordForgot(w, r)
+		})
+
+		r.Post("/password/reset", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.PasswordReset(w, r)
+		})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Post("/logout", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.Logout(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Post("/logout/all", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.LogoutAll(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
 			Post("/add/{id}", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.UserAdd(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
 			Post("/dell/{id}", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.UserDell(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
 			Get("/events", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.UserEvents(w, r)
 			})
 
-		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey)).
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
 			Get("/tickets", func(w http.ResponseWriter, r *http.Request) {
 				a.handler.UserTickets(w, r)
 			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Post("/calendar/token", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.CalendarToken(w, r)
+			})
+
+		r.Get("/calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.CalendarFeed(w, r)
+		})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Put("/locale", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.UserLocale(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage),
+			authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)).
+			Put("/digest", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.UserDigest(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Get("/channels", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.UserChannelsGet(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Put("/channels", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.UserChannelsSet(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Put("/reminders/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.UserRemindersSet(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Delete("/reminders/{id}", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.UserRemindersDell(w, r)
+			})
+
+		r.With(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage)).
+			Get("/tickets/{eventID}/qr", func(w http.ResponseWriter, r *http.Request) {
+				a.handler.TicketQR(w, r)
+			})
+	})
+
+	a.router.Route("/api/admin", func(r chi.Router) {
+		r.Use(authorization.AuthorizationMiddleware(a.conf.TokenSecretKey, a.storage))
+		r.Use(authorization.RoleMiddleware(entity.RoleAdmin))
+
+		r.Post("/role/grant", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.RoleGrant(w, r)
+		})
+
+		r.Post("/role/revoke", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.RoleRevoke(w, r)
+		})
+
+		r.Get("/outbox", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.OutboxList(w, r)
+		})
+
+		r.Post("/outbox/{id}/replay", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.OutboxReplay(w, r)
+		})
 	})
 
 	a.router.Route("/api/images", func(r chi.Router) {
 		r.Get("/{filename}", func(w http.ResponseWriter, r *http.Request) {
 			a.handler.Image(w, r)
 		})
+		r.Head("/{filename}", func(w http.ResponseWriter, r *http.Request) {
+			a.handler.Image(w, r)
+		})
+	})
+
+	a.router.Get("/.well-known/ticket-keys", func(w http.ResponseWriter, r *http.Request) {
+		a.handler.TicketKeys(w, r)
 	})
 }
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"context"
+	"graduation/internal/logger"
+	"time"
+)
+
+// imageGCGrace keeps objects this young: their upload may still be in a
+// transaction that has not committed its photo rows.
+const imageGCGrace = time.Hour
+
+// loopImageGC removes objects that no photo row names, left by failed
+// deletes, until ctx is cancelled.
+func (a *App) loopImageGC(ctx context.Context) {
+	ticker := time.NewTicker(a.conf.ImageGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := a.storage.CollectImageGarbage(ctx, time.Now().Add(-imageGCGrace))
+			if err != nil {
+				logger.Error("cannot collect images: %v", err)
+				continue
+			}
+			if removed > 0 {
+				logger.Info("Removed %d orphaned images", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// lifecycle ties the background parts of the app to a root context that is
+// cancelled on SIGINT or SIGTERM.
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newLifecycle(parent context.Context) *lifecycle {
+	ctx, cancel := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	return &lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Go runs f in a goroutine. f must return soon after its context is done.
+func (l *lifecycle) Go(f func(ctx context.Context)) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		f(l.ctx)
+	}()
+}
+
+// Wait waits for the goroutines started by Go, at most until ctx is done.
+func (l *lifecycle) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"graduation/internal/logger"
 	"net/http"
@@ -17,9 +19,57 @@ func Run() error {
 	app.createMiddlewareHandlers()
 	app.createHandlers()
 
-	go app.notification.LoopNotification()
+	life := newLifecycle(context.Background())
+	defer life.cancel()
 
-	address := app.conf.Host + ":" + strconv.Itoa(app.conf.Port)
+	life.Go(app.notification.LoopNotification)
+	if app.conf.ImageGCInterval > 0 {
+		life.Go(app.loopImageGC)
+	}
+
+	// Requests keep their own contexts, so a signal does not cancel the
+	// ones in flight: Shutdown lets them finish.
+	server := &http.Server{
+		Addr:    app.conf.Host + ":" + strconv.Itoa(app.conf.Port),
+		Handler: app.router,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serveErr:
+		err = fmt.Errorf("cannot serve: %w", err)
+		life.cancel()
+	case <-life.ctx.Done():
+		logger.Info("Shutdown server")
+	}
+
+	return errors.Join(err, app.shutdown(server, life))
+}
+
+// shutdown drains requests, waits for the notification loop and closes the
+// storage, all within the configured timeout.
+func (a *App) shutdown(server *http.Server, life *lifecycle) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.conf.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("cannot shutdown server: %w", err))
+	}
+
+	if err := life.Wait(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("cannot stop notification: %w", err))
+	}
+
+	if err := a.storage.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("cannot close storage: %w", err))
+	}
+
+	logger.Info("Server stopped")
 
-	return http.ListenAndServe(address, app.router)
+	return errors.Join(errs...)
 }
@@ -1,8 +1,11 @@
 package authorization
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 
@@ -11,10 +14,16 @@ import (
 
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID int
+	UserID    int
+	Role      string
+	SessionID int
 }
 
-func getUserID(secretKey, tokenString string) (int, error) {
+type SessionChecker interface {
+	SessionActive(ctx context.Context, sessionID int) (bool, error)
+}
+
+func getClaims(secretKey, tokenString string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims,
 		func(t *jwt.Token) (interface{}, error) {
@@ -24,32 +33,49 @@ func getUserID(secretKey, tokenString string) (int, error) {
 			return []byte(secretKey), nil
 		})
 	if err != nil {
-		return 0, fmt.Errorf("cannot pars: %v", err)
+		return nil, fmt.Errorf("cannot pars: %v", err)
 	}
 
 	if !token.Valid {
-		return 0, fmt.Errorf("token is not valid: %v", err)
+		return nil, fmt.Errorf("token is not valid: %v", err)
 	}
 
-	return claims.UserID, nil
+	if claims.SessionID == 0 {
+		return nil, errors.New("token without session")
+	}
+
+	return claims, nil
 }
 
-func AuthorizationMiddleware(secretKey string) func(http.Handler) http.Handler {
+func AuthorizationMiddleware(secretKey string, sessions SessionChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			cookie, err := r.Cookie("Authorization")
 			if err != nil {
 				logger.Error("cookies do not contain a token: %v", err)
-				w.WriteHeader(http.StatusUnauthorized)
+				problem.Write(w, http.StatusUnauthorized, "access token is missing")
 				return
 			}
-			id, err := getUserID(secretKey, cookie.Value)
+			claims, err := getClaims(secretKey, cookie.Value)
 			if err != nil {
 				logger.Error("token does not pass validation")
-				w.WriteHeader(http.StatusUnauthorized)
+				problem.Write(w, http.StatusUnauthorized, "access token not valid or expired")
+				return
+			}
+			active, err := sessions.SessionActive(r.Context(), claims.SessionID)
+			if err != nil {
+				logger.Error("cannot check session: %v", err)
+				problem.Write(w, http.StatusInternalServerError, "")
+				return
+			}
+			if !active {
+				logger.Error("session %d revoked", claims.SessionID)
+				problem.Write(w, http.StatusUnauthorized, "session revoked")
 				return
 			}
-			r.Header.Set("User_id", strconv.Itoa(id))
+			r.Header.Set("User_id", strconv.Itoa(claims.UserID))
+			r.Header.Set("User_role", claims.Role)
+			r.Header.Set("Session_id", strconv.Itoa(claims.SessionID))
 
 			next.ServeHTTP(w, r)
 		})
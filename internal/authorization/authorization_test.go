@@ -5,11 +5,13 @@ import (
 	"graduation/internal/authorization"
 	"graduation/internal/config"
 	"graduation/internal/logger"
+	"graduation/internal/storage/mock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,7 +24,13 @@ func TestAuthorizationMiddleware(t *testing.T) {
 
 	requestBody := `{"login":"test","password":"123"}`
 
-	token, err := authorization.BuildJWTString("secretKey", time.Hour*3, 1)
+	token, err := authorization.BuildJWTString("secretKey", time.Hour*3, 1, "attendee", 1)
+	assert.NoError(t, err)
+
+	revoked, err := authorization.BuildJWTString("secretKey", time.Hour*3, 1, "attendee", 2)
+	assert.NoError(t, err)
+
+	withoutSession, err := authorization.BuildJWTString("secretKey", time.Hour*3, 1, "attendee", 0)
 	assert.NoError(t, err)
 
 	tests := []struct {
@@ -58,6 +66,24 @@ got status 401
 			cookie:             http.Cookie{},
 			expectedStatusCode: 401,
 		},
+		{
+			name: `
+AuthorizationMiddleware #4 
+revoked session
+got status 401
+			`,
+			cookie:             http.Cookie{Name: "Authorization", Value: revoked},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+AuthorizationMiddleware #5 
+token without session
+got status 401
+			`,
+			cookie:             http.Cookie{Name: "Authorization", Value: withoutSession},
+			expectedStatusCode: 401,
+		},
 	}
 
 	for _, test := range tests {
@@ -70,7 +96,14 @@ got status 401
 
 			request.AddCookie(&test.cookie)
 
-			handler := authorization.AuthorizationMiddleware("secretKey")(mockHandler)
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessions := mock.NewMockStorage(c)
+			sessions.EXPECT().SessionActive(gomock.Any(), 1).Return(true, nil).AnyTimes()
+			sessions.EXPECT().SessionActive(gomock.Any(), 2).Return(false, nil).AnyTimes()
+
+			handler := authorization.AuthorizationMiddleware("secretKey", sessions)(mockHandler)
 
 			handler.ServeHTTP(recorder, request)
 
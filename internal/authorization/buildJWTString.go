@@ -7,12 +7,14 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func BuildJWTString(secretKey string, tokenEXP time.Duration, id int) (string, error) {
+func BuildJWTString(secretKey string, tokenEXP time.Duration, id int, role string, sessionID int) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenEXP)),
 		},
-		UserID: id,
+		UserID:    id,
+		Role:      role,
+		SessionID: sessionID,
 	})
 
 	tokenString, err := token.SignedString([]byte(secretKey))
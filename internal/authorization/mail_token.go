@@ -0,0 +1,63 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// mailAudience keeps verification links and access tokens apart even
+// though both are signed with the same key.
+const mailAudience = "mail-verify"
+
+type MailClaims struct {
+	jwt.RegisteredClaims
+	UserID int
+	Mail   string
+}
+
+// BuildMailToken signs the address the user registered with, the link
+// stops working once the user changes the address or the token expires.
+func BuildMailToken(secretKey string, tokenEXP time.Duration, id int, mail string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, MailClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenEXP)),
+			Audience:  jwt.ClaimStrings{mailAudience},
+		},
+		UserID: id,
+		Mail:   mail,
+	})
+
+	tokenString, err := token.SignedString([]byte(secretKey))
+	if err != nil {
+		return "", fmt.Errorf("cannot get token: %v", err)
+	}
+
+	return tokenString, nil
+}
+
+func ParseMailToken(secretKey, tokenString string) (*MailClaims, error) {
+	claims := &MailClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+			return []byte(secretKey), nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("cannot pars: %v", err)
+	}
+
+	if !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+
+	if !claims.VerifyAudience(mailAudience, true) {
+		return nil, errors.New("token is not a mail token")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,33 @@
+package authorization_test
+
+import (
+	"graduation/internal/authorization"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailToken(t *testing.T) {
+	token, err := authorization.BuildMailToken("secretKey", time.Hour, 7, "user@mail.ru")
+	require.NoError(t, err)
+
+	claims, err := authorization.ParseMailToken("secretKey", token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID)
+	assert.Equal(t, "user@mail.ru", claims.Mail)
+
+	_, err = authorization.ParseMailToken("otherKey", token)
+	assert.Error(t, err)
+
+	expired, err := authorization.BuildMailToken("secretKey", -time.Minute, 7, "user@mail.ru")
+	require.NoError(t, err)
+	_, err = authorization.ParseMailToken("secretKey", expired)
+	assert.Error(t, err)
+
+	access, err := authorization.BuildJWTString("secretKey", time.Hour, 7, "attendee", 1)
+	require.NoError(t, err)
+	_, err = authorization.ParseMailToken("secretKey", access)
+	assert.Error(t, err)
+}
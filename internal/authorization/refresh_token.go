@@ -0,0 +1,36 @@
+package authorization
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// BuildRefreshToken returns an opaque refresh token for the client and the
+// hash that is stored on the server side instead of the token itself.
+func BuildRefreshToken() (string, string, error) {
+	return BuildOpaqueToken()
+}
+
+// BuildOpaqueToken returns a random URL-safe token and its hash.
+func BuildOpaqueToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("cannot read random: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashOpaqueToken(token), nil
+}
+
+func HashRefreshToken(token string) string {
+	return HashOpaqueToken(token)
+}
+
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,29 @@
+package authorization
+
+import (
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+)
+
+// RoleMiddleware must run after AuthorizationMiddleware, which puts the role
+// from the token claims into the User_role header.
+func RoleMiddleware(roles ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role := r.Header.Get("User_role")
+			if !allowed[role] {
+				logger.Error("role %q is not allowed for %s", role, r.URL.Path)
+				problem.Write(w, http.StatusForbidden, "role "+role+" is not allowed")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package authorization_test
+
+import (
+	"graduation/internal/authorization"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleMiddleware(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	sessions := mock.NewMockStorage(c)
+	sessions.EXPECT().SessionActive(gomock.Any(), 1).Return(true, nil).AnyTimes()
+
+	tests := []struct {
+		name               string
+		role               string
+		expectedStatusCode int
+	}{
+		{
+			name: `
+RoleMiddleware #1 
+organizer role
+got status 200
+			`,
+			role:               entity.RoleOrganizer,
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+RoleMiddleware #2 
+admin role
+got status 200
+			`,
+			role:               entity.RoleAdmin,
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+RoleMiddleware #3 
+attendee role
+got status 403
+			`,
+			role:               entity.RoleAttendee,
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token, err := authorization.BuildJWTString("secretKey", time.Hour*3, 1, test.role, 1)
+			assert.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			request.AddCookie(&http.Cookie{Name: "Authorization", Value: token})
+
+			recorder := httptest.NewRecorder()
+
+			handler := authorization.AuthorizationMiddleware("secretKey", sessions)(
+				authorization.RoleMiddleware(entity.RoleOrganizer, entity.RoleAdmin)(mockHandler))
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+		})
+	}
+
+	t.Run("header spoofing", func(t *testing.T) {
+		token, err := authorization.BuildJWTString("secretKey", time.Hour*3, 1, entity.RoleAttendee, 1)
+		assert.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.AddCookie(&http.Cookie{Name: "Authorization", Value: token})
+		request.Header.Set("User_role", entity.RoleAdmin)
+
+		recorder := httptest.NewRecorder()
+
+		handler := authorization.AuthorizationMiddleware("secretKey", sessions)(
+			authorization.RoleMiddleware(entity.RoleAdmin)(mockHandler))
+
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
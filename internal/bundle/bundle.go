@@ -0,0 +1,117 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/ticket"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Format is bumped whenever the bundle layout changes in a way old scanners
+// cannot read.
+const Format = 1
+
+// Bundle is everything a door scanner needs to check tickets of one event
+// without network. Tickets are listed by ticket.ID, never by token.
+type Bundle struct {
+	Format   int         `json:"format"`
+	Version  int64       `json:"version"`
+	EventID  int         `json:"event_id"`
+	Tickets  []string    `json:"tickets"`
+	Redeemed []string    `json:"redeemed"`
+	Revoked  []string    `json:"revoked"`
+	Keys     ticket.JWKS `json:"keys"`
+}
+
+type Claims struct {
+	Bundle
+	jwt.RegisteredClaims
+}
+
+func New(eventID int, tickets []entity.Ticket, revoked []string, keys ticket.JWKS, now time.Time) *Bundle {
+	bundle := Bundle{
+		Format:   Format,
+		Version:  now.UnixMilli(),
+		EventID:  eventID,
+		Tickets:  []string{},
+		Redeemed: []string{},
+		Revoked:  []string{},
+		Keys:     keys,
+	}
+
+	for _, tick := range tickets {
+		switch {
+		case !tick.Status:
+			bundle.Revoked = append(bundle.Revoked, ticket.ID(tick.Token))
+		case !tick.RedeemedAt.IsZero():
+			bundle.Redeemed = append(bundle.Redeemed, ticket.ID(tick.Token))
+		default:
+			bundle.Tickets = append(bundle.Tickets, ticket.ID(tick.Token))
+		}
+	}
+	for _, token := range revoked {
+		bundle.Revoked = append(bundle.Revoked, ticket.ID(token))
+	}
+
+	sort.Strings(bundle.Tickets)
+	sort.Strings(bundle.Redeemed)
+	sort.Strings(bundle.Revoked)
+
+	return &bundle
+}
+
+func (b *Bundle) Sign(tick *ticket.TicketToken) (string, error) {
+	return tick.Sign(Claims{
+		Bundle: *b,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(time.UnixMilli(b.Version)),
+		},
+	})
+}
+
+// ErrNoKeys is returned by Parse without pinned keys: the keys carried by a
+// bundle can not vouch for the bundle itself.
+var ErrNoKeys = errors.New("no pinned keys")
+
+// Parse verifies a signed bundle against pinned keys, saved from
+// /.well-known/ticket-keys while online. The keys carried by the bundle are
+// replaced with the pinned ones, so tickets are checked with them too.
+func Parse(data string, pinned ticket.JWKS) (*Bundle, error) {
+	if len(pinned.Keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	token, err := jwt.ParseWithClaims(data, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, jwk := range pinned.Keys {
+			if jwk.Kid != kid {
+				continue
+			}
+			if jwk.Alg != token.Method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+			}
+			return jwk.PublicKey()
+		}
+
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify bundle: %w", err)
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid bundle")
+	}
+
+	if claims.Format != Format {
+		return nil, fmt.Errorf("unsupported bundle format: %d", claims.Format)
+	}
+	claims.Keys = pinned
+
+	return &claims.Bundle, nil
+}
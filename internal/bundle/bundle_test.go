@@ -0,0 +1,127 @@
+package bundle_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"graduation/internal/bundle"
+	"graduation/internal/entity"
+	"graduation/internal/ticket"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTicketToken(t *testing.T, kid string) *ticket.TicketToken {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyring, err := ticket.NewKeyring(&ticket.Key{ID: kid, Method: jwt.SigningMethodEdDSA, Private: private, Public: public})
+	require.NoError(t, err)
+
+	return ticket.NewWithKeyring("", keyring)
+}
+
+func issue(t *testing.T, tick *ticket.TicketToken, userID, eventID int) entity.Ticket {
+	issued := entity.Ticket{UserID: userID, EventID: eventID, Exp: 1, Status: true}
+	require.NoError(t, tick.Generate(&issued))
+	return issued
+}
+
+func TestBundle(t *testing.T) {
+	tick := newTicketToken(t, "k1")
+
+	valid := issue(t, tick, 1, 7)
+	redeemed := issue(t, tick, 2, 7)
+	redeemed.RedeemedAt = time.Now()
+	cancelled := issue(t, tick, 3, 7)
+	other := issue(t, tick, 4, 8)
+	missing := issue(t, tick, 5, 7)
+
+	signed, err := bundle.New(7, []entity.Ticket{valid, redeemed}, []string{cancelled.Token}, tick.JWKS(), time.Now()).Sign(tick)
+	require.NoError(t, err)
+
+	t.Run("parse", func(t *testing.T) {
+		b, err := bundle.Parse(signed, tick.JWKS())
+		require.NoError(t, err)
+		assert.Equal(t, 7, b.EventID)
+		assert.Equal(t, bundle.Format, b.Format)
+		assert.Equal(t, []string{ticket.ID(valid.Token)}, b.Tickets)
+		assert.Equal(t, []string{ticket.ID(redeemed.Token)}, b.Redeemed)
+		assert.Equal(t, []string{ticket.ID(cancelled.Token)}, b.Revoked)
+	})
+
+	t.Run("without pinned keys", func(t *testing.T) {
+		_, err := bundle.Parse(signed, ticket.JWKS{})
+		assert.ErrorIs(t, err, bundle.ErrNoKeys)
+	})
+
+	t.Run("pinned keys mismatch", func(t *testing.T) {
+		_, err := bundle.Parse(signed, newTicketToken(t, "k1").JWKS())
+		assert.Error(t, err)
+	})
+
+	t.Run("forged with own keys", func(t *testing.T) {
+		forger := newTicketToken(t, "k1")
+		forged, err := bundle.New(7, []entity.Ticket{issue(t, forger, 9, 7)}, nil, forger.JWKS(), time.Now()).Sign(forger)
+		require.NoError(t, err)
+
+		_, err = bundle.Parse(forged, tick.JWKS())
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		_, err := bundle.Parse(signed[:len(signed)-4]+"AAAA", tick.JWKS())
+		assert.Error(t, err)
+	})
+
+	t.Run("sign without keyring", func(t *testing.T) {
+		_, err := bundle.New(7, nil, nil, ticket.JWKS{}, time.Now()).Sign(ticket.NewWithKeyring("123", nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("scanner", func(t *testing.T) {
+		b, err := bundle.Parse(signed, tick.JWKS())
+		require.NoError(t, err)
+
+		var log bytes.Buffer
+		scanner, err := bundle.NewScanner(b, &log)
+		require.NoError(t, err)
+
+		checked, err := scanner.Check(valid.Token, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, checked.UserID)
+
+		_, err = scanner.Check(valid.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrRedeemed)
+
+		_, err = scanner.Check(redeemed.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrRedeemed)
+
+		_, err = scanner.Check(cancelled.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrRevoked)
+
+		_, err = scanner.Check(other.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrWrongEvent)
+
+		_, err = scanner.Check(missing.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrUnknown)
+
+		_, err = scanner.Check("bad_token", time.Now())
+		assert.ErrorIs(t, err, bundle.ErrInvalid)
+
+		foreign := issue(t, newTicketToken(t, "k1"), 1, 7)
+		_, err = scanner.Check(foreign.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrInvalid)
+
+		restarted, err := bundle.NewScanner(b, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.NoError(t, restarted.Replay(bytes.NewReader(log.Bytes())))
+
+		_, err = restarted.Check(valid.Token, time.Now())
+		assert.ErrorIs(t, err, bundle.ErrRedeemed)
+	})
+}
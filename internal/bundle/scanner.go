@@ -0,0 +1,118 @@
+package bundle
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/ticket"
+	"io"
+	"time"
+)
+
+var (
+	ErrInvalid    = errors.New("ticket signature or claims invalid")
+	ErrWrongEvent = errors.New("ticket for another event")
+	ErrUnknown    = errors.New("ticket not in bundle")
+	ErrRevoked    = errors.New("ticket revoked")
+	ErrRedeemed   = errors.New("ticket already redeemed")
+)
+
+type state int
+
+const (
+	stateValid state = iota
+	stateRedeemed
+	stateRevoked
+)
+
+// Scanner checks tickets against a bundle and appends every accepted scan to
+// a log, one entity.Checkin per line.
+type Scanner struct {
+	bundle   *Bundle
+	verifier *ticket.TicketToken
+	tickets  map[string]state
+	log      io.Writer
+}
+
+func NewScanner(bundle *Bundle, log io.Writer) (*Scanner, error) {
+	verifier, err := ticket.NewVerifier(bundle.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build verifier: %w", err)
+	}
+
+	tickets := make(map[string]state, len(bundle.Tickets)+len(bundle.Redeemed)+len(bundle.Revoked))
+	for _, id := range bundle.Tickets {
+		tickets[id] = stateValid
+	}
+	for _, id := range bundle.Redeemed {
+		tickets[id] = stateRedeemed
+	}
+	for _, id := range bundle.Revoked {
+		tickets[id] = stateRevoked
+	}
+
+	return &Scanner{bundle: bundle, verifier: verifier, tickets: tickets, log: log}, nil
+}
+
+// Replay marks tickets from an earlier log as redeemed, so a restarted
+// scanner does not let the same ticket in twice.
+func (s *Scanner) Replay(log io.Reader) error {
+	scanner := bufio.NewScanner(log)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var checkin entity.Checkin
+		if err := json.Unmarshal(scanner.Bytes(), &checkin); err != nil {
+			return fmt.Errorf("cannot parse log line: %w", err)
+		}
+
+		id := ticket.ID(checkin.Token)
+		if s.tickets[id] == stateValid {
+			s.tickets[id] = stateRedeemed
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (s *Scanner) Check(token string, now time.Time) (*entity.Ticket, error) {
+	tick := entity.Ticket{Token: token}
+	if err := s.verifier.Validate(&tick); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	if tick.EventID != s.bundle.EventID {
+		return nil, ErrWrongEvent
+	}
+
+	id := ticket.ID(token)
+	current, ok := s.tickets[id]
+	if !ok {
+		return nil, ErrUnknown
+	}
+
+	switch current {
+	case stateRevoked:
+		return nil, ErrRevoked
+	case stateRedeemed:
+		return nil, ErrRedeemed
+	}
+
+	line, err := json.Marshal(entity.Checkin{Token: token, ScannedAt: now.UTC()})
+	if err != nil {
+		return nil, fmt.Errorf("cannot json to byte: %w", err)
+	}
+	if _, err := s.log.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("cannot write log: %w", err)
+	}
+
+	s.tickets[id] = stateRedeemed
+	tick.RedeemedAt = now
+
+	return &tick, nil
+}
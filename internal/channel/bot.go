@@ -0,0 +1,73 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Bot sends messages as text through a Telegram-style bot API: POST
+// <url>/bot<token>/sendMessage with the chat id the user gave.
+type Bot struct {
+	client *http.Client
+	url    string
+}
+
+func NewBot(apiURL, token string) *Bot {
+	return &Bot{client: newClient(), url: strings.TrimSuffix(apiURL, "/") + "/bot" + token + "/sendMessage"}
+}
+
+type botMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type botResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (b *Bot) Send(ctx context.Context, to string, msg Message) error {
+	body, err := json.Marshal(botMessage{ChatID: to, Text: msg.Subject + "\n\n" + msg.Text})
+	if err != nil {
+		return fmt.Errorf("cannot json to byte: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		// The error holds the URL and with it the token.
+		return fmt.Errorf("cannot reach bot API: %w", errorWithoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	var answer botResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&answer); err != nil {
+		return fmt.Errorf("bot API answered %d: %w", resp.StatusCode, err)
+	}
+
+	if !answer.OK {
+		return fmt.Errorf("bot API answered %d: %s", resp.StatusCode, answer.Description)
+	}
+
+	return nil
+}
+
+func errorWithoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
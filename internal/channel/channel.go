@@ -0,0 +1,34 @@
+package channel
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// requestTimeout bounds one HTTP delivery, a slow receiver is retried later.
+const requestTimeout = 10 * time.Second
+
+// Message is a notification that does not depend on how it is delivered.
+// Key is unique per notification, receivers may use it to drop repeats.
+// HTML and Text carry the same message, QR is the ticket code as PNG and
+// Calendar an iCalendar file, channels that cannot carry them skip them.
+type Message struct {
+	Key      string
+	EventID  int
+	Subject  string
+	HTML     string
+	Text     string
+	Token    string
+	QR       []byte
+	Calendar []byte
+}
+
+// Channel delivers messages to an address whose form depends on the channel.
+type Channel interface {
+	Send(ctx context.Context, to string, msg Message) error
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
@@ -0,0 +1,182 @@
+package channel_test
+
+import (
+	"context"
+	"encoding/json"
+	"graduation/internal/channel"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var message = channel.Message{
+	Key:     "reminder:1:2:180",
+	EventID: 1,
+	Subject: "Event in 3 hours",
+	HTML:    "<h1>Concert</h1>",
+	Text:    "Concert",
+	Token:   "token",
+}
+
+func TestWebhookSend(t *testing.T) {
+	var got channel.WebhookPayload
+	var signature, key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &got))
+
+		signature = r.Header.Get(channel.SignatureHeader)
+		key = r.Header.Get("Idempotency-Key")
+		assert.Equal(t, channel.Sign([]byte("secret"), body), signature)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := channel.NewLocalWebhook("secret", nil).Send(context.Background(), server.URL, message)
+	require.NoError(t, err)
+
+	assert.Equal(t, channel.WebhookPayload{
+		Key:     "reminder:1:2:180",
+		EventID: "MQ==",
+		Subject: "Event in 3 hours",
+		Text:    "Concert",
+		HTML:    "<h1>Concert</h1>",
+		Ticket:  "token",
+	}, got)
+	assert.Contains(t, signature, "sha256=")
+	assert.Equal(t, "reminder:1:2:180", key)
+}
+
+func TestWebhookSendUnsigned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(channel.SignatureHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, channel.NewLocalWebhook("", nil).Send(context.Background(), server.URL, message))
+}
+
+func TestWebhookSendFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := channel.NewLocalWebhook("secret", nil).Send(context.Background(), server.URL, message)
+	assert.ErrorContains(t, err, "502")
+}
+
+func TestWebhookSendBlocked(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// the test server listens on loopback
+	err := channel.NewWebhook("secret", nil).Send(context.Background(), server.URL, message)
+	assert.ErrorIs(t, err, channel.ErrBlockedAddress)
+
+	// a name is refused by the address it resolves to
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	err = channel.NewWebhook("secret", nil).Send(context.Background(), "http://localhost:"+u.Port(), message)
+	assert.ErrorIs(t, err, channel.ErrBlockedAddress)
+
+	err = channel.NewLocalWebhook("secret", []string{"example.com"}).Send(context.Background(), server.URL, message)
+	assert.ErrorContains(t, err, "not allowed")
+	assert.False(t, called)
+}
+
+func TestWebhookSendRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	err := channel.NewLocalWebhook("secret", nil).Send(context.Background(), server.URL, message)
+	assert.ErrorContains(t, err, "302")
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		allow   []string
+		blocked bool
+		wantErr bool
+	}{
+		{url: "https://example.com/hook"},
+		{url: "http://93.184.216.34/hook"},
+		{url: "ftp://example.com", wantErr: true},
+		{url: "https://", wantErr: true},
+		{url: "http://127.0.0.1:8080/", blocked: true},
+		{url: "http://[::1]/", blocked: true},
+		{url: "http://10.0.0.1/", blocked: true},
+		{url: "http://192.168.1.1/", blocked: true},
+		{url: "http://169.254.169.254/latest/meta-data", blocked: true},
+		{url: "http://100.64.0.1/", blocked: true},
+		{url: "http://0.0.0.0/", blocked: true},
+		{url: "http://[::ffff:127.0.0.1]/", blocked: true},
+		{url: "http://[fd00:ec2::254]/", blocked: true},
+		{url: "https://hooks.example.com/", allow: []string{"example.com"}},
+		{url: "https://EXAMPLE.com./", allow: []string{"example.com"}},
+		{url: "https://notexample.com/", allow: []string{"example.com"}, wantErr: true},
+		{url: "https://evil.com/", allow: []string{"example.com"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			err := channel.CheckWebhookURL(test.url, test.allow)
+			switch {
+			case test.blocked:
+				assert.ErrorIs(t, err, channel.ErrBlockedAddress)
+			case test.wantErr:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBotSend(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bot123:abc/sendMessage", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	err := channel.NewBot(server.URL+"/", "123:abc").Send(context.Background(), "42", message)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"chat_id": "42", "text": "Event in 3 hours\n\nConcert"}, got)
+}
+
+func TestBotSendFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+	}))
+	defer server.Close()
+
+	err := channel.NewBot(server.URL, "123:abc").Send(context.Background(), "42", message)
+	assert.ErrorContains(t, err, "chat not found")
+}
+
+func TestBotSendHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	err := channel.NewBot(server.URL, "123:abc").Send(context.Background(), "42", message)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "123:abc")
+}
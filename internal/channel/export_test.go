@@ -0,0 +1,10 @@
+package channel
+
+import "net/http"
+
+// NewLocalWebhook is a webhook that may post to test servers on loopback.
+func NewLocalWebhook(secret string, allow []string) *Webhook {
+	wh := NewWebhook(secret, allow)
+	wh.client.Transport = &http.Transport{}
+	return wh
+}
@@ -0,0 +1,20 @@
+package channel
+
+import (
+	"context"
+	"graduation/internal/mail"
+)
+
+// SMTP sends messages as emails with HTML and text bodies, the QR code
+// inline and the calendar attached.
+type SMTP struct {
+	mail *mail.Mail
+}
+
+func NewSMTP(m *mail.Mail) *SMTP {
+	return &SMTP{mail: m}
+}
+
+func (s *SMTP) Send(_ context.Context, to string, msg Message) error {
+	return s.mail.SendMessage(to, msg.Subject, msg.HTML, msg.Text, msg.QR, msg.Calendar)
+}
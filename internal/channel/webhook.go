@@ -0,0 +1,202 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/encoding"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader carries "sha256=<hex>", the HMAC-SHA256 of the request
+// body with the webhook secret.
+const SignatureHeader = "X-Signature"
+
+// WebhookPayload is the JSON body posted to a webhook.
+type WebhookPayload struct {
+	Key     string `json:"key"`
+	EventID string `json:"event_id,omitempty"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	Ticket  string `json:"ticket,omitempty"`
+}
+
+// ErrBlockedAddress is returned for webhooks on loopback, private,
+// link-local and other addresses that are not public.
+var ErrBlockedAddress = errors.New("webhook address is not public")
+
+// blockedNets are the ranges publicIP refuses besides those net.IP knows.
+var blockedNets = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),
+	mustCIDR("100.64.0.0/10"),
+	mustCIDR("192.0.0.0/24"),
+	mustCIDR("198.18.0.0/15"),
+	mustCIDR("240.0.0.0/4"),
+	mustCIDR("64:ff9b::/96"),
+}
+
+func mustCIDR(cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+// publicIP reports whether ip is an internet address a webhook may reach.
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, ipNet := range blockedNets {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialPublic refuses connections to addresses that are not public. It runs
+// on the resolved address, so a name that resolves to an internal address
+// is refused whatever it resolved to before.
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("cannot split address: %w", err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// newWebhookClient dials public addresses only. It ignores proxies, which
+// would dial for it, and does not follow redirects.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout, Control: dialPublic}
+	return &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: requestTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// hostAllowed reports whether host is on allow, as a name or a subdomain
+// of one. An empty allow lets every host through.
+func hostAllowed(host string, allow []string) bool {
+	if len(allow) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range allow {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckWebhookURL tells whether a webhook on rawURL can be delivered: an
+// http or https URL whose host is on allow and is not an address that is
+// not public. Names are checked again when the webhook is sent.
+func CheckWebhookURL(rawURL string, allow []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("not correct webhook url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook url must be http or https: %s", rawURL)
+	}
+	if !hostAllowed(u.Hostname(), allow) {
+		return fmt.Errorf("webhook host %s is not allowed", u.Hostname())
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, u.Hostname())
+	}
+	return nil
+}
+
+// Webhook posts messages as JSON to the URL the user gave. Only public
+// addresses are dialed, and only hosts on allow when it is set.
+type Webhook struct {
+	client *http.Client
+	secret []byte
+	allow  []string
+}
+
+// NewWebhook makes a webhook channel, requests are signed when secret is set.
+func NewWebhook(secret string, allow []string) *Webhook {
+	return &Webhook{client: newWebhookClient(), secret: []byte(secret), allow: allow}
+}
+
+// Sign is the SignatureHeader value for body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (wh *Webhook) Send(ctx context.Context, to string, msg Message) error {
+	payload := WebhookPayload{
+		Key:     msg.Key,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+		Ticket:  msg.Token,
+	}
+	if msg.EventID != 0 {
+		payload.EventID = encoding.EncodeID(msg.EventID)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot json to byte: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, to, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	if !hostAllowed(req.URL.Hostname(), wh.allow) {
+		return fmt.Errorf("webhook host %s is not allowed", req.URL.Hostname())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", msg.Key)
+	if len(wh.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(wh.secret, body))
+	}
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %d", resp.StatusCode)
+	}
+
+	return nil
+}
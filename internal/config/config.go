@@ -13,6 +13,10 @@ func NewFlags() Flags {
 			Port: 8080,
 		},
 
+		URLBase: URLBase{
+			BaseURL: "http://localhost:8080",
+		},
+
 		Logger: Logger{
 			LoggerFilePath:  "file.log",
 			LoggerFileFlag:  false,
@@ -20,7 +24,12 @@ func NewFlags() Flags {
 		},
 
 		Storage: Storage{
-			DatabaseDSN: "host=localhost user=url password=1234 dbname=url sslmode=disable",
+			DatabaseDSN:     "host=localhost user=url password=1234 dbname=url sslmode=disable",
+			DatabaseBackend: "postgres",
+			ObjectStorage: ObjectStorage{
+				StorageBackend: "minio",
+				StoragePath:    "images",
+			},
 		},
 
 		Token: Token{
@@ -29,10 +38,57 @@ func NewFlags() Flags {
 				Time:     3,
 				TokenEXP: time.Hour * 3,
 			},
+			Refresh: TokenTime{
+				Time:     720,
+				TokenEXP: time.Hour * 720,
+			},
 		},
 
 		TicketKey: TicketKey{
-			TicketSecretKey: "",
+			TicketSecretKey:  "",
+			TicketKeysPath:   "",
+			TicketSigningKey: "",
+		},
+
+		PasswordHash: PasswordHash{
+			HashAlgorithm: "argon2id",
+		},
+
+		QRCode: QRCode{
+			QRSize:  256,
+			QRLevel: "M",
+		},
+
+		Shutdown: Shutdown{
+			ShutdownTimeout: 30 * time.Second,
+		},
+
+		SMTP: SMTP{
+			FromName: "EVENT.NE",
+		},
+
+		Events: Events{
+			EventTimezone: "Europe/Moscow",
+		},
+
+		Templates: Templates{
+			TemplatesDir: "",
+		},
+
+		Images: Images{
+			ImageMaxSize:    10 << 20,
+			ImageMaxPixels:  40_000_000,
+			ImageGCInterval: time.Hour,
+			ImageServeMode:  "redirect",
+			ImagePresignTTL: time.Hour,
+		},
+
+		Channels: Channels{
+			WebhookEnabled:    false,
+			WebhookSecret:     "",
+			WebhookAllowHosts: "",
+			BotAPIURL:         "https://api.telegram.org",
+			BotToken:          "",
 		},
 	}
 }
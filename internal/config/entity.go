@@ -8,6 +8,8 @@ import (
 	"time"
 )
 
+// URLBase is the public address of the service, links in emails start
+// with it.
 type URLBase struct {
 	BaseURL string
 }
@@ -24,10 +26,13 @@ type ObjectStorage struct {
 	StorageEndpoint   string `json:"storageEndpoint"`
 	StorageBucketName string `json:"storageBucketName"`
 	StorageUseSSL     bool   `json:"storageUseSSL"`
+	StorageBackend    string `json:"storageBackend"`
+	StoragePath       string `json:"storagePath"`
 }
 
 type Storage struct {
-	DatabaseDSN string
+	DatabaseDSN     string
+	DatabaseBackend string
 	ObjectStorage
 }
 
@@ -43,11 +48,84 @@ type TokenTime struct {
 
 type Token struct {
 	TokenTime
+	Refresh        TokenTime
 	TokenSecretKey string
 }
 
 type TicketKey struct {
-	TicketSecretKey string
+	TicketSecretKey  string
+	TicketKeysPath   string
+	TicketSigningKey string
+}
+
+type PasswordHash struct {
+	HashAlgorithm string
+}
+
+type QRCode struct {
+	QRSize  int
+	QRLevel string
+}
+
+type Shutdown struct {
+	ShutdownTimeout time.Duration
+}
+
+// Channels configures notification channels besides email. The webhook
+// channel is off unless WebhookEnabled, the bot channel without BotToken.
+// WebhookAllowHosts is a comma-separated list of hosts webhooks may go to,
+// with their subdomains; empty lets any public host.
+type Channels struct {
+	WebhookEnabled    bool
+	WebhookSecret     string
+	WebhookAllowHosts string
+	BotAPIURL         string
+	BotToken          string
+}
+
+// WebhookHosts is WebhookAllowHosts as a list of lower-case hosts.
+func (c Channels) WebhookHosts() []string {
+	var hosts []string
+	for _, host := range strings.Split(c.WebhookAllowHosts, ",") {
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// Events says where events happen. Event dates are kept as wall-clock time
+// without a zone, EventTimezone is the IANA zone they are in.
+type Events struct {
+	EventTimezone string
+}
+
+// EventLocation loads EventTimezone.
+func (e Events) EventLocation() (*time.Location, error) {
+	loc, err := time.LoadLocation(e.EventTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load event timezone %q: %w", e.EventTimezone, err)
+	}
+	return loc, nil
+}
+
+// Templates points at a directory whose <locale>/<name>.{subject,html,txt}
+// files replace the built-in notification templates.
+type Templates struct {
+	TemplatesDir string
+}
+
+// Images limits uploaded event images, ImageMaxSize is in bytes.
+// ImageGCInterval is how often orphaned objects are removed from the
+// bucket, zero turns the collector off. ImageServeMode is "redirect" to a
+// presigned URL living ImagePresignTTL or "stream" through the service.
+type Images struct {
+	ImageMaxSize    int64
+	ImageMaxPixels  int
+	ImageGCInterval time.Duration
+	ImageServeMode  string
+	ImagePresignTTL time.Duration
 }
 
 type SMTP struct {
@@ -55,16 +133,25 @@ type SMTP struct {
 	SMTPUsername string `json:"smtpUsername"`
 	SMTPPassword string `json:"smtpPassword"`
 	From         string `json:"from"`
+	FromName     string `json:"fromName"`
 	SMTPPort     int    `json:"smtpPort"`
 }
 
 type Flags struct {
 	NetAddress
+	URLBase
 	Logger
 	Storage
 	Token
 	SMTP
 	TicketKey
+	PasswordHash
+	QRCode
+	Shutdown
+	Channels
+	Events
+	Templates
+	Images
 }
 
 func (a NetAddress) String() string {
@@ -51,6 +51,11 @@ func parseFile(flags *Flags) error {
 		return fmt.Errorf("cannot pars SMTP: %w", err)
 	}
 
+	// only MinIO needs the credentials of the object storage
+	if flags.StorageBackend != "minio" {
+		return nil
+	}
+
 	if err := parsObjectStorage(flags); err != nil {
 		return fmt.Errorf("cannot pars ObjectStorage: %w", err)
 	}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 func parseENV(flags *Flags) {
@@ -11,6 +13,9 @@ func parseENV(flags *Flags) {
 	if time := os.Getenv("TOKEN_EXP"); time != "" {
 		flags.TokenTime.Set(time)
 	}
+	if time := os.Getenv("REFRESH_EXP"); time != "" {
+		flags.Refresh.Set(time)
+	}
 	if key := os.Getenv("SECRET_KEY"); key != "" {
 		flags.TokenSecretKey = key
 	}
@@ -23,4 +28,84 @@ func parseENV(flags *Flags) {
 	if tiketSecretKey := os.Getenv("SECRET_KEY_TICKET"); tiketSecretKey != "" {
 		flags.TicketSecretKey = tiketSecretKey
 	}
+	if keysPath := os.Getenv("TICKET_KEYS_PATH"); keysPath != "" {
+		flags.TicketKeysPath = keysPath
+	}
+	if signingKey := os.Getenv("TICKET_SIGNING_KEY"); signingKey != "" {
+		flags.TicketSigningKey = signingKey
+	}
+	if hashAlgorithm := os.Getenv("PASSWORD_HASH"); hashAlgorithm != "" {
+		flags.HashAlgorithm = hashAlgorithm
+	}
+	if qrSize := os.Getenv("QR_SIZE"); qrSize != "" {
+		if size, err := strconv.Atoi(qrSize); err == nil {
+			flags.QRSize = size
+		}
+	}
+	if qrLevel := os.Getenv("QR_LEVEL"); qrLevel != "" {
+		flags.QRLevel = qrLevel
+	}
+	if shutdownTimeout := os.Getenv("SHUTDOWN_TIMEOUT"); shutdownTimeout != "" {
+		if timeout, err := time.ParseDuration(shutdownTimeout); err == nil {
+			flags.ShutdownTimeout = timeout
+		}
+	}
+	if webhookEnabled := os.Getenv("WEBHOOK_ENABLED"); webhookEnabled != "" {
+		if enabled, err := strconv.ParseBool(webhookEnabled); err == nil {
+			flags.WebhookEnabled = enabled
+		}
+	}
+	if webhookSecret := os.Getenv("WEBHOOK_SECRET"); webhookSecret != "" {
+		flags.WebhookSecret = webhookSecret
+	}
+	if webhookAllowHosts := os.Getenv("WEBHOOK_ALLOW_HOSTS"); webhookAllowHosts != "" {
+		flags.WebhookAllowHosts = webhookAllowHosts
+	}
+	if botAPIURL := os.Getenv("BOT_API_URL"); botAPIURL != "" {
+		flags.BotAPIURL = botAPIURL
+	}
+	if botToken := os.Getenv("BOT_TOKEN"); botToken != "" {
+		flags.BotToken = botToken
+	}
+	if templatesDir := os.Getenv("TEMPLATES_DIR"); templatesDir != "" {
+		flags.TemplatesDir = templatesDir
+	}
+	if baseURL := os.Getenv("BASE_URL"); baseURL != "" {
+		flags.BaseURL = baseURL
+	}
+	if imageMaxSize := os.Getenv("IMAGE_MAX_SIZE"); imageMaxSize != "" {
+		if size, err := strconv.ParseInt(imageMaxSize, 10, 64); err == nil {
+			flags.ImageMaxSize = size
+		}
+	}
+	if imageMaxPixels := os.Getenv("IMAGE_MAX_PIXELS"); imageMaxPixels != "" {
+		if pixels, err := strconv.Atoi(imageMaxPixels); err == nil {
+			flags.ImageMaxPixels = pixels
+		}
+	}
+	if imageGCInterval := os.Getenv("IMAGE_GC_INTERVAL"); imageGCInterval != "" {
+		if interval, err := time.ParseDuration(imageGCInterval); err == nil {
+			flags.ImageGCInterval = interval
+		}
+	}
+	if imageServeMode := os.Getenv("IMAGE_SERVE_MODE"); imageServeMode != "" {
+		flags.ImageServeMode = imageServeMode
+	}
+	if imagePresignTTL := os.Getenv("IMAGE_PRESIGN_TTL"); imagePresignTTL != "" {
+		if ttl, err := time.ParseDuration(imagePresignTTL); err == nil {
+			flags.ImagePresignTTL = ttl
+		}
+	}
+	if blobStore := os.Getenv("BLOB_STORE"); blobStore != "" {
+		flags.StorageBackend = blobStore
+	}
+	if blobPath := os.Getenv("BLOB_PATH"); blobPath != "" {
+		flags.StoragePath = blobPath
+	}
+	if databaseBackend := os.Getenv("DATABASE_BACKEND"); databaseBackend != "" {
+		flags.DatabaseBackend = databaseBackend
+	}
+	if eventTimezone := os.Getenv("EVENT_TIMEZONE"); eventTimezone != "" {
+		flags.EventTimezone = eventTimezone
+	}
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"flag"
+	"time"
 )
 
 func parseFlags() *Flags {
@@ -11,12 +12,58 @@ func parseFlags() *Flags {
 
 	flag.Var(&flags.TokenTime, "t", "user token lifetimer")
 
+	flag.Var(&flags.Refresh, "r", "refresh token lifetime in hours")
+
 	flag.StringVar(&flags.TokenSecretKey, "k", "supersecretkey", "secret key for encoding the token")
 
 	flag.StringVar(&flags.DatabaseDSN, "d", "host=localhost user=url password=1234 dbname=dbbot sslmode=disable", "DatabaseDSN")
 
 	flag.StringVar(&flags.TicketSecretKey, "s", "supersecretkey", "secret key for ticket token")
 
+	flag.StringVar(&flags.TicketKeysPath, "ticket-keys", "", "directory with ticket signing keys <kid>.pem")
+
+	flag.StringVar(&flags.TicketSigningKey, "ticket-kid", "", "kid of the key that signs new tickets")
+
+	flag.StringVar(&flags.HashAlgorithm, "p", "argon2id", "password hash algorithm: argon2id or bcrypt")
+
+	flag.IntVar(&flags.QRSize, "qr-size", 256, "ticket QR code size in pixels")
+
+	flag.StringVar(&flags.QRLevel, "qr-level", "M", "ticket QR code error correction level: L, M, Q or H")
+
+	flag.DurationVar(&flags.ShutdownTimeout, "shutdown-timeout", 30*time.Second, "time to finish requests and notifications on shutdown")
+
+	flag.BoolVar(&flags.WebhookEnabled, "webhook", false, "turn on the webhook channel")
+
+	flag.StringVar(&flags.WebhookSecret, "webhook-secret", "", "key that signs webhook notifications")
+
+	flag.StringVar(&flags.WebhookAllowHosts, "webhook-allow-hosts", "", "comma-separated hosts webhooks may go to, any public host when empty")
+
+	flag.StringVar(&flags.BotAPIURL, "bot-api-url", "https://api.telegram.org", "bot API base URL")
+
+	flag.StringVar(&flags.BotToken, "bot-token", "", "bot API token, the bot channel is off without it")
+
+	flag.StringVar(&flags.TemplatesDir, "templates", "", "directory with templates that replace the built-in notification ones")
+
+	flag.StringVar(&flags.BaseURL, "b", "http://localhost:8080", "public address of the service for links in emails")
+
+	flag.Int64Var(&flags.ImageMaxSize, "image-max-size", 10<<20, "largest uploaded image in bytes")
+
+	flag.IntVar(&flags.ImageMaxPixels, "image-max-pixels", 40_000_000, "largest uploaded image in pixels, width times height")
+
+	flag.DurationVar(&flags.ImageGCInterval, "image-gc-interval", time.Hour, "how often orphaned images are removed from the bucket, 0 turns it off")
+
+	flag.StringVar(&flags.ImageServeMode, "image-serve", "redirect", "how images are served: redirect to a presigned URL or stream")
+
+	flag.DurationVar(&flags.ImagePresignTTL, "image-presign-ttl", time.Hour, "lifetime of presigned image URLs")
+
+	flag.StringVar(&flags.StorageBackend, "blob-store", "minio", "where images are kept: minio, fs or memory")
+
+	flag.StringVar(&flags.StoragePath, "blob-path", "images", "directory of images for the fs blob store")
+
+	flag.StringVar(&flags.DatabaseBackend, "database", "postgres", "where data is kept: postgres or memory, memory is lost on exit")
+
+	flag.StringVar(&flags.EventTimezone, "event-tz", "Europe/Moscow", "IANA time zone of event dates, used in calendar files")
+
 	flag.BoolVar(&flags.Logger.LoggerFileFlag, "l", false, "Logger only file")
 	flag.BoolVar(&flags.Logger.LoggerMultiFlag, "L", false, "Logger Multi")
 
@@ -0,0 +1,28 @@
+package entity
+
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelBot     = "bot"
+)
+
+func ValidChannel(channel string) bool {
+	return channel == ChannelEmail || channel == ChannelWebhook || channel == ChannelBot
+}
+
+// UserChannel is where the user wants notifications over a channel: an email
+// address, a webhook URL or a bot chat id. An empty email address is the
+// address of the account. Verified tells whether the owner of an email
+// address opened the verification link, other addresses are not used
+// until then.
+type UserChannel struct {
+	Channel  string
+	Address  string
+	Verified bool
+}
+
+// Unverified reports whether the channel waits for its address to be
+// verified.
+func (c UserChannel) Unverified() bool {
+	return c.Channel == ChannelEmail && c.Address != "" && !c.Verified
+}
@@ -0,0 +1,9 @@
+package entity
+
+import "time"
+
+// Checkin is one accepted scan written by the offline scanner.
+type Checkin struct {
+	Token     string    `json:"token"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
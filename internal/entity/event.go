@@ -13,4 +13,8 @@ type Event struct {
 	Date            time.Time
 	Active          bool
 	Images          []Image
+	// SeriesID and Occurrence are set for occurrences of a recurring series,
+	// Occurrence is the start given by the rule before any edits.
+	SeriesID   int
+	Occurrence time.Time
 }
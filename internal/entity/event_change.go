@@ -0,0 +1,20 @@
+package entity
+
+// EventChange is one edited field of an event, values are formatted the way
+// the API accepts them.
+type EventChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ChangeNotice is a queued "event changed" email for one attendee.
+type ChangeNotice struct {
+	ID      int
+	EventID int
+	UserID  int
+	Mail    string
+	Locale  string
+	Token   string
+	Changes []EventChange
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+const (
+	SortDate       = "date"
+	SortDateDesc   = "-date"
+	SortRelevance  = "relevance"
+	SortSeats      = "seats"
+	SortPopularity = "popular"
+)
+
+// EventFilter selects events of the public listing. Zero values mean the
+// filter is not applied, except From and To which are always set.
+type EventFilter struct {
+	Query       string
+	From        time.Time
+	To          time.Time
+	OrganizerID int
+	Place       string
+	MinSeats    int
+	Sort        string
+	Limit       int
+	Page        int
+	// SeriesUntil lists occurrences of active series that have no event yet
+	// up to this time, with a zero ID. Zero lists events only.
+	SeriesUntil time.Time
+}
+
+func ValidSort(sort string) bool {
+	switch sort {
+	case SortDate, SortDateDesc, SortRelevance, SortSeats, SortPopularity:
+		return true
+	}
+	return false
+}
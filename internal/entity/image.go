@@ -1,6 +1,24 @@
 package entity
 
+// Image is a picture of an event. Filename is the object name of the
+// original, Data is its content on upload and empty when read back. An
+// event with images has exactly one Cover.
 type Image struct {
+	ID         int
 	Filename   string
-	Base64Data []byte
+	Data       []byte `json:"-"`
+	MIME       string
+	Width      int
+	Height     int
+	Cover      bool
+	Thumbnails []Thumbnail
+}
+
+// Thumbnail is a smaller copy of an Image with the same aspect ratio.
+type Thumbnail struct {
+	Filename string
+	Data     []byte `json:"-"`
+	MIME     string
+	Width    int
+	Height   int
 }
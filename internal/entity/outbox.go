@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxDead    = "dead"
+)
+
+func ValidOutboxStatus(status string) bool {
+	return status == OutboxPending || status == OutboxSent || status == OutboxDead
+}
+
+// OutboxMail is a notification waiting for delivery over Channel to Address.
+// Key identifies what it is about, so the same notification is never queued
+// twice for a channel. Body is HTML, Text the same message for channels
+// without HTML. Token is the ticket whose QR code goes with it, Calendar an
+// iCalendar file attached to emails.
+type OutboxMail struct {
+	ID            int
+	Key           string
+	UserID        int
+	EventID       int
+	Channel       string
+	Address       string
+	Subject       string
+	Body          string
+	Text          string
+	Token         string
+	Calendar      string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+}
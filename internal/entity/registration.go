@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Kinds of registration notices.
+const (
+	NoticeRegistration = "registration"
+	NoticeCancellation = "cancellation"
+	NoticePromotion    = "promotion"
+)
+
+// RegistrationNotice is a queued email about the attendee's registration:
+// confirmed, cancelled or moved from the waitlist. Token is the ticket, empty
+// after a cancellation.
+type RegistrationNotice struct {
+	ID      int
+	EventID int
+	UserID  int
+	Kind    string
+	Mail    string
+	Locale  string
+	Token   string
+}
+
+// DigestEvent is an event of the organizer with Signups new attendees since
+// the last digest.
+type DigestEvent struct {
+	EventID         int
+	Title           string
+	Date            time.Time
+	Signups         int
+	Participants    int
+	MaxParticipants int
+}
+
+// Digest is the new signups to the events of an organizer since Since.
+type Digest struct {
+	UserID int
+	Mail   string
+	Locale string
+	Since  time.Time
+	Events []DigestEvent
+}
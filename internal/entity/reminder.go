@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	// MaxReminders is how many reminders one schedule may have.
+	MaxReminders = 5
+	// MaxReminderMinutes is the earliest reminder, 30 days before the event.
+	MaxReminderMinutes = 30 * 24 * 60
+)
+
+// DefaultReminders is the schedule, in minutes before the event, of events
+// whose organizer did not set one.
+var DefaultReminders = []int{180}
+
+// Reminder is a reminder of the event to an attendee that is due, Minutes
+// before the event at Date. RecordID is the registration, a new one gets a
+// new id.
+type Reminder struct {
+	EventID  int
+	UserID   int
+	RecordID int
+	Minutes  int
+	Date     time.Time
+	Mail     string
+	Locale   string
+	Token    string
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	// SeriesHorizon is how far ahead occurrences of a series exist as events.
+	SeriesHorizon = 90 * 24 * time.Hour
+	// SeriesMaxHorizon bounds how far a listing shows occurrences that have
+	// no event yet.
+	SeriesMaxHorizon = 2 * 365 * 24 * time.Hour
+)
+
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+)
+
+// Series is a recurring event. Its occurrences are ordinary events, created
+// from RRule up to MaterializedUntil.
+type Series struct {
+	ID                int
+	UserID            int
+	Title             string
+	Description       string
+	Place             string
+	MaxParticipants   int
+	Start             time.Time
+	RRule             string
+	MaterializedUntil time.Time
+	Active            bool
+}
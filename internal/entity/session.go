@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type Session struct {
+	ID        int
+	UserID    int
+	Role      string
+	ExpiresAt time.Time
+}
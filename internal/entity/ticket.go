@@ -1,9 +1,13 @@
 package entity
 
+import "time"
+
 type Ticket struct {
-	UserID  int
-	EventID int
-	Exp     int
-	Status  bool
-	Token   string
+	UserID     int
+	EventID    int
+	Exp        int
+	Status     bool
+	Token      string
+	RedeemedAt time.Time
+	RedeemedBy int
 }
@@ -0,0 +1,36 @@
+package entity
+
+const (
+	RoleAttendee  = "attendee"
+	RoleOrganizer = "organizer"
+	RoleAdmin     = "admin"
+)
+
+type User struct {
+	ID       int
+	Login    string
+	Password string
+	Mail     string
+	Role     string
+
+	MailVerified bool
+}
+
+func ValidRole(role string) bool {
+	return role == RoleAttendee || role == RoleOrganizer || role == RoleAdmin
+}
+
+const (
+	LocaleEN = "en"
+	LocaleRU = "ru"
+
+	// DefaultLocale is the locale of users who did not pick one.
+	DefaultLocale = LocaleEN
+)
+
+// Locales are the languages notifications are written in.
+var Locales = []string{LocaleEN, LocaleRU}
+
+func ValidLocale(locale string) bool {
+	return locale == LocaleEN || locale == LocaleRU
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/authorization"
+	"graduation/internal/entity"
+	"graduation/internal/templates"
+	"net/url"
+	"time"
+)
+
+const (
+	verifyTokenEXP = 24 * time.Hour
+	resetTokenEXP  = time.Hour
+
+	verifyPath = "/api/user/verify"
+	// resetPath is the page of the site where the user types a new password,
+	// it sends the token to POST /api/user/password/reset.
+	resetPath = "/password/reset"
+)
+
+// queueAccountMail puts an account email into the outbox: a failing mail
+// server is retried there and does not show in the answer. key tells the
+// email apart from the others of the user.
+func (h *Handler) queueAccountMail(ctx context.Context, userID int, to, locale, name, key string, data templates.Data) error {
+	if h.templates == nil {
+		return errors.New("mail is not configured")
+	}
+
+	msg, err := h.templates.Render(locale, name, data)
+	if err != nil {
+		return fmt.Errorf("cannot render %s: %w", name, err)
+	}
+
+	err = h.storage.EnqueueMail(ctx, []entity.OutboxMail{{
+		Key:     key,
+		UserID:  userID,
+		Channel: entity.ChannelEmail,
+		Address: to,
+		Subject: msg.Subject,
+		Body:    msg.HTML,
+		Text:    msg.Text,
+	}})
+	if err != nil {
+		return fmt.Errorf("cannot enqueue %s: %w", name, err)
+	}
+	h.wake()
+
+	return nil
+}
+
+// queueVerification queues the verification link to mail, the address of
+// the account or of an email channel. The address is not used until the
+// link is opened.
+func (h *Handler) queueVerification(ctx context.Context, userID int, mail, locale string) error {
+	token, err := authorization.BuildMailToken(h.tokenSecretKey, verifyTokenEXP, userID, mail)
+	if err != nil {
+		return fmt.Errorf("cannot build mail token: %w", err)
+	}
+
+	key := fmt.Sprintf("verify:%d:%s:%d", userID, mail, time.Now().UnixNano())
+	return h.queueAccountMail(ctx, userID, mail, locale, templates.Verify, key, templates.Data{
+		Link: h.baseURL + verifyPath + "?token=" + url.QueryEscape(token),
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+)
+
+type DataRole struct {
+	Login string `json:"login"`
+	Role  string `json:"role"`
+}
+
+func (h *Handler) setRole(w http.ResponseWriter, r *http.Request, login, role string) {
+	if err := h.storage.SetRole(r.Context(), login, role); err != nil {
+		logger.Error("cannot set role: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) RoleGrant(w http.ResponseWriter, r *http.Request) {
+	var data DataRole
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if data.Login == "" || !entity.ValidRole(data.Role) {
+		logger.Error("bad login or role: %s %s", data.Login, data.Role)
+		problem.Write(w, http.StatusBadRequest, "login and role are required")
+		return
+	}
+
+	h.setRole(w, r, data.Login, data.Role)
+}
+
+func (h *Handler) RoleRevoke(w http.ResponseWriter, r *http.Request) {
+	var data DataRole
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if data.Login == "" {
+		logger.Error("bad login: %s", data.Login)
+		problem.Write(w, http.StatusBadRequest, "login is required")
+		return
+	}
+
+	h.setRole(w, r, data.Login, entity.RoleAttendee)
+}
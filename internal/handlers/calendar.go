@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"graduation/internal/authorization"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/ics"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/storage"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const calendarFeedPath = "/api/user/calendar.ics"
+
+type RespCalendarToken struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+func writeCalendar(w http.ResponseWriter, calendar *ics.Calendar, filename string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar.Encode(time.Now()))
+}
+
+func (h *Handler) EventICS(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/ics"))
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	writeCalendar(w, &ics.Calendar{Location: h.location, Events: []entity.Event{*event}}, "event-"+strconv.Itoa(event.ID)+".ics")
+}
+
+// CalendarToken issues a new secret for the personal calendar feed, the
+// previous feed URL stops working.
+func (h *Handler) CalendarToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	token, hash, err := authorization.BuildOpaqueToken()
+	if err != nil {
+		logger.Error("cannot build calendar token: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if err := h.storage.SetCalendarToken(r.Context(), userID, hash); err != nil {
+		logger.Error("cannot set calendar token: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	respToken, err := json.Marshal(RespCalendarToken{
+		Token: token,
+		URL:   h.baseURL + calendarFeedPath + "?token=" + url.QueryEscape(token),
+	})
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respToken)
+}
+
+// CalendarFeed serves the events a user is registered for. Calendar apps
+// cannot log in, so the user is found by the secret token in the URL.
+func (h *Handler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		logger.Error("calendar token emty: %v", errors.New("token emty"))
+		problem.Write(w, http.StatusUnauthorized, "token is required")
+		return
+	}
+
+	userID, err := h.storage.CalendarUser(r.Context(), authorization.HashOpaqueToken(token))
+	if err != nil {
+		var notFound *storage.NotFoundError
+		if errors.As(err, &notFound) {
+			logger.Error("calendar token not exist: %v", err)
+			problem.Write(w, http.StatusUnauthorized, "calendar token not valid")
+		} else {
+			logger.Error("cannot get calendar user: %v", err)
+			problem.Write(w, http.StatusInternalServerError, "")
+		}
+		return
+	}
+
+	events, err := h.storage.GetUserEvents(r.Context(), userID)
+	if err != nil {
+		logger.Error("cannot get events: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	writeCalendar(w, &ics.Calendar{Name: "EVENT.NE", Location: h.location, Events: events}, "calendar.ics")
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RespCheckin struct {
+	EventID    string    `json:"event_id"`
+	Title      string    `json:"title"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+	CheckedIn  int       `json:"checked_in"`
+}
+
+type RespCheckinCount struct {
+	EventID         string `json:"event_id"`
+	CheckedIn       int    `json:"checked_in"`
+	Participants    int    `json:"participants"`
+	MaxParticipants int    `json:"max_participants"`
+}
+
+func (h *Handler) Checkin(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.String()[19:]
+	if token == "" {
+		logger.Error("token from url emty: %v", errors.New("token emty"))
+		problem.Write(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	ticket := entity.Ticket{
+		Token: token,
+	}
+
+	if err := h.tick.Validate(&ticket); err != nil {
+		logger.Error("cannot validate ticket: %v", err)
+		problem.Write(w, http.StatusBadRequest, "ticket is not valid")
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), ticket.EventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	redeemed, err := h.storage.RedeemTicket(r.Context(), token, userID)
+	if err != nil {
+		logger.Error("cannot redeem ticket: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	count, err := h.storage.CheckinCount(r.Context(), event.ID)
+	if err != nil {
+		logger.Error("cannot get checkin count: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	respCheckin, err := json.Marshal(RespCheckin{
+		EventID:    encoding.EncodeID(event.ID),
+		Title:      event.Title,
+		RedeemedAt: redeemed.RedeemedAt,
+		CheckedIn:  count,
+	})
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respCheckin)
+}
+
+func (h *Handler) CheckinCount(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(r.URL.String()[20:])
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	count, err := h.storage.CheckinCount(r.Context(), event.ID)
+	if err != nil {
+		logger.Error("cannot get checkin count: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	respCount, err := json.Marshal(RespCheckinCount{
+		EventID:         encoding.EncodeID(event.ID),
+		CheckedIn:       count,
+		Participants:    event.Participants,
+		MaxParticipants: event.MaxParticipants,
+	})
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respCount)
+}
@@ -1,10 +1,9 @@
 package handlers
 
 import (
-	"errors"
 	"graduation/internal/encoding"
 	"graduation/internal/logger"
-	"graduation/internal/storage"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 )
@@ -13,31 +12,33 @@ func (h *Handler) EventClose(w http.ResponseWriter, r *http.Request) {
 	eventID, err := encoding.DecodeID(r.URL.String()[17:])
 	if err != nil {
 		logger.Error("cannot get id from url: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
 		return
 	}
 
 	userID, err := strconv.Atoi(r.Header.Get("User_id"))
 	if err != nil {
 		logger.Error("cannot get user id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
 		return
 	}
 
-	if err := h.storage.CloseEvent(r.Context(), userID, eventID); err != nil {
-		var repErr *storage.RepError
-		if errors.As(err, &repErr) {
-			if repErr.UniqueViolation {
-				logger.Error("user not have event: %v", err)
-				w.WriteHeader(http.StatusUnauthorized)
-			} else if repErr.ForeignKeyViolation {
-				logger.Error("event not exist: %v", err)
-				w.WriteHeader(http.StatusNotFound)
-			}
-		} else {
-			logger.Error("cannot close event: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-		}
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	if err := h.storage.CloseEvent(r.Context(), event.UserID, eventID); err != nil {
+		logger.Error("cannot close event: %v", err)
+		writeError(w, err)
 		return
 	}
 
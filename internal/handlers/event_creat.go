@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"graduation/internal/entity"
 	"graduation/internal/logger"
-	"graduation/internal/utils"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,6 +12,8 @@ import (
 	"graduation/internal/encoding"
 )
 
+// Photo is an image of a new event. Filename is not used, the stored name
+// gets the extension of the stored format: PNG for a WebP.
 type Photo struct {
 	Filename   string `json:"filename"`
 	Base64Data []byte `json:"base64_data"`
@@ -32,21 +34,21 @@ func (h *Handler) EventCreat(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		logger.Error("bad json: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
 		return
 	}
 
 	userID, err := strconv.Atoi(r.Header.Get("User_id"))
 	if err != nil {
 		logger.Error("cannot get user id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
 		return
 	}
 
-	date, err := time.Parse("2006-01-02 15:04", data.Date)
+	date, err := data.validate(time.Now())
 	if err != nil {
-		logger.Error("cannot get data.Date: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		logger.Error("not correct event: %v", err)
+		writeError(w, err)
 		return
 	}
 
@@ -61,16 +63,19 @@ func (h *Handler) EventCreat(w http.ResponseWriter, r *http.Request) {
 		Active:          true,
 	}
 
-	for _, photo := range data.Photo {
-		event.Images = append(event.Images, entity.Image{
-			Filename:   utils.GenerateString() + ".jpg",
-			Base64Data: photo.Base64Data,
-		})
+	for index, photo := range data.Photo {
+		image, err := h.newImage(photo.Base64Data)
+		if err != nil {
+			logger.Error("not correct photo: %v", err)
+			writeImageError(w, index, err)
+			return
+		}
+		event.Images = append(event.Images, *image)
 	}
 
 	if err := h.storage.CreateEvent(r.Context(), &event); err != nil {
 		logger.Error("cannot creat event: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
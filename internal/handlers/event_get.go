@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
 	"net/http"
 	"time"
 
@@ -10,29 +11,31 @@ import (
 )
 
 type RespEvent struct {
-	ID              string    `json:"id"`
-	Title           string    `json:"title"`
-	Description     string    `json:"description"`
-	Place           string    `json:"place"`
-	Participants    int       `json:"participants"`
-	MaxParticipants int       `json:"max_participants"`
-	Date            time.Time `json:"data"`
-	Active          bool      `json:"active"`
-	Photo           []string  `json:"photo"`
+	ID              string      `json:"id,omitempty"`
+	Title           string      `json:"title"`
+	Description     string      `json:"description"`
+	Place           string      `json:"place"`
+	Participants    int         `json:"participants"`
+	MaxParticipants int         `json:"max_participants"`
+	Date            time.Time   `json:"data"`
+	Active          bool        `json:"active"`
+	Photo           []string    `json:"photo"`
+	Images          []RespImage `json:"images,omitempty"`
+	Series          string      `json:"series,omitempty"`
 }
 
 func (h *Handler) EventGet(w http.ResponseWriter, r *http.Request) {
 	eventID, err := encoding.DecodeID(r.URL.String()[11:])
 	if err != nil {
 		logger.Error("cannot get id from url: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
 		return
 	}
 
 	event, err := h.storage.GetEvent(r.Context(), eventID)
 	if err != nil {
 		logger.Error("cannot get event: %v", err)
-		w.WriteHeader(http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
@@ -45,6 +48,8 @@ func (h *Handler) EventGet(w http.ResponseWriter, r *http.Request) {
 		MaxParticipants: event.MaxParticipants,
 		Date:            event.Date,
 		Active:          event.Active,
+		Series:          encodeOptionalID(event.SeriesID),
+		Images:          respImages(event.Images),
 	}
 
 	for _, image := range event.Images {
@@ -54,7 +59,7 @@ func (h *Handler) EventGet(w http.ResponseWriter, r *http.Request) {
 	respEvent, err := json.Marshal(dataResp)
 	if err != nil {
 		logger.Error("cannot json to byte: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/imaging"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/utils"
+	"graduation/internal/validation"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxUploadImages is how many files one upload request may carry.
+	maxUploadImages = 10
+	// partOverhead covers the headers and boundary of a multipart part.
+	partOverhead = 4 << 10
+)
+
+// RespImage describes a stored image. Thumbnails are smallest first, so the
+// frontend can build srcset from them.
+type RespImage struct {
+	Filename   string          `json:"filename"`
+	MIME       string          `json:"mime"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Cover      bool            `json:"cover"`
+	Thumbnails []RespThumbnail `json:"thumbnails,omitempty"`
+}
+
+type RespThumbnail struct {
+	Filename string `json:"filename"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+func respImages(images []entity.Image) []RespImage {
+	var resp []RespImage
+	for _, image := range images {
+		respImage := RespImage{
+			Filename: image.Filename,
+			MIME:     image.MIME,
+			Width:    image.Width,
+			Height:   image.Height,
+			Cover:    image.Cover,
+		}
+		for _, thumbnail := range image.Thumbnails {
+			respImage.Thumbnails = append(respImage.Thumbnails, RespThumbnail{
+				Filename: thumbnail.Filename,
+				Width:    thumbnail.Width,
+				Height:   thumbnail.Height,
+			})
+		}
+		resp = append(resp, respImage)
+	}
+
+	return resp
+}
+
+// newImage cleans data and names the image and its thumbnails after the
+// detected type, like abc.jpg and abc_320.jpg.
+func (h *Handler) newImage(data []byte) (*entity.Image, error) {
+	if h.images == nil {
+		return nil, errors.New("images not configured")
+	}
+
+	result, err := h.images.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	name := utils.GenerateString()
+	image := &entity.Image{
+		Filename: name + imaging.Ext(result.MIME),
+		Data:     result.Data,
+		MIME:     result.MIME,
+		Width:    result.Width,
+		Height:   result.Height,
+	}
+	for _, thumbnail := range result.Thumbnails {
+		image.Thumbnails = append(image.Thumbnails, entity.Thumbnail{
+			Filename: fmt.Sprintf("%s_%d%s", name, thumbnail.Width, imaging.Ext(thumbnail.MIME)),
+			Data:     thumbnail.Data,
+			MIME:     thumbnail.MIME,
+			Width:    thumbnail.Width,
+			Height:   thumbnail.Height,
+		})
+	}
+
+	return image, nil
+}
+
+// writeImageError answers an upload whose image number index was rejected.
+func writeImageError(w http.ResponseWriter, index int, err error) {
+	switch {
+	case errors.Is(err, imaging.ErrTooLarge):
+		problem.Write(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("image %d: %v", index, err))
+	case errors.Is(err, imaging.ErrNotImage):
+		problem.Write(w, http.StatusUnsupportedMediaType, fmt.Sprintf("image %d: %v", index, err))
+	default:
+		problem.Write(w, http.StatusInternalServerError, "")
+	}
+}
+
+// DataImagesOrder lists every image of the event in the new order.
+type DataImagesOrder struct {
+	Filenames []string `json:"filenames"`
+}
+
+type DataImageCover struct {
+	Filename string `json:"filename"`
+}
+
+// managedEvent returns the id of the event in id when the user may manage
+// its images, otherwise it answers the request and returns false.
+func (h *Handler) managedEvent(w http.ResponseWriter, r *http.Request, id string) (int, bool) {
+	eventID, err := encoding.DecodeID(id)
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return 0, false
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return 0, false
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return 0, false
+	}
+
+	return event.ID, true
+}
+
+// EventImagesUpload adds the files of the "image" parts of a
+// multipart/form-data body to the end of the event's images.
+func (h *Handler) EventImagesUpload(w http.ResponseWriter, r *http.Request) {
+	if h.images == nil {
+		logger.Error("images not configured")
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	eventID, ok := h.managedEvent(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/images"))
+	if !ok {
+		return
+	}
+
+	maxSize := h.images.MaxSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadImages*(maxSize+partOverhead))
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		logger.Error("not multipart: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not multipart/form-data")
+		return
+	}
+
+	var images []entity.Image
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.Error("cannot read part: %v", err)
+			writeMultipartError(w, err)
+			return
+		}
+
+		if part.FormName() != "image" {
+			part.Close()
+			continue
+		}
+
+		if len(images) == maxUploadImages {
+			logger.Error("more than %d images", maxUploadImages)
+			problem.Write(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d images per request", maxUploadImages))
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+		if err != nil {
+			logger.Error("cannot read image: %v", err)
+			writeMultipartError(w, err)
+			return
+		}
+
+		image, err := h.newImage(data)
+		if err != nil {
+			logger.Error("not correct image: %v", err)
+			writeImageError(w, len(images), err)
+			return
+		}
+		images = append(images, *image)
+	}
+
+	if len(images) == 0 {
+		logger.Error("no images in upload")
+		problem.Write(w, http.StatusBadRequest, "no image parts in the request")
+		return
+	}
+
+	if err := h.storage.AddEventImages(r.Context(), eventID, images); err != nil {
+		logger.Error("cannot add images: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	respImages, err := json.Marshal(respImages(images))
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respImages)
+}
+
+// EventImageDell removes the image named in the path
+// /api/event/{id}/images/{filename} with its thumbnails.
+func (h *Handler) EventImageDell(w http.ResponseWriter, r *http.Request) {
+	id, filename, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/images/")
+	eventID, ok := h.managedEvent(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := h.storage.DellEventImage(r.Context(), eventID, filename); err != nil {
+		logger.Error("cannot dell image: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) EventImagesOrder(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := h.managedEvent(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/images/order"))
+	if !ok {
+		return
+	}
+
+	var data DataImagesOrder
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	v := validation.New()
+	v.Check(len(data.Filenames) > 0, "filenames", "is required")
+	seen := make(map[string]bool)
+	for _, filename := range data.Filenames {
+		v.Check(!seen[filename], "filenames", "must not repeat a filename")
+		seen[filename] = true
+	}
+	if err := v.Err(); err != nil {
+		logger.Error("not correct order: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if err := h.storage.ReorderEventImages(r.Context(), eventID, data.Filenames); err != nil {
+		logger.Error("cannot reorder images: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) EventImageCover(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := h.managedEvent(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/images/cover"))
+	if !ok {
+		return
+	}
+
+	var data DataImageCover
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	v := validation.New()
+	v.Required("filename", data.Filename)
+	if err := v.Err(); err != nil {
+		logger.Error("not correct cover: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if err := h.storage.SetEventCover(r.Context(), eventID, data.Filename); err != nil {
+		logger.Error("cannot set cover: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeMultipartError(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		problem.Write(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+
+	problem.Write(w, http.StatusBadRequest, "multipart body is not valid")
+}
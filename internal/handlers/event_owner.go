@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"graduation/internal/entity"
+	"net/http"
+)
+
+// canManageEvent reports whether the user may manage the event: its
+// organizer, or an admin on behalf of the organizer.
+func canManageEvent(r *http.Request, userID int, event *entity.Event) bool {
+	return event.UserID == userID || r.Header.Get("User_role") == entity.RoleAdmin
+}
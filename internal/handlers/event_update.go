@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/validation"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const eventDateLayout = "2006-01-02 15:04"
+
+// DataEventUpdate holds the fields of PATCH /api/event/{id}, a nil field is
+// left unchanged.
+type DataEventUpdate struct {
+	Title        *string `json:"title"`
+	Description  *string `json:"description"`
+	Place        *string `json:"place"`
+	Participants *int    `json:"participants"`
+	Date         *string `json:"date"`
+}
+
+type RespEventUpdate struct {
+	ID      string               `json:"id"`
+	Changes []entity.EventChange `json:"changes"`
+}
+
+// applyUpdate copies the set fields of data into event and returns what
+// changed. Returns validation.Errors when a new value is not valid.
+func applyUpdate(event *entity.Event, data *DataEventUpdate, now time.Time) ([]entity.EventChange, error) {
+	v := validation.New()
+
+	if data.Title != nil {
+		v.Required("title", *data.Title)
+		v.MaxLength("title", *data.Title, maxTitleLength)
+	}
+	if data.Description != nil {
+		v.MaxLength("description", *data.Description, maxDescriptionLength)
+	}
+	if data.Place != nil {
+		v.Required("place", *data.Place)
+		v.MaxLength("place", *data.Place, maxPlaceLength)
+	}
+	if data.Participants != nil {
+		v.Positive("participants", *data.Participants)
+	}
+
+	var date time.Time
+	if data.Date != nil {
+		var ok bool
+		if date, ok = v.Date("date", *data.Date, eventDateLayout); ok && !date.Equal(event.Date) {
+			v.Future("date", date, now)
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	changes := []entity.EventChange{}
+
+	if data.Title != nil && *data.Title != event.Title {
+		changes = append(changes, entity.EventChange{Field: "title", Old: event.Title, New: *data.Title})
+		event.Title = *data.Title
+	}
+
+	if data.Description != nil && *data.Description != event.Description {
+		changes = append(changes, entity.EventChange{Field: "description", Old: event.Description, New: *data.Description})
+		event.Description = *data.Description
+	}
+
+	if data.Place != nil && *data.Place != event.Place {
+		changes = append(changes, entity.EventChange{Field: "place", Old: event.Place, New: *data.Place})
+		event.Place = *data.Place
+	}
+
+	if data.Participants != nil && *data.Participants != event.MaxParticipants {
+		changes = append(changes, entity.EventChange{
+			Field: "participants",
+			Old:   strconv.Itoa(event.MaxParticipants),
+			New:   strconv.Itoa(*data.Participants),
+		})
+		event.MaxParticipants = *data.Participants
+	}
+
+	if data.Date != nil && !date.Equal(event.Date) {
+		changes = append(changes, entity.EventChange{
+			Field: "date",
+			Old:   event.Date.Format(eventDateLayout),
+			New:   date.Format(eventDateLayout),
+		})
+		event.Date = date
+	}
+
+	return changes, nil
+}
+
+func (h *Handler) EventUpdate(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(r.URL.String()[11:])
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var data DataEventUpdate
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	h.saveEventUpdate(w, r, userID, event, &data)
+}
+
+// saveEventUpdate applies data to the event, saves it and writes the
+// response.
+func (h *Handler) saveEventUpdate(w http.ResponseWriter, r *http.Request, userID int, event *entity.Event, data *DataEventUpdate) {
+	if !event.Active {
+		logger.Error("event %d closed", event.ID)
+		problem.Write(w, http.StatusConflict, "event is closed")
+		return
+	}
+
+	changes, err := applyUpdate(event, data, time.Now())
+	if err != nil {
+		logger.Error("not correct update: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if event.MaxParticipants < event.Participants {
+		logger.Error("max participants %d less than participants %d", event.MaxParticipants, event.Participants)
+		problem.Write(w, http.StatusConflict, "participants less than registered users")
+		return
+	}
+
+	if len(changes) > 0 {
+		if err := h.storage.UpdateEvent(r.Context(), userID, event, changes, h.tick); err != nil {
+			logger.Error("cannot update event: %v", err)
+			writeError(w, err)
+			return
+		}
+		h.wake()
+	}
+
+	respUpdate, err := json.Marshal(RespEventUpdate{ID: encoding.EncodeID(event.ID), Changes: changes})
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respUpdate)
+}
@@ -2,89 +2,130 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"graduation/internal/encoding"
+	"graduation/internal/entity"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
-type OptionalFrom time.Time
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 100
+)
 
-func (o *OptionalFrom) UnmarshalJSON(data []byte) error {
-	var value string
-	if err := json.Unmarshal(data, &value); err != nil {
-		return err
-	}
+type RespEvents struct {
+	Page   int         `json:"page"`
+	Pages  int         `json:"pages"`
+	Total  int         `json:"total"`
+	Events []RespEvent `json:"events"`
+}
 
-	date, err := time.Parse("2006-01-02", value)
-	if err != nil {
-		return err
+func parseInt(query url.Values, key string, value int) (int, error) {
+	if param := query.Get(key); param != "" {
+		return strconv.Atoi(param)
 	}
-
-	*o = OptionalFrom(date)
-	return nil
+	return value, nil
 }
 
-type OptionalTo time.Time
+// parseEventFilter reads the listing filter from the query string:
+// q, from, to (2006-01-02), organizer, place, seats, sort, limit, page.
+// Without from and to the listing covers today.
+func parseEventFilter(query url.Values, now time.Time) (*entity.EventFilter, error) {
+	today := now.Truncate(24 * time.Hour)
+	filter := entity.EventFilter{
+		Query: strings.TrimSpace(query.Get("q")),
+		From:  today,
+		To:    today.Add(23*time.Hour + 59*time.Minute + 59*time.Second),
+		Place: strings.TrimSpace(query.Get("place")),
+		Sort:  entity.SortDate,
+		Limit: defaultEventsLimit,
+		Page:  1,
+	}
 
-func (o *OptionalTo) UnmarshalJSON(data []byte) error {
-	var value string
-	if err := json.Unmarshal(data, &value); err != nil {
-		return err
+	if from := query.Get("from"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, fmt.Errorf("not correct from: %w", err)
+		}
+		filter.From = date
 	}
 
-	date, err := time.Parse("2006-01-02", value)
-	if err != nil {
-		return err
+	if to := query.Get("to"); to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, fmt.Errorf("not correct to: %w", err)
+		}
+		filter.To = date.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 	}
 
-	*o = OptionalTo(date.Add(23*time.Hour + 59*time.Minute + 59*time.Second))
-	return nil
-}
+	if filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("to before from")
+	}
 
-type DataEventsGet struct {
-	From  OptionalFrom `json:"from"`
-	To    OptionalTo   `json:"to"`
-	Limit int          `json:"limit"`
-	Page  int          `json:"page"`
-}
+	if organizer := query.Get("organizer"); organizer != "" {
+		organizerID, err := encoding.DecodeID(organizer)
+		if err != nil {
+			return nil, fmt.Errorf("not correct organizer: %w", err)
+		}
+		filter.OrganizerID = organizerID
+	}
 
-type RespEvents struct {
-	Page   int         `json:"page"`
-	Pages  int         `json:"pages"`
-	Events []RespEvent `json:"events"`
-}
+	var err error
+	if filter.MinSeats, err = parseInt(query, "seats", 0); err != nil || filter.MinSeats < 0 {
+		return nil, fmt.Errorf("not correct seats: %v", err)
+	}
+	if filter.Limit, err = parseInt(query, "limit", defaultEventsLimit); err != nil || filter.Limit < 1 || filter.Limit > maxEventsLimit {
+		return nil, fmt.Errorf("not correct limit: %v", err)
+	}
+	if filter.Page, err = parseInt(query, "page", 1); err != nil || filter.Page < 1 {
+		return nil, fmt.Errorf("not correct page: %v", err)
+	}
 
-func intDataEventsGet() *DataEventsGet {
-	data := DataEventsGet{
-		From:  OptionalFrom(time.Now().Truncate(24 * time.Hour)),
-		To:    OptionalTo(time.Now().Truncate(24 * time.Hour).Add(23*time.Hour + 59*time.Minute + 59*time.Second)),
-		Limit: 100,
-		Page:  1,
+	if sort := query.Get("sort"); sort != "" {
+		if !entity.ValidSort(sort) {
+			return nil, fmt.Errorf("not correct sort: %s", sort)
+		}
+		filter.Sort = sort
+	} else if filter.Query != "" {
+		filter.Sort = entity.SortRelevance
 	}
-	return &data
+
+	return &filter, nil
 }
 
 func (h *Handler) EventsGet(w http.ResponseWriter, r *http.Request) {
-	data := intDataEventsGet()
-
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		logger.Error("not byte to json: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+	filter, err := parseEventFilter(r.URL.Query(), time.Now())
+	if err != nil {
+		logger.Error("cannot parse filter: %v", err)
+		problem.Write(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	events, pages, err := h.storage.GetEvents(r.Context(), time.Time(data.From), time.Time(data.To), data.Limit, data.Page)
+	// Occurrences of series exist SeriesHorizon ahead, a listing looking
+	// further shows the rest without creating them.
+	now := time.Now()
+	if filter.To.After(now.Add(entity.SeriesHorizon)) {
+		filter.SeriesUntil = now.Add(entity.SeriesMaxHorizon)
+	}
+
+	events, total, err := h.storage.GetEvents(r.Context(), filter)
 	if err != nil {
 		logger.Error("cannot get events: %v", err)
-		w.WriteHeader(http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
 	dataEvents := []RespEvent{}
 	for index, event := range events {
 		dataEvents = append(dataEvents, RespEvent{
-			ID:              encoding.EncodeID(event.ID),
+			ID:              encodeOptionalID(event.ID),
 			Title:           event.Title,
 			Description:     event.Description,
 			Place:           event.Place,
@@ -92,6 +133,8 @@ func (h *Handler) EventsGet(w http.ResponseWriter, r *http.Request) {
 			MaxParticipants: event.MaxParticipants,
 			Date:            event.Date,
 			Active:          event.Active,
+			Series:          encodeOptionalID(event.SeriesID),
+			Images:          respImages(event.Images),
 		})
 		for _, image := range event.Images {
 			dataEvents[index].Photo = append(dataEvents[index].Photo, image.Filename)
@@ -99,15 +142,16 @@ func (h *Handler) EventsGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	dataResp := RespEvents{
-		Page:   data.Page,
-		Pages:  pages,
+		Page:   filter.Page,
+		Pages:  int(math.Ceil(float64(total) / float64(filter.Limit))),
+		Total:  total,
 		Events: dataEvents,
 	}
 
 	respEvent, err := json.Marshal(dataResp)
 	if err != nil {
 		logger.Error("cannot json to byte: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
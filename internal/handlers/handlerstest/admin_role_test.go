@@ -0,0 +1,137 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerRole(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		url                string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/admin/role/grant #1 
+correct input body
+got status 200
+			`,
+			url:       "/api/admin/role/grant",
+			inputBody: `{"login": "user_1", "role": "organizer"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetRole(ctx, "user_1", "organizer").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/admin/role/grant #2
+unknown role
+got status 400
+			`,
+			url:                "/api/admin/role/grant",
+			inputBody:          `{"login": "user_1", "role": "root"}`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/admin/role/grant #3
+user not exist
+got status 404
+			`,
+			url:       "/api/admin/role/grant",
+			inputBody: `{"login": "user_1", "role": "admin"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetRole(ctx, "user_1", "admin").Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+POST /api/admin/role/revoke #4
+correct input body
+got status 200
+			`,
+			url:       "/api/admin/role/revoke",
+			inputBody: `{"login": "user_1"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetRole(ctx, "user_1", "attendee").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/admin/role/revoke #5
+not correct return SetRole
+got status 500
+			`,
+			url:       "/api/admin/role/revoke",
+			inputBody: `{"login": "user_1"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetRole(ctx, "user_1", "attendee").Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+POST /api/admin/role/revoke #6
+not correct input body
+got status 400
+			`,
+			url:                "/api/admin/role/revoke",
+			inputBody:          ``,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "grant") {
+					h.RoleGrant(w, r)
+				} else {
+					h.RoleRevoke(w, r)
+				}
+			}
+
+			req, err := http.NewRequest("POST", test.url, strings.NewReader(test.inputBody))
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
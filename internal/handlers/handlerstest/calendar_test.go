@@ -0,0 +1,268 @@
+package handlerstest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"graduation/internal/authorization"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerEventICS(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, eventID int)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputEventID       int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedContains   string
+	}{
+		{
+			name: `
+GET /api/event/{id}/ics #1 
+correct inputID
+got status 200
+			`,
+			inputID:      `MQ==`,
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{
+					ID:    eventID,
+					Title: "Title_1",
+					Date:  time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedContains:   "BEGIN:VEVENT\r\nUID:event-MQ==@event.ne\r\n",
+		},
+		{
+			name: `
+GET /api/event/{id}/ics #2
+not correct inputID
+got status 400
+			`,
+			inputID:            ``,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/event/{id}/ics #3
+event not exist
+got status 404
+			`,
+			inputID:      `MQ==`,
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), test.inputEventID)
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.EventICS(w, r)
+			}
+
+			req, err := http.NewRequest("GET", "/api/event/"+test.inputID+"/ics", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedContains != "" {
+				assert.Equal(t, "text/calendar; charset=utf-8", rr.Header().Get("Content-Type"))
+				assert.Contains(t, rr.Body.String(), test.expectedContains)
+			}
+		})
+	}
+}
+
+func TestHandlerCalendarToken(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	t.Run(`
+POST /api/user/calendar/token #1 
+correct headerID
+got status 200
+	`, func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+
+		repo := mock.NewMockStorage(c)
+
+		var stored string
+		repo.EXPECT().SetCalendarToken(gomock.Any(), 1, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, userID int, tokenHash string) error {
+				stored = tokenHash
+				return nil
+			})
+
+		h := handlers.Init(handlers.Deps{Storage: repo, BaseURL: "https://example.com/"})
+
+		req, err := http.NewRequest("POST", "/api/user/calendar/token", nil)
+		assert.NoError(t, err)
+		req.Header.Set("User_id", "1")
+
+		rr := httptest.NewRecorder()
+		h.CalendarToken(rr, req)
+
+		assert.Equal(t, 200, rr.Code)
+
+		var resp handlers.RespCalendarToken
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, authorization.HashOpaqueToken(resp.Token), stored)
+		// calendar apps subscribe only to absolute URLs
+		assert.Equal(t, "https://example.com/api/user/calendar.ics?token="+url.QueryEscape(resp.Token), resp.URL)
+	})
+
+	t.Run(`
+POST /api/user/calendar/token #2
+not correct headerID
+got status 400
+	`, func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+
+		h := handlers.Init(handlers.Deps{Storage: mock.NewMockStorage(c)})
+
+		req, err := http.NewRequest("POST", "/api/user/calendar/token", nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		h.CalendarToken(rr, req)
+
+		assert.Equal(t, 400, rr.Code)
+	})
+}
+
+func TestHandlerCalendarFeed(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, tokenHash string)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputToken         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedEvents     int
+	}{
+		{
+			name: `
+GET /api/user/calendar.ics #1 
+correct token
+got status 200
+			`,
+			inputToken: "token",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, tokenHash string) {
+				r.EXPECT().CalendarUser(ctx, tokenHash).Return(1, nil)
+				r.EXPECT().GetUserEvents(ctx, 1).Return([]entity.Event{
+					{ID: 1, Title: "Title_1", Date: time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC)},
+					{ID: 2, Title: "Title_2", Date: time.Date(2024, 3, 11, 18, 30, 0, 0, time.UTC)},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedEvents:     2,
+		},
+		{
+			name: `
+GET /api/user/calendar.ics #2
+without token
+got status 401
+			`,
+			inputToken:         "",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, tokenHash string) {},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+GET /api/user/calendar.ics #3
+unknown token
+got status 401
+			`,
+			inputToken: "token",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, tokenHash string) {
+				r.EXPECT().CalendarUser(ctx, tokenHash).Return(0, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+GET /api/user/calendar.ics #4
+not correct return GetUserEvents
+got status 500
+			`,
+			inputToken: "token",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, tokenHash string) {
+				r.EXPECT().CalendarUser(ctx, tokenHash).Return(1, nil)
+				r.EXPECT().GetUserEvents(ctx, 1).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), authorization.HashOpaqueToken(test.inputToken))
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.CalendarFeed(w, r)
+			}
+
+			req, err := http.NewRequest("GET", "/api/user/calendar.ics?token="+test.inputToken, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedEvents > 0 {
+				assert.Equal(t, test.expectedEvents, strings.Count(rr.Body.String(), "BEGIN:VEVENT"))
+			}
+		})
+	}
+}
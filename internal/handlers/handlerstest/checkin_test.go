@@ -0,0 +1,255 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"graduation/internal/ticket"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerCheckin(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, token string)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tick, err := ticket.Init(&config.TicketKey{TicketSecretKey: "123"})
+	assert.NoError(t, err)
+
+	valid := entity.Ticket{UserID: 2, EventID: 1, Exp: 1}
+	assert.NoError(t, tick.Generate(&valid))
+
+	tests := []struct {
+		name               string
+		inputToken         string
+		headerID           string
+		headerRole         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/event/checkin #1 
+first scan by organizer
+got status 200
+			`,
+			inputToken: valid.Token,
+			headerID:   "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, token string) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 1}, nil)
+				r.EXPECT().RedeemTicket(ctx, token, 1).Return(&entity.Ticket{EventID: 1, RedeemedAt: time.Now()}, nil)
+				r.EXPECT().CheckinCount(ctx, 1).Return(1, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/event/checkin #2
+second scan
+got status 409
+			`,
+			inputToken: valid.Token,
+			headerID:   "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, token string) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 1}, nil)
+				r.EXPECT().RedeemTicket(ctx, token, 1).Return(nil, &storage.ConflictError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+POST /api/event/checkin #3
+not organizer of event
+got status 403
+			`,
+			inputToken: valid.Token,
+			headerID:   "3",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, token string) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 1}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+POST /api/event/checkin #4
+admin scan
+got status 200
+			`,
+			inputToken: valid.Token,
+			headerID:   "3",
+			headerRole: entity.RoleAdmin,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, token string) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 1}, nil)
+				r.EXPECT().RedeemTicket(ctx, token, 3).Return(&entity.Ticket{EventID: 1, RedeemedAt: time.Now()}, nil)
+				r.EXPECT().CheckinCount(ctx, 1).Return(1, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/event/checkin #5
+not correct token
+got status 400
+			`,
+			inputToken:         "bad_token",
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, token string) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/checkin #6
+ticket not exist
+got status 404
+			`,
+			inputToken: valid.Token,
+			headerID:   "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, token string) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 1}, nil)
+				r.EXPECT().RedeemTicket(ctx, token, 1).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), test.inputToken)
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Ticket: tick})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.Checkin(w, r)
+			}
+
+			req, err := http.NewRequest("POST", "/api/event/checkin/"+test.inputToken, nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerCheckinCount(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, eventID int)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name                 string
+		inputID              string
+		headerID             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: `
+GET /api/event/checkins #1 
+correct inputID, headerID
+got status 200
+			`,
+			inputID:  `MQ==`,
+			headerID: "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1, Participants: 5, MaxParticipants: 10}, nil)
+				r.EXPECT().CheckinCount(ctx, eventID).Return(3, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"event_id":"MQ==","checked_in":3,"participants":5,"max_participants":10}`,
+		},
+		{
+			name: `
+GET /api/event/checkins #2
+not organizer of event
+got status 403
+			`,
+			inputID:  `MQ==`,
+			headerID: "2",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+GET /api/event/checkins #3
+event not exist
+got status 404
+			`,
+			inputID:  `MQ==`,
+			headerID: "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+GET /api/event/checkins #4
+not correct inputID
+got status 400
+			`,
+			inputID:            ``,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID int) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), 1)
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.CheckinCount(w, r)
+			}
+
+			req, err := http.NewRequest("GET", "/api/event/checkins/"+test.inputID, nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedResponseBody != "" {
+				assert.Equal(t, test.expectedResponseBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -45,7 +45,7 @@ got status 200
 				"description": "Description",
 				"place": "Place",
 				"participants": 1,
-				"date": "2023-11-28 00:00",
+				"date": "2030-11-28 00:00",
 				"photo": []
 				}`,
 			headerID: "1",
@@ -56,7 +56,7 @@ got status 200
 				Place:           "Place",
 				Participants:    0,
 				MaxParticipants: 1,
-				Date:            utils.ParseDate("2023-11-28 00:00"),
+				Date:            utils.ParseDate("2030-11-28 00:00"),
 				Active:          true,
 			},
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, e *entity.Event) {
@@ -87,7 +87,7 @@ got status 400
 				"description": "Description",
 				"place": "Place",
 				"participants": 1,
-				"date": "2023-11-28 00:00",
+				"date": "2030-11-28 00:00",
 				"photo": []
 				}`,
 			headerID:           "",
@@ -116,14 +116,14 @@ got status 400
 			name: `
 POST /api/event/creat #5 
 not correct return CreateEvent
-got status 400
+got status 500
 			`,
 			inputBody: `{
 				"title": "Title",
 				"description": "Description",
 				"place": "Place",
 				"participants": 1,
-				"date": "2023-11-28 00:00",
+				"date": "2030-11-28 00:00",
 				"photo": []
 				}`,
 			headerID: "1",
@@ -134,13 +134,13 @@ got status 400
 				Place:           "Place",
 				Participants:    0,
 				MaxParticipants: 1,
-				Date:            utils.ParseDate("2023-11-28 00:00"),
+				Date:            utils.ParseDate("2030-11-28 00:00"),
 				Active:          true,
 			},
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, e *entity.Event) {
 				r.EXPECT().CreateEvent(ctx, e).Return(errors.New("err"))
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
 		},
 	}
 
@@ -152,7 +152,7 @@ got status 400
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), &test.event)
 
-			h := handlers.Init(repo, nil, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.EventCreat(w, r)
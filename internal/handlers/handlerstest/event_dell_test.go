@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"graduation/internal/config"
+	"graduation/internal/entity"
 	"graduation/internal/handlers"
 	"graduation/internal/logger"
 	"graduation/internal/storage"
@@ -27,6 +28,7 @@ func TestHandlerEventDell(t *testing.T) {
 		name               string
 		inputID            string
 		headerID           string
+		headerRole         string
 		inputEventID       int
 		inputUserID        int
 		mockBehavior       mockBehavior
@@ -43,6 +45,7 @@ got status 200
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: userID}, nil)
 				r.EXPECT().DellEvent(ctx, userID, eventID).Return(nil)
 			},
 			expectedStatusCode: 200,
@@ -62,21 +65,22 @@ got status 400
 			name: `
 POST /api/event/dell #3
 not correct return DellEvent
-got status 400
+got status 500
 			`,
 			inputID:      `MQ==`,
 			headerID:     "1",
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: userID}, nil)
 				r.EXPECT().DellEvent(ctx, userID, eventID).Return(errors.New("err"))
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
 		},
 		{
 			name: `
 POST /api/event/dell #4
-not correct return DellEvent (event not exist)
+not correct return GetEvent (event not exist)
 got status 404
 			`,
 			inputID:      `MQ==`,
@@ -84,24 +88,24 @@ got status 404
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
-				r.EXPECT().DellEvent(ctx, userID, eventID).Return(&storage.RepError{Err: errors.New("err"), ForeignKeyViolation: true})
+				r.EXPECT().GetEvent(ctx, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 404,
 		},
 		{
 			name: `
 POST /api/event/dell #5
-not correct return DellEvent (user not have event)
-got status 401
+user not organizer of event
+got status 403
 			`,
 			inputID:      `MQ==`,
 			headerID:     "1",
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
-				r.EXPECT().DellEvent(ctx, userID, eventID).Return(&storage.RepError{Err: errors.New("err"), UniqueViolation: true})
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: userID + 1}, nil)
 			},
-			expectedStatusCode: 401,
+			expectedStatusCode: 403,
 		},
 		{
 			name: `
@@ -114,6 +118,23 @@ got status 400
 			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {},
 			expectedStatusCode: 400,
 		},
+		{
+			name: `
+POST /api/event/dell #7
+admin dell event of other user
+got status 200
+			`,
+			inputID:      `MQ==`,
+			headerID:     "2",
+			headerRole:   "admin",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: userID}, nil)
+				r.EXPECT().DellEvent(ctx, userID, eventID).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
 	}
 
 	for _, test := range tests {
@@ -124,7 +145,7 @@ got status 400
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputUserID, test.inputEventID)
 
-			h := handlers.Init(repo, nil, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.EventDell(w, r)
@@ -134,6 +155,7 @@ got status 400
 			assert.NoError(t, err)
 
 			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
 
 			rr := httptest.NewRecorder()
 
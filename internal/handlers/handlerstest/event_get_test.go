@@ -7,6 +7,7 @@ import (
 	"graduation/internal/entity"
 	"graduation/internal/handlers"
 	"graduation/internal/logger"
+	"graduation/internal/storage"
 	"graduation/internal/storage/mock"
 	"graduation/internal/utils"
 	"net/http"
@@ -76,7 +77,7 @@ got status 404
 			inputID:      `MQ==`,
 			inputEventID: 1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
-				r.EXPECT().GetEvent(ctx, eventID).Return(nil, errors.New("err"))
+				r.EXPECT().GetEvent(ctx, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 404,
 		},
@@ -90,7 +91,7 @@ got status 404
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputEventID)
 
-			h := handlers.Init(repo, nil, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.EventGet(w, r)
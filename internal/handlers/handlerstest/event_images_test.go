@@ -0,0 +1,496 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/imaging"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+// multipartBody puts each file into its own part named field.
+func multipartBody(t *testing.T, field string, files ...[]byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, file := range files {
+		part, err := writer.CreateFormFile(field, "photo.jpg")
+		require.NoError(t, err)
+		part.Write(file)
+	}
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestHandlerEventImagesUpload(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	images, err := imaging.Init(&config.Images{ImageMaxSize: 64 << 10, ImageMaxPixels: 1_000_000, ImageServeMode: imaging.ServeRedirect, ImagePresignTTL: time.Hour})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		field              string
+		files              [][]byte
+		contentType        string
+		headerID           string
+		headerRole         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: `
+POST /api/event/{id}/images #1
+organizer uploads png with a thumbnail
+got status 200
+			`,
+			field:      "image",
+			files:      [][]byte{pngImage(t, 400, 200)},
+			headerID:   "5",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().AddEventImages(ctx, 1, gomock.Any()).DoAndReturn(func(ctx context.Context, eventID int, images []entity.Image) error {
+					require.Len(t, images, 1)
+					assert.Equal(t, imaging.MIMEPNG, images[0].MIME)
+					assert.True(t, strings.HasSuffix(images[0].Filename, ".png"))
+					require.Len(t, images[0].Thumbnails, 1)
+					assert.Equal(t, strings.TrimSuffix(images[0].Filename, ".png")+"_320.png", images[0].Thumbnails[0].Filename)
+					assert.NotEmpty(t, images[0].Thumbnails[0].Data)
+					return nil
+				})
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `"mime":"image/png","width":400,"height":200,"cover":false,"thumbnails":[{"filename":`,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #2
+file is not an image
+got status 415
+			`,
+			field:      "image",
+			files:      [][]byte{pngImage(t, 10, 10), []byte("<svg></svg>")},
+			headerID:   "5",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 415,
+			expectedBody:       `image 1: not a JPEG, PNG, GIF or WebP image`,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #3
+file larger than the limit
+got status 413
+			`,
+			field:      "image",
+			files:      [][]byte{append([]byte("\xff\xd8\xff"), make([]byte, 100<<10)...)},
+			headerID:   "5",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 413,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #4
+not organizer of event
+got status 403
+			`,
+			field:      "image",
+			files:      [][]byte{pngImage(t, 10, 10)},
+			headerID:   "6",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #5
+event not exist
+got status 404
+			`,
+			field:      "image",
+			files:      [][]byte{pngImage(t, 10, 10)},
+			headerID:   "5",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #6
+no image parts
+got status 400
+			`,
+			field:      "file",
+			files:      [][]byte{pngImage(t, 10, 10)},
+			headerID:   "5",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #7
+body is not multipart
+got status 400
+			`,
+			contentType: "application/json",
+			headerID:    "5",
+			headerRole:  entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/{id}/images #8
+not correct return AddEventImages
+got status 500
+			`,
+			field:      "image",
+			files:      [][]byte{pngImage(t, 10, 10)},
+			headerID:   "9",
+			headerRole: entity.RoleAdmin,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().AddEventImages(ctx, 1, gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Images: images})
+
+			body, contentType := multipartBody(t, test.field, test.files...)
+			if test.contentType != "" {
+				contentType = test.contentType
+			}
+
+			req, err := http.NewRequest("POST", "/api/event/MQ==/images", body)
+			assert.NoError(t, err)
+
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
+
+			rr := httptest.NewRecorder()
+
+			h.EventImagesUpload(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), test.expectedBody)
+		})
+	}
+}
+
+func TestHandlerEventImageDell(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputPath          string
+		headerID           string
+		headerRole         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+DELETE /api/event/{id}/images/{filename} #1
+organizer removes image
+got status 200
+			`,
+			inputPath:  "/api/event/MQ==/images/abc.jpg",
+			headerID:   "5",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().DellEventImage(ctx, 1, "abc.jpg").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+DELETE /api/event/{id}/images/{filename} #2
+image not exist
+got status 404
+			`,
+			inputPath:  "/api/event/MQ==/images/abc.jpg",
+			headerID:   "9",
+			headerRole: entity.RoleAdmin,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().DellEventImage(ctx, 1, "abc.jpg").Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+DELETE /api/event/{id}/images/{filename} #3
+not organizer of event
+got status 403
+			`,
+			inputPath:  "/api/event/MQ==/images/abc.jpg",
+			headerID:   "6",
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+DELETE /api/event/{id}/images/{filename} #4
+not correct id
+got status 400
+			`,
+			inputPath:          "/api/event/%%%/images/abc.jpg",
+			headerID:           "5",
+			headerRole:         entity.RoleOrganizer,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req := httptest.NewRequest("DELETE", "/", nil)
+			req.URL.Path = test.inputPath
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
+
+			rr := httptest.NewRecorder()
+
+			h.EventImageDell(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerEventImagesOrder(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PUT /api/event/{id}/images/order #1
+correct order
+got status 200
+			`,
+			inputBody: `{"filenames":["b.png","a.jpg"]}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().ReorderEventImages(ctx, 1, []string{"b.png", "a.jpg"}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/event/{id}/images/order #2
+filename repeated
+got status 400
+			`,
+			inputBody: `{"filenames":["a.jpg","a.jpg"]}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/event/{id}/images/order #3
+order misses an image
+got status 409
+			`,
+			inputBody: `{"filenames":["a.jpg"]}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().ReorderEventImages(ctx, 1, []string{"a.jpg"}).Return(&storage.ConflictError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+PUT /api/event/{id}/images/order #4
+not correct input body
+got status 400
+			`,
+			inputBody: `{"filenames":`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("PUT", "/api/event/MQ==/images/order", strings.NewReader(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", "5")
+			req.Header.Set("User_role", entity.RoleOrganizer)
+
+			rr := httptest.NewRecorder()
+
+			h.EventImagesOrder(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerEventImageCover(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PUT /api/event/{id}/images/cover #1
+correct cover
+got status 200
+			`,
+			inputBody: `{"filename":"b.png"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().SetEventCover(ctx, 1, "b.png").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/event/{id}/images/cover #2
+filename missing
+got status 400
+			`,
+			inputBody: `{}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/event/{id}/images/cover #3
+image not exist
+got status 404
+			`,
+			inputBody: `{"filename":"c.png"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().SetEventCover(ctx, 1, "c.png").Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("PUT", "/api/event/MQ==/images/cover", strings.NewReader(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", "5")
+			req.Header.Set("User_role", entity.RoleOrganizer)
+
+			rr := httptest.NewRecorder()
+
+			h.EventImageCover(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
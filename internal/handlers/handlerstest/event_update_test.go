@@ -0,0 +1,271 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerEventUpdate(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, event entity.Event)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	date := time.Now().Add(time.Hour * 48).Truncate(time.Minute).UTC()
+	event := entity.Event{
+		ID:              1,
+		UserID:          1,
+		Title:           "title",
+		Description:     "description",
+		Place:           "place",
+		Participants:    5,
+		MaxParticipants: 10,
+		Date:            date,
+		Active:          true,
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputBody          string
+		headerID           string
+		headerRole         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PATCH /api/event/{id} #1 
+correct place, participants
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"place":"new place","participants":20}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+				updated := event
+				updated.Place = "new place"
+				updated.MaxParticipants = 20
+				r.EXPECT().UpdateEvent(ctx, 1, &updated, []entity.EventChange{
+					{Field: "place", Old: "place", New: "new place"},
+					{Field: "participants", Old: "10", New: "20"},
+				}, gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #2
+nothing changed
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"title":"title"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #3
+admin change date of other user event
+got status 200
+			`,
+			inputID:    `MQ==`,
+			inputBody:  `{"date":"` + date.Add(time.Hour).Format("2006-01-02 15:04") + `"}`,
+			headerID:   "2",
+			headerRole: "admin",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+				r.EXPECT().UpdateEvent(ctx, 2, gomock.Any(), gomock.Len(1), gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #4
+participants less than registered
+got status 409
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"participants":3}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #5
+participants grew between read and update
+got status 409
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"participants":6}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+				r.EXPECT().UpdateEvent(ctx, 1, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&storage.ConflictError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #6
+date in the past
+got status 400
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"date":"2000-01-01 10:00"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #7
+empty title
+got status 400
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"title":" "}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #8
+bad json
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"participants":"many"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, event entity.Event) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #9
+user not organizer of event
+got status 403
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"place":"new place"}`,
+			headerID:  "3",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #10
+event not exist
+got status 404
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"place":"new place"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #11
+event closed
+got status 409
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"place":"new place"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				event.Active = false
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #12
+not correct return UpdateEvent
+got status 500
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"place":"new place"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, event entity.Event) {
+				r.EXPECT().GetEvent(ctx, event.ID).Return(&event, nil)
+				r.EXPECT().UpdateEvent(ctx, 1, gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+PATCH /api/event/{id} #13
+not correct inputID
+got status 400
+			`,
+			inputID:            ``,
+			inputBody:          `{"place":"new place"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, event entity.Event) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), event)
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.EventUpdate(w, r)
+			}
+
+			req, err := http.NewRequest("PATCH", "/api/event/"+test.inputID, bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"graduation/internal/utils"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 	"time"
 
@@ -20,7 +19,7 @@ import (
 )
 
 func TestHandlerEventsGet(t *testing.T) {
-	type mockBehavior func(r *mock.MockStorage, ctx context.Context, from, to time.Time, limit, page int)
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter)
 
 	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
 		logger.Panic(err.Error())
@@ -28,11 +27,8 @@ func TestHandlerEventsGet(t *testing.T) {
 
 	tests := []struct {
 		name                 string
-		inputBody            string
-		from                 time.Time
-		to                   time.Time
-		limit                int
-		page                 int
+		inputQuery           string
+		filter               *entity.EventFilter
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -43,18 +39,16 @@ GET /api/events #1
 correct inputID
 got status 200
 			`,
-			inputBody: `{
-				"from": "2023-11-16",
-				"to": "2023-11-30",
-				"limit": 20,
-				"page": 1
-			}`,
-			from:  utils.ParseDate("2023-11-16 00:00"),
-			to:    time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC),
-			limit: 20,
-			page:  1,
-			mockBehavior: func(r *mock.MockStorage, ctx context.Context, from, to time.Time, limit, page int) {
-				r.EXPECT().GetEvents(ctx, from, to, limit, page).Return(
+			inputQuery: `?from=2023-11-16&to=2023-11-30&limit=20&page=1`,
+			filter: &entity.EventFilter{
+				From:  utils.ParseDate("2023-11-16 00:00"),
+				To:    time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC),
+				Sort:  entity.SortDate,
+				Limit: 20,
+				Page:  1,
+			},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {
+				r.EXPECT().GetEvents(ctx, filter).Return(
 					[]entity.Event{
 						{
 							ID:              1,
@@ -80,42 +74,149 @@ got status 200
 							Active:          true,
 							Images:          []entity.Image{},
 						},
-					}, 1, nil)
+					}, 2, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"page":1,"pages":1,"events":[{"id":"MQ==","title":"Title_1","description":"Description_1","place":"Place_1","participants":0,"max_participants":1,"data":"2023-11-28T00:01:00Z","active":true,"photo":null},{"id":"Mg==","title":"Title_2","description":"Description_2","place":"Place_2","participants":0,"max_participants":1,"data":"2023-11-28T00:01:00Z","active":true,"photo":null}]}`,
+			expectedResponseBody: `{"page":1,"pages":1,"total":2,"events":[{"id":"MQ==","title":"Title_1","description":"Description_1","place":"Place_1","participants":0,"max_participants":1,"data":"2023-11-28T00:01:00Z","active":true,"photo":null},{"id":"Mg==","title":"Title_2","description":"Description_2","place":"Place_2","participants":0,"max_participants":1,"data":"2023-11-28T00:01:00Z","active":true,"photo":null}]}`,
 		},
 		{
 			name: `
 GET /api/events #2
-not correct inputID
+not correct from
 got status 400
 			`,
-			inputBody:          ``,
-			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, from, to time.Time, limit, page int) {},
+			inputQuery:         `?from=16.11.2023`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {},
 			expectedStatusCode: 400,
 		},
 		{
 			name: `
 GET /api/events #3
 not correct return GetEvents
-got status 404
+got status 500
 			`,
-			inputBody: `{
-				"from": "2023-11-16",
-				"to": "2023-11-30",
-				"limit": 20,
-				"page": 1
-			}`,
-			from:  utils.ParseDate("2023-11-16 00:00"),
-			to:    time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC),
-			limit: 20,
-			page:  1,
-			mockBehavior: func(r *mock.MockStorage, ctx context.Context, from, to time.Time, limit, page int) {
-				r.EXPECT().GetEvents(ctx, from, to, limit, page).Return(
+			inputQuery: `?from=2023-11-16&to=2023-11-30&limit=20&page=1`,
+			filter: &entity.EventFilter{
+				From:  utils.ParseDate("2023-11-16 00:00"),
+				To:    time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC),
+				Sort:  entity.SortDate,
+				Limit: 20,
+				Page:  1,
+			},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {
+				r.EXPECT().GetEvents(ctx, filter).Return(
 					nil, 0, errors.New("err"))
 			},
-			expectedStatusCode: 404,
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+GET /api/events #4
+search with filters, relevance by default
+got status 200
+			`,
+			inputQuery: `?q=jazz+concert&from=2023-11-16&to=2023-11-30&organizer=Mg==&place=Hall&seats=3&limit=10&page=2`,
+			filter: &entity.EventFilter{
+				Query:       "jazz concert",
+				From:        utils.ParseDate("2023-11-16 00:00"),
+				To:          time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC),
+				OrganizerID: 2,
+				Place:       "Hall",
+				MinSeats:    3,
+				Sort:        entity.SortRelevance,
+				Limit:       10,
+				Page:        2,
+			},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {
+				r.EXPECT().GetEvents(ctx, filter).Return(nil, 11, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"page":2,"pages":2,"total":11,"events":[]}`,
+		},
+		{
+			name: `
+GET /api/events #5
+explicit sort
+got status 200
+			`,
+			inputQuery: `?from=2023-11-16&to=2023-11-30&sort=seats`,
+			filter: &entity.EventFilter{
+				From:  utils.ParseDate("2023-11-16 00:00"),
+				To:    time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC),
+				Sort:  entity.SortSeats,
+				Limit: 100,
+				Page:  1,
+			},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {
+				r.EXPECT().GetEvents(ctx, filter).Return(nil, 0, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"page":1,"pages":0,"total":0,"events":[]}`,
+		},
+		{
+			name: `
+GET /api/events #6
+not correct sort
+got status 400
+			`,
+			inputQuery:         `?sort=price`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/events #7
+not correct limit
+got status 400
+			`,
+			inputQuery:         `?limit=1000`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/events #8
+to before from
+got status 400
+			`,
+			inputQuery:         `?from=2023-11-30&to=2023-11-16`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/events #9
+not correct organizer
+got status 400
+			`,
+			inputQuery:         `?organizer=!!`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/events #10
+not correct seats
+got status 400
+			`,
+			inputQuery:         `?seats=-1`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/events #11
+to beyond the series horizon lists occurrences without creating them
+got status 200
+			`,
+			inputQuery: `?to=` + time.Now().Add(entity.SeriesHorizon+48*time.Hour).Format("2006-01-02"),
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filter *entity.EventFilter) {
+				r.EXPECT().GetEvents(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f *entity.EventFilter) ([]entity.Event, int, error) {
+					assert.False(t, f.SeriesUntil.IsZero())
+					return []entity.Event{{SeriesID: 2, Title: "Yoga", Active: true}}, 1, nil
+				})
+			},
+			expectedStatusCode: 200,
 		},
 	}
 
@@ -125,15 +226,15 @@ got status 404
 			defer c.Finish()
 
 			repo := mock.NewMockStorage(c)
-			test.mockBehavior(repo, context.Background(), test.from, test.to, test.limit, test.page)
+			test.mockBehavior(repo, context.Background(), test.filter)
 
-			h := handlers.Init(repo, nil, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.EventsGet(w, r)
 			}
 
-			req, err := http.NewRequest("GET", "/api/events", strings.NewReader(test.inputBody))
+			req, err := http.NewRequest("GET", "/api/events"+test.inputQuery, nil)
 			assert.NoError(t, err)
 
 			rr := httptest.NewRecorder()
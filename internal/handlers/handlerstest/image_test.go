@@ -1,20 +1,35 @@
 package handlerstest
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"graduation/internal/config"
 	"graduation/internal/handlers"
+	"graduation/internal/imaging"
 	"graduation/internal/logger"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
 	"graduation/internal/storage/mock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+type readSeekCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekCloser) Close() error {
+	return nil
+}
+
 func TestHandlerImage(t *testing.T) {
 	type mockBehavior func(r *mock.MockStorage, ctx context.Context, filename string)
 
@@ -22,38 +37,147 @@ func TestHandlerImage(t *testing.T) {
 		logger.Panic(err.Error())
 	}
 
+	modified := time.Date(2024, 5, 13, 12, 0, 0, 0, time.UTC)
+	object := func(r *mock.MockStorage, ctx context.Context, filename string) {
+		r.EXPECT().OpenImage(ctx, filename).Return(readSeekCloser{bytes.NewReader([]byte("0123456789"))}, &ostorage.Object{
+			Name:         filename,
+			ContentType:  "image/png",
+			ETag:         "abc",
+			Size:         10,
+			LastModified: modified,
+		}, nil)
+	}
+
 	tests := []struct {
 		name                 string
 		inputFilename        string
+		serveMode            string
+		requestHeaders       map[string]string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
+		expectedHeaders      map[string]string
 		expectedResponseBody string
 	}{
 		{
 			name: `
-POST /api/images #1 
-correct inputFilename
-got status 200
+GET /api/images/{filename} #1
+redirect mode
+got status 302
 			`,
-			inputFilename: "321",
+			inputFilename: "abc.jpg",
+			serveMode:     imaging.ServeRedirect,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filename string) {
-				r.EXPECT().GetImage(ctx, filename).Return("123", nil)
+				r.EXPECT().GetImage(ctx, filename, time.Hour).Return("https://minio/bucket/abc.jpg?X-Amz-Signature=1", nil)
+			},
+			expectedStatusCode: 302,
+			expectedHeaders: map[string]string{
+				"Location":      "https://minio/bucket/abc.jpg?X-Amz-Signature=1",
+				"Cache-Control": "private, max-age=1800",
 			},
-			expectedStatusCode:   200,
-			expectedResponseBody: "123",
 		},
 		{
 			name: `
-POST /api/images #2
-not correct return GetImage
+GET /api/images/{filename} #2
+redirect mode, image not exist
 got status 404
 			`,
-
+			inputFilename: "abc.jpg",
+			serveMode:     imaging.ServeRedirect,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filename string) {
-				r.EXPECT().GetImage(ctx, filename).Return("", errors.New("err"))
+				r.EXPECT().GetImage(ctx, filename, time.Hour).Return("", &storage.NotFoundError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 404,
 		},
+		{
+			name: `
+GET /api/images/{filename} #3
+stream mode
+got status 200
+			`,
+			inputFilename:      "abc.png",
+			serveMode:          imaging.ServeStream,
+			mockBehavior:       object,
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Type":  "image/png",
+				"ETag":          `"abc"`,
+				"Last-Modified": "Mon, 13 May 2024 12:00:00 GMT",
+				"Accept-Ranges": "bytes",
+			},
+			expectedResponseBody: "0123456789",
+		},
+		{
+			name: `
+GET /api/images/{filename} #4
+stream mode, range
+got status 206
+			`,
+			inputFilename:      "abc.png",
+			serveMode:          imaging.ServeStream,
+			requestHeaders:     map[string]string{"Range": "bytes=2-5"},
+			mockBehavior:       object,
+			expectedStatusCode: 206,
+			expectedHeaders: map[string]string{
+				"Content-Range": "bytes 2-5/10",
+			},
+			expectedResponseBody: "2345",
+		},
+		{
+			name: `
+GET /api/images/{filename} #5
+stream mode, etag matches
+got status 304
+			`,
+			inputFilename:      "abc.png",
+			serveMode:          imaging.ServeStream,
+			requestHeaders:     map[string]string{"If-None-Match": `"abc"`},
+			mockBehavior:       object,
+			expectedStatusCode: 304,
+		},
+		{
+			name: `
+GET /api/images/{filename} #6
+stream mode, not modified since
+got status 304
+			`,
+			inputFilename:      "abc.png",
+			serveMode:          imaging.ServeStream,
+			requestHeaders:     map[string]string{"If-Modified-Since": "Mon, 13 May 2024 12:00:00 GMT"},
+			mockBehavior:       object,
+			expectedStatusCode: 304,
+		},
+		{
+			name: `
+GET /api/images/{filename} #7
+stream mode, not correct return OpenImage
+got status 500
+			`,
+			inputFilename: "abc.png",
+			serveMode:     imaging.ServeStream,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filename string) {
+				r.EXPECT().OpenImage(ctx, filename).Return(nil, nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+GET /api/images/{filename} #8
+redirect mode, blob store without presigned URLs
+got status 200
+			`,
+			inputFilename: "abc.png",
+			serveMode:     imaging.ServeRedirect,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, filename string) {
+				r.EXPECT().GetImage(ctx, filename, time.Hour).Return("", fmt.Errorf("cannot presign image: %w", ostorage.ErrPresignNotSupported))
+				object(r, ctx, filename)
+			},
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Content-Type":  "image/png",
+				"Cache-Control": "public, max-age=31536000, immutable",
+			},
+			expectedResponseBody: "0123456789",
+		},
 	}
 
 	for _, test := range tests {
@@ -64,20 +188,25 @@ got status 404
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputFilename)
 
-			h := handlers.Init(repo, nil, "", 0)
+			images, err := imaging.Init(&config.Images{ImageMaxSize: 1, ImageMaxPixels: 1, ImageServeMode: test.serveMode, ImagePresignTTL: time.Hour})
+			require.NoError(t, err)
 
-			handler := func(w http.ResponseWriter, r *http.Request) {
-				h.Image(w, r)
-			}
+			h := handlers.Init(handlers.Deps{Storage: repo, Images: images})
 
 			req, err := http.NewRequest("GET", "/api/images/"+test.inputFilename, nil)
 			assert.NoError(t, err)
+			for key, value := range test.requestHeaders {
+				req.Header.Set(key, value)
+			}
 
 			rr := httptest.NewRecorder()
 
-			handler(rr, req)
+			h.Image(rr, req)
 
 			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			for key, value := range test.expectedHeaders {
+				assert.Equal(t, value, rr.Header().Get(key), key)
+			}
 			if test.expectedResponseBody != "" {
 				assert.Equal(t, test.expectedResponseBody, rr.Body.String())
 			}
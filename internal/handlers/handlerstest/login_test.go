@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"graduation/internal/config"
+	"graduation/internal/entity"
 	"graduation/internal/handlers"
+	"graduation/internal/hasher"
 	"graduation/internal/logger"
+	"graduation/internal/storage"
 	"graduation/internal/storage/mock"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +27,12 @@ func TestHandlerLogin(t *testing.T) {
 		logger.Panic(err.Error())
 	}
 
+	hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+	assert.NoError(t, err)
+
+	encoded, err := hash.Hash("password_1")
+	assert.NoError(t, err)
+
 	tests := []struct {
 		name      string
 		inputBody string
@@ -44,7 +53,8 @@ got status 200
 			inputLogin:    "user_1",
 			inputPassword: "password_1",
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password string) {
-				r.EXPECT().GetUser(ctx, login, password).Return(1, nil)
+				r.EXPECT().GetUser(ctx, login).Return(&entity.User{ID: 1, Password: encoded, Role: entity.RoleAttendee}, nil)
+				r.EXPECT().CreateSession(ctx, 1, gomock.Any(), gomock.Any()).Return(1, nil)
 			},
 			expectedStatusCode: 200,
 		},
@@ -61,17 +71,61 @@ got status 400
 		{
 			name: `
 POST /api/user/login #3
-not correct return setUser
+user not found
 got status 401
 			`,
 			inputBody:     `{"login": "user_1", "password": "password_1"}`,
 			inputLogin:    "user_1",
 			inputPassword: "password_1",
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password string) {
-				r.EXPECT().GetUser(ctx, login, password).Return(0, errors.New("err"))
+				r.EXPECT().GetUser(ctx, login).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 401,
 		},
+		{
+			name: `
+POST /api/user/login #4
+not correct password
+got status 401
+			`,
+			inputBody:     `{"login": "user_1", "password": "password_2"}`,
+			inputLogin:    "user_1",
+			inputPassword: "password_2",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password string) {
+				r.EXPECT().GetUser(ctx, login).Return(&entity.User{ID: 1, Password: encoded, Role: entity.RoleAttendee}, nil)
+			},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+POST /api/user/login #5
+plaintext password in db
+got status 200 and rehash
+			`,
+			inputBody:     `{"login": "user_1", "password": "password_1"}`,
+			inputLogin:    "user_1",
+			inputPassword: "password_1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password string) {
+				r.EXPECT().GetUser(ctx, login).Return(&entity.User{ID: 1, Password: hasher.PlainPrefix + password, Role: entity.RoleAttendee}, nil)
+				r.EXPECT().UpdatePassword(ctx, 1, gomock.Not(password)).Return(nil)
+				r.EXPECT().CreateSession(ctx, 1, gomock.Any(), gomock.Any()).Return(1, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/login #6
+not correct return getUser
+got status 500
+			`,
+			inputBody:     `{"login": "user_1", "password": "password_1"}`,
+			inputLogin:    "user_1",
+			inputPassword: "password_1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password string) {
+				r.EXPECT().GetUser(ctx, login).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
 	}
 
 	for _, test := range tests {
@@ -82,7 +136,7 @@ got status 401
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputLogin, test.inputPassword)
 
-			h := handlers.Init(repo, nil, "your_secret_key", time.Hour)
+			h := handlers.Init(handlers.Deps{Storage: repo, Hasher: hash, TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.Login(w, r)
@@ -0,0 +1,299 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/authorization"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"graduation/internal/templates"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerVerifyMail(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	token, err := authorization.BuildMailToken("your_secret_key", time.Hour, 5, "user@mail.ru")
+	assert.NoError(t, err)
+
+	expired, err := authorization.BuildMailToken("your_secret_key", -time.Hour, 5, "user@mail.ru")
+	assert.NoError(t, err)
+
+	access, err := authorization.BuildJWTString("your_secret_key", time.Hour, 5, "attendee", 1)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		inputToken         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+GET /api/user/verify #1
+correct token
+got status 200
+			`,
+			inputToken: token,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().VerifyMail(ctx, 5, "user@mail.ru").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+GET /api/user/verify #2
+token emty
+got status 400
+			`,
+			inputToken:         ``,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/user/verify #3
+token expired
+got status 401
+			`,
+			inputToken:         expired,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+GET /api/user/verify #4
+access token instead of mail token
+got status 401
+			`,
+			inputToken:         access,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+GET /api/user/verify #5
+mail changed
+got status 404
+			`,
+			inputToken: token,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().VerifyMail(ctx, 5, "user@mail.ru").Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+GET /api/user/verify #6
+not correct return VerifyMail
+got status 500
+			`,
+			inputToken: token,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().VerifyMail(ctx, 5, "user@mail.ru").Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo, TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
+
+			req, err := http.NewRequest("GET", "/api/user/verify?token="+url.QueryEscape(test.inputToken), nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			h.VerifyMail(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerVerifyResend(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		headerID           string
+		templates          bool
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/user/verify/resend #1
+mail already verified
+got status 409
+			`,
+			headerID: `5`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().MailVerified(ctx, 5).Return(true, nil)
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+POST /api/user/verify/resend #2
+not correct headerID
+got status 400
+			`,
+			headerID:           ``,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/user/verify/resend #3
+not correct return MailVerified
+got status 500
+			`,
+			headerID: `5`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().MailVerified(ctx, 5).Return(false, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+POST /api/user/verify/resend #4
+mail is not configured
+got status 500
+			`,
+			headerID: `5`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().MailVerified(ctx, 5).Return(false, nil)
+				r.EXPECT().GetMail(ctx, 5).Return("user@mail.ru", nil)
+				r.EXPECT().GetLocale(ctx, 5).Return("ru", nil)
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+POST /api/user/verify/resend #5
+verification email goes through the outbox
+got status 200
+			`,
+			headerID:  `5`,
+			templates: true,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().MailVerified(ctx, 5).Return(false, nil)
+				r.EXPECT().GetMail(ctx, 5).Return("user@mail.ru", nil)
+				r.EXPECT().GetLocale(ctx, 5).Return("ru", nil)
+				r.EXPECT().EnqueueMail(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, mails []entity.OutboxMail) error {
+					assert.Len(t, mails, 1)
+					assert.True(t, strings.HasPrefix(mails[0].Key, "verify:5:user@mail.ru:"))
+					assert.Equal(t, entity.ChannelEmail, mails[0].Channel)
+					assert.Equal(t, "user@mail.ru", mails[0].Address)
+					assert.Contains(t, mails[0].Text, "https://example.com/api/user/verify?token=")
+					return nil
+				})
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/verify/resend #6
+not correct return EnqueueMail
+got status 500
+			`,
+			headerID:  `5`,
+			templates: true,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().MailVerified(ctx, 5).Return(false, nil)
+				r.EXPECT().GetMail(ctx, 5).Return("user@mail.ru", nil)
+				r.EXPECT().GetLocale(ctx, 5).Return("ru", nil)
+				r.EXPECT().EnqueueMail(ctx, gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	tmpl, err := templates.New("")
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			deps := handlers.Deps{Storage: repo, BaseURL: "https://example.com", TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour}
+			if test.templates {
+				deps.Templates = tmpl
+			}
+			h := handlers.Init(deps)
+
+			req, err := http.NewRequest("POST", "/api/user/verify/resend", nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.VerifyResend(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerVerifyResendLimit(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock.NewMockStorage(c)
+	repo.EXPECT().MailVerified(gomock.Any(), 5).Return(true, nil).Times(5)
+
+	h := handlers.Init(handlers.Deps{Storage: repo, TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
+	resend := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/api/user/verify/resend", nil)
+		assert.NoError(t, err)
+		req.Header.Set("User_id", "5")
+
+		rr := httptest.NewRecorder()
+		h.VerifyResend(rr, req)
+		return rr
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, 409, resend().Code)
+	}
+	rr := resend()
+	assert.Equal(t, 429, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+}
@@ -0,0 +1,218 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerOutboxList(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	created := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		inputQuery         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: `
+GET /api/admin/outbox #1
+dead mails by default
+got status 200
+			`,
+			inputQuery: ``,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().OutboxMails(ctx, entity.OutboxDead, 100, 0).Return([]entity.OutboxMail{{
+					ID:            3,
+					Key:           "reminder:1:2:180",
+					UserID:        2,
+					EventID:       1,
+					Channel:       entity.ChannelEmail,
+					Address:       "user@mail.ru",
+					Subject:       "Event in 3 hours",
+					Status:        entity.OutboxDead,
+					Attempts:      8,
+					NextAttemptAt: created,
+					LastError:     "failed to send mail",
+					CreatedAt:     created,
+				}}, 1, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody: `{"page":1,"pages":1,"total":1,"mails":[{"id":"Mw==","key":"reminder:1:2:180","user_id":"Mg==","event_id":"MQ==",
+"channel":"email","address":"user@mail.ru","subject":"Event in 3 hours","status":"dead","attempts":8,"next_attempt_at":"2024-03-18T12:00:00Z",
+"last_error":"failed to send mail","created_at":"2024-03-18T12:00:00Z"}]}`,
+		},
+		{
+			name: `
+GET /api/admin/outbox #2
+pending mails, second page
+got status 200
+			`,
+			inputQuery: `?status=pending&limit=10&page=2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().OutboxMails(ctx, entity.OutboxPending, 10, 10).Return(nil, 11, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `{"page":2,"pages":2,"total":11,"mails":[]}`,
+		},
+		{
+			name: `
+GET /api/admin/outbox #3
+not correct status
+got status 400
+			`,
+			inputQuery:         `?status=lost`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/admin/outbox #4
+not correct limit
+got status 400
+			`,
+			inputQuery:         `?limit=1000`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/admin/outbox #5
+not correct return OutboxMails
+got status 500
+			`,
+			inputQuery: ``,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().OutboxMails(ctx, entity.OutboxDead, 100, 0).Return(nil, 0, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("GET", "/api/admin/outbox"+test.inputQuery, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			h.OutboxList(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerOutboxReplay(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/admin/outbox/{id}/replay #1
+dead mail
+got status 200
+			`,
+			inputID: `Mw==`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().ReplayMail(ctx, 3).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/admin/outbox/{id}/replay #2
+not correct id
+got status 400
+			`,
+			inputID:            `-`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/admin/outbox/{id}/replay #3
+mail not exist
+got status 404
+			`,
+			inputID: `Mw==`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().ReplayMail(ctx, 3).Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+POST /api/admin/outbox/{id}/replay #4
+mail not dead
+got status 409
+			`,
+			inputID: `Mw==`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().ReplayMail(ctx, 3).Return(&storage.ConflictError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 409,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("POST", "/api/admin/outbox/"+test.inputID+"/replay", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			h.OutboxReplay(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/authorization"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/hasher"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"graduation/internal/templates"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerPasswordForgot(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		templates          bool
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/user/password/forgot #1
+unknown login
+got status 200
+			`,
+			inputBody: `{"login": "user_1"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetUser(ctx, "user_1").Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/password/forgot #2
+not correct input body
+got status 400
+			`,
+			inputBody:          `{}`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/user/password/forgot #3
+not correct return GetUser
+got status 200
+			`,
+			inputBody: `{"login": "user_1"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetUser(ctx, "user_1").Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/password/forgot #4
+not correct return CreatePasswordReset
+got status 200
+			`,
+			inputBody: `{"login": "user_1"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetUser(ctx, "user_1").Return(&entity.User{ID: 5, Login: "user_1", Mail: "user@mail.ru"}, nil)
+				r.EXPECT().CreatePasswordReset(ctx, 5, gomock.Any(), gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/password/forgot #5
+mail is not configured
+got status 200
+			`,
+			inputBody: `{"login": "user_1"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetUser(ctx, "user_1").Return(&entity.User{ID: 5, Login: "user_1", Mail: "user@mail.ru"}, nil)
+				r.EXPECT().CreatePasswordReset(ctx, 5, gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetLocale(ctx, 5).Return("en", nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/password/forgot #6
+reset email goes through the outbox
+got status 200
+			`,
+			inputBody: `{"login": "user_1"}`,
+			templates: true,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetUser(ctx, "user_1").Return(&entity.User{ID: 5, Login: "user_1", Mail: "user@mail.ru"}, nil)
+				r.EXPECT().CreatePasswordReset(ctx, 5, gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetLocale(ctx, 5).Return("en", nil)
+				r.EXPECT().EnqueueMail(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, mails []entity.OutboxMail) error {
+					assert.Len(t, mails, 1)
+					assert.True(t, strings.HasPrefix(mails[0].Key, "reset:"))
+					assert.Equal(t, 5, mails[0].UserID)
+					assert.Equal(t, entity.ChannelEmail, mails[0].Channel)
+					assert.Equal(t, "user@mail.ru", mails[0].Address)
+					assert.Contains(t, mails[0].Text, "https://example.com/password/reset?token=")
+					assert.Empty(t, mails[0].Token)
+					return nil
+				})
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/password/forgot #7
+mail server is down
+got status 200
+			`,
+			inputBody: `{"login": "user_1"}`,
+			templates: true,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetUser(ctx, "user_1").Return(&entity.User{ID: 5, Login: "user_1", Mail: "user@mail.ru"}, nil)
+				r.EXPECT().CreatePasswordReset(ctx, 5, gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetLocale(ctx, 5).Return("en", nil)
+				r.EXPECT().EnqueueMail(ctx, gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 200,
+		},
+	}
+
+	tmpl, err := templates.New("")
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			deps := handlers.Deps{Storage: repo, BaseURL: "https://example.com"}
+			if test.templates {
+				deps.Templates = tmpl
+			}
+			h := handlers.Init(deps)
+
+			req, err := http.NewRequest("POST", "/api/user/password/forgot", strings.NewReader(test.inputBody))
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			start := time.Now()
+			h.PasswordForgot(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			// known and unknown logins take the same time
+			if rr.Code == 200 {
+				assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestHandlerPasswordForgotLimit(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock.NewMockStorage(c)
+	repo.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, &storage.NotFoundError{Err: errors.New("err")}).AnyTimes()
+
+	h := handlers.Init(handlers.Deps{Storage: repo})
+	forgot := func(login, addr string) int {
+		req, err := http.NewRequest("POST", "/api/user/password/forgot", strings.NewReader(`{"login": "`+login+`"}`))
+		assert.NoError(t, err)
+		req.RemoteAddr = addr
+
+		rr := httptest.NewRecorder()
+		h.PasswordForgot(rr, req)
+		return rr.Code
+	}
+
+	// five emails per login
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, 200, forgot("user_1", "10.0.0.1:1000"))
+	}
+	assert.Equal(t, 429, forgot("user_1", "10.0.0.2:1000"))
+
+	// twenty per address
+	for i := 0; i < 15; i++ {
+		assert.Equal(t, 200, forgot("user_"+strconv.Itoa(i+2), "10.0.0.1:1000"))
+	}
+	assert.Equal(t, 429, forgot("other", "10.0.0.1:2000"))
+	assert.Equal(t, 200, forgot("other", "10.0.0.3:1000"))
+}
+
+func TestHandlerPasswordReset(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+	assert.NoError(t, err)
+
+	tokenHash := authorization.HashOpaqueToken("code")
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/user/password/reset #1
+correct token
+got status 200
+			`,
+			inputBody: `{"token": "code", "password": "password_2"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().ResetPassword(ctx, tokenHash, gomock.Not("password_2")).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/password/reset #2
+password emty
+got status 400
+			`,
+			inputBody:          `{"token": "code"}`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/user/password/reset #3
+token used or expired
+got status 401
+			`,
+			inputBody: `{"token": "code", "password": "password_2"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().ResetPassword(ctx, tokenHash, gomock.Any()).Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+POST /api/user/password/reset #4
+not correct return ResetPassword
+got status 500
+			`,
+			inputBody: `{"token": "code", "password": "password_2"}`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().ResetPassword(ctx, tokenHash, gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Hasher: hash})
+
+			req, err := http.NewRequest("POST", "/api/user/password/reset", strings.NewReader(test.inputBody))
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			h.PasswordReset(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"graduation/internal/config"
+	"graduation/internal/entity"
 	"graduation/internal/handlers"
+	"graduation/internal/hasher"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
 	"graduation/internal/storage"
 	"graduation/internal/storage/mock"
+	"graduation/internal/templates"
 	"strings"
 	"time"
 
@@ -26,6 +30,9 @@ func TestHandlerRegister(t *testing.T) {
 		logger.Panic(err.Error())
 	}
 
+	hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+	assert.NoError(t, err)
+
 	tests := []struct {
 		name      string
 		inputBody string
@@ -49,7 +56,8 @@ got status 200
 			inputPassword: "password_1",
 			inputmail:     "mail_1@mail.ru",
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password, mail string) {
-				r.EXPECT().SetUser(ctx, login, password, mail).Return(1, nil)
+				r.EXPECT().SetUser(ctx, login, gomock.Not(password), mail, entity.RoleAttendee).Return(1, nil)
+				r.EXPECT().CreateSession(ctx, 1, gomock.Any(), gomock.Any()).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"id":1}`,
@@ -68,16 +76,16 @@ got status 400
 			name: `
 POST /api/user/register #3
 not correct return setUser
-got status 400
+got status 500
 			`,
 			inputBody:     `{"login": "user_1", "password": "password_1", "mail": "mail_1@mail.ru"}`,
 			inputLogin:    "user_1",
 			inputPassword: "password_1",
 			inputmail:     "mail_1@mail.ru",
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password, mail string) {
-				r.EXPECT().SetUser(ctx, login, password, mail).Return(0, errors.New("err"))
+				r.EXPECT().SetUser(ctx, login, gomock.Not(password), mail, entity.RoleAttendee).Return(0, errors.New("err"))
 			},
-			expectedStatusCode:   400,
+			expectedStatusCode:   500,
 			expectedResponseBody: `{"id":1}`,
 		},
 		{
@@ -91,11 +99,53 @@ got status 409
 			inputPassword: "password_1",
 			inputmail:     "mail_1@mail.ru",
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password, mail string) {
-				r.EXPECT().SetUser(ctx, login, password, mail).Return(0, &storage.RepError{Err: errors.New("err"), Repetition: true})
+				r.EXPECT().SetUser(ctx, login, gomock.Not(password), mail, entity.RoleAttendee).Return(0, &storage.ConflictError{Err: errors.New("err")})
 			},
 			expectedStatusCode:   409,
 			expectedResponseBody: `{"id":1}`,
 		},
+		{
+			name: `
+POST /api/user/register #5
+organizer role on register is ignored
+got status 200
+			`,
+			inputBody:     `{"login": "user_1", "password": "password_1", "mail": "mail_1@mail.ru", "role": "organizer"}`,
+			inputLogin:    "user_1",
+			inputPassword: "password_1",
+			inputmail:     "mail_1@mail.ru",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password, mail string) {
+				r.EXPECT().SetUser(ctx, login, gomock.Not(password), mail, entity.RoleAttendee).Return(1, nil)
+				r.EXPECT().CreateSession(ctx, 1, gomock.Any(), gomock.Any()).Return(1, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/register #6
+admin role on register is ignored
+got status 200
+			`,
+			inputBody:     `{"login": "user_1", "password": "password_1", "mail": "mail_1@mail.ru", "role": "admin"}`,
+			inputLogin:    "user_1",
+			inputPassword: "password_1",
+			inputmail:     "mail_1@mail.ru",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, login, password, mail string) {
+				r.EXPECT().SetUser(ctx, login, gomock.Not(password), mail, entity.RoleAttendee).Return(1, nil)
+				r.EXPECT().CreateSession(ctx, 1, gomock.Any(), gomock.Any()).Return(1, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/register #7
+not correct mail
+got status 400
+			`,
+			inputBody:          `{"login": "user_1", "password": "password_1", "mail": "mail_1"}`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, login, password, mail string) {},
+			expectedStatusCode: 400,
+		},
 	}
 
 	for _, test := range tests {
@@ -106,7 +156,7 @@ got status 409
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputLogin, test.inputPassword, test.inputmail)
 
-			h := handlers.Init(repo, nil, "your_secret_key", time.Hour)
+			h := handlers.Init(handlers.Deps{Storage: repo, Hasher: hash, TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.Register(w, r)
@@ -127,3 +177,81 @@ got status 409
 		})
 	}
 }
+
+func TestHandlerRegisterProblem(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	h := handlers.Init(handlers.Deps{Storage: mock.NewMockStorage(c), TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
+
+	req, err := http.NewRequest("POST", "/api/user/register", strings.NewReader(`{"login": "", "password": "password_1", "mail": "mail_1"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.Register(rr, req)
+
+	assert.Equal(t, 400, rr.Code)
+	assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "request has invalid fields",
+		"errors": [
+			{"field": "login", "message": "is required"},
+			{"field": "mail", "message": "must be an email address"}
+		]
+	}`, rr.Body.String())
+}
+
+func TestHandlerRegisterQueuesVerification(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+	assert.NoError(t, err)
+	tmpl, err := templates.New("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		enqueueErr error
+	}{
+		{name: "queued"},
+		// the account works without the mail, the user can ask to resend it
+		{name: "outbox is down", enqueueErr: errors.New("err")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			ctx := context.Background()
+			repo.EXPECT().SetUser(ctx, "user_1", gomock.Any(), "mail_1@mail.ru", entity.RoleAttendee).Return(1, nil)
+			repo.EXPECT().CreateSession(ctx, 1, gomock.Any(), gomock.Any()).Return(1, nil)
+			repo.EXPECT().EnqueueMail(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, mails []entity.OutboxMail) error {
+				assert.Len(t, mails, 1)
+				assert.True(t, strings.HasPrefix(mails[0].Key, "verify:1:mail_1@mail.ru:"))
+				assert.Equal(t, "mail_1@mail.ru", mails[0].Address)
+				return test.enqueueErr
+			})
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Hasher: hash, Templates: tmpl, BaseURL: "https://example.com", TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
+
+			req, err := http.NewRequest("POST", "/api/user/register", strings.NewReader(`{"login": "user_1", "password": "password_1", "mail": "mail_1@mail.ru"}`))
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			h.Register(rr, req)
+
+			assert.Equal(t, 200, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerRemindersGet(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		headerID           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: `
+GET /api/event/{id}/reminders #1
+user follows event schedule
+got status 200
+			`,
+			inputID:  `MQ==`,
+			headerID: `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().EventReminders(ctx, 1).Return([]int{60, 1440}, nil)
+				r.EXPECT().UserReminders(ctx, 1, 2).Return(nil, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `{"event":[60,1440],"user":null}`,
+		},
+		{
+			name: `
+GET /api/event/{id}/reminders #2
+user opted out
+got status 200
+			`,
+			inputID:  `MQ==`,
+			headerID: `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().EventReminders(ctx, 1).Return([]int{180}, nil)
+				r.EXPECT().UserReminders(ctx, 1, 2).Return([]int{}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `{"event":[180],"user":[]}`,
+		},
+		{
+			name: `
+GET /api/event/{id}/reminders #3
+event not exist
+got status 404
+			`,
+			inputID:  `MQ==`,
+			headerID: `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+GET /api/event/{id}/reminders #4
+not correct id
+got status 400
+			`,
+			inputID:            `-`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("GET", "/api/event/"+test.inputID+"/reminders", nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.RemindersGet(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerEventRemindersSet(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputBody          string
+		headerID           string
+		headerRole         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PUT /api/event/{id}/reminders #1
+organizer sets week, day and hour, sorted and deduped
+got status 200
+			`,
+			inputID:    `MQ==`,
+			inputBody:  `{"reminders":[10080,60,1440,60]}`,
+			headerID:   `5`,
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().SetEventReminders(ctx, 1, []int{60, 1440, 10080}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/event/{id}/reminders #2
+admin turns reminders off
+got status 200
+			`,
+			inputID:    `MQ==`,
+			inputBody:  `{"reminders":[]}`,
+			headerID:   `9`,
+			headerRole: entity.RoleAdmin,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+				r.EXPECT().SetEventReminders(ctx, 1, []int{}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/event/{id}/reminders #3
+not organizer of event
+got status 403
+			`,
+			inputID:    `MQ==`,
+			inputBody:  `{"reminders":[60]}`,
+			headerID:   `6`,
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(&entity.Event{ID: 1, UserID: 5}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+PUT /api/event/{id}/reminders #4
+offset out of range
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"reminders":[0]}`,
+			headerID:           `5`,
+			headerRole:         entity.RoleOrganizer,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/event/{id}/reminders #5
+too many reminders
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"reminders":[1,2,3,4,5,6]}`,
+			headerID:           `5`,
+			headerRole:         entity.RoleOrganizer,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/event/{id}/reminders #6
+reminders missing
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{}`,
+			headerID:           `5`,
+			headerRole:         entity.RoleOrganizer,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/event/{id}/reminders #7
+event not exist
+got status 404
+			`,
+			inputID:    `MQ==`,
+			inputBody:  `{"reminders":[60]}`,
+			headerID:   `5`,
+			headerRole: entity.RoleOrganizer,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetEvent(ctx, 1).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("PUT", "/api/event/"+test.inputID+"/reminders", bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
+
+			rr := httptest.NewRecorder()
+
+			h.EventRemindersSet(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerUserRemindersSet(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputBody          string
+		headerID           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PUT /api/user/reminders/{id} #1
+attendee overrides schedule
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"reminders":[30]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserReminders(ctx, 1, 2, []int{30}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/reminders/{id} #2
+attendee opts out
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"reminders":[]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserReminders(ctx, 1, 2, []int{}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/reminders/{id} #3
+user not registered to event
+got status 404
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"reminders":[30]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserReminders(ctx, 1, 2, []int{30}).Return(&storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+PUT /api/user/reminders/{id} #4
+offset longer than 30 days
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"reminders":[43201]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/reminders/{id} #5
+not correct return SetUserReminders
+got status 500
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"reminders":[30]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserReminders(ctx, 1, 2, []int{30}).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("PUT", "/api/user/reminders/"+test.inputID, bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.UserRemindersSet(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
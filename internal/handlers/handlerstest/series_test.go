@@ -0,0 +1,435 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	seriesStart = time.Date(2030, 3, 4, 10, 0, 0, 0, time.UTC)
+	series      = entity.Series{
+		ID:              1,
+		UserID:          1,
+		Title:           "title",
+		Description:     "description",
+		Place:           "place",
+		MaxParticipants: 10,
+		Start:           seriesStart,
+		RRule:           "FREQ=WEEKLY;COUNT=10",
+		Active:          true,
+	}
+	occurrence = entity.Event{
+		ID:              2,
+		UserID:          1,
+		Title:           "title",
+		Description:     "description",
+		Place:           "place",
+		Participants:    5,
+		MaxParticipants: 10,
+		Date:            seriesStart.AddDate(0, 0, 7),
+		Active:          true,
+		SeriesID:        1,
+		Occurrence:      seriesStart.AddDate(0, 0, 7),
+	}
+)
+
+func TestHandlerSeriesCreat(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		headerID           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: `
+POST /api/event/series #1
+correct body
+got status 200
+			`,
+			inputBody: `{"title":"title","description":"description","place":"place","participants":10,"date":"2030-03-04 10:00","rrule":"RRULE:FREQ=WEEKLY;COUNT=10"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().CreateSeries(ctx, &entity.Series{
+					UserID:          1,
+					Title:           "title",
+					Description:     "description",
+					Place:           "place",
+					MaxParticipants: 10,
+					Start:           seriesStart,
+					RRule:           "FREQ=WEEKLY;COUNT=10",
+				}, gomock.Any()).Return([]int{1, 2}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `{"id":"MA==","events":["MQ==","Mg=="]}`,
+		},
+		{
+			name: `
+POST /api/event/series #2
+not correct rrule
+got status 400
+			`,
+			inputBody:          `{"title":"title","date":"2030-03-04 10:00","rrule":"FREQ=HOURLY"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/series #3
+not correct date
+got status 400
+			`,
+			inputBody:          `{"title":"title","date":"04.03.2030","rrule":"FREQ=WEEKLY"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/series #4
+not correct return CreateSeries
+got status 500
+			`,
+			inputBody: `{"title":"title","place":"hall","participants":10,"date":"2030-03-04 10:00","rrule":"FREQ=WEEKLY"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().CreateSeries(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("POST", "/api/event/series", bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.SeriesCreat(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerSeriesCancel(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputBody          string
+		headerID           string
+		headerRole         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/event/series/{id}/cancel #1
+cancel this occurrence
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"this"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				r.EXPECT().GetEvent(ctx, 2).Return(&occurrence, nil)
+				r.EXPECT().CancelOccurrence(ctx, 1, 2).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #2
+cancel this and following occurrences
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"following"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				r.EXPECT().GetEvent(ctx, 2).Return(&occurrence, nil)
+				r.EXPECT().TruncateSeries(ctx, 1, occurrence.Occurrence, "FREQ=WEEKLY;UNTIL=20300311T095959Z").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #3
+not correct scope
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"event":"Mg==","scope":"all"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #4
+series not exist
+got status 404
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"this"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #5
+series of other organizer
+got status 403
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"this"}`,
+			headerID:  "2",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #6
+event not in series
+got status 404
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"this"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				single := occurrence
+				single.SeriesID = 0
+				r.EXPECT().GetEvent(ctx, 2).Return(&single, nil)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #7
+admin cancel occurrence of other organizer
+got status 200
+			`,
+			inputID:    `MQ==`,
+			inputBody:  `{"event":"Mg==","scope":"this"}`,
+			headerID:   "2",
+			headerRole: "admin",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				r.EXPECT().GetEvent(ctx, 2).Return(&occurrence, nil)
+				r.EXPECT().CancelOccurrence(ctx, 1, 2).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/event/series/{id}/cancel #8
+occurrence has attendees
+got status 409
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"following"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				r.EXPECT().GetEvent(ctx, 2).Return(&occurrence, nil)
+				r.EXPECT().TruncateSeries(ctx, 1, occurrence.Occurrence, gomock.Any()).Return(&storage.ConflictError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 409,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("POST", "/api/event/series/"+test.inputID+"/cancel", bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("User_role", test.headerRole)
+
+			rr := httptest.NewRecorder()
+
+			h.SeriesCancel(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestHandlerSeriesUpdate(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputBody          string
+		headerID           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PATCH /api/event/series/{id} #1
+edit this occurrence
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"this","place":"new place"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				event := occurrence
+				r.EXPECT().GetEvent(ctx, 2).Return(&event, nil)
+				updated := occurrence
+				updated.Place = "new place"
+				r.EXPECT().UpdateEvent(ctx, 1, &updated, []entity.EventChange{
+					{Field: "place", Old: "place", New: "new place"},
+				}, gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PATCH /api/event/series/{id} #2
+edit this and following occurrences, move them by a day
+got status 200
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"following","participants":20,"date":"2030-03-12 10:00"}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				event := occurrence
+				r.EXPECT().GetEvent(ctx, 2).Return(&event, nil)
+				next := entity.Series{
+					UserID:          1,
+					Title:           "title",
+					Description:     "description",
+					Place:           "place",
+					MaxParticipants: 20,
+					Start:           occurrence.Occurrence.Add(24 * time.Hour),
+					RRule:           "FREQ=WEEKLY;COUNT=9",
+				}
+				r.EXPECT().SplitSeries(ctx, 1, 1, occurrence.Occurrence, "FREQ=WEEKLY;UNTIL=20300311T095959Z", &next, []entity.EventChange{
+					{Field: "participants", Old: "10", New: "20"},
+					{Field: "date", Old: "2030-03-11 10:00", New: "2030-03-12 10:00"},
+				}, 24*time.Hour, gomock.Any()).Return([]int{2, 3}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PATCH /api/event/series/{id} #3
+new capacity less than participants of a following occurrence
+got status 409
+			`,
+			inputID:   `MQ==`,
+			inputBody: `{"event":"Mg==","scope":"following","participants":6}`,
+			headerID:  "1",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().GetSeries(ctx, 1).Return(&series, nil)
+				event := occurrence
+				r.EXPECT().GetEvent(ctx, 2).Return(&event, nil)
+				r.EXPECT().SplitSeries(ctx, 1, 1, occurrence.Occurrence, gomock.Any(), gomock.Any(), gomock.Any(), time.Duration(0), gomock.Any()).
+					Return(nil, &storage.ConflictError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+PATCH /api/event/series/{id} #4
+not correct event id
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"event":"","scope":"following"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("PATCH", "/api/event/series/"+test.inputID, bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.SeriesUpdate(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
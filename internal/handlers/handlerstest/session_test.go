@@ -0,0 +1,213 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/authorization"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerRefresh(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, refreshHash string)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		cookie             *http.Cookie
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/user/refresh #1 
+correct refresh cookie
+got status 200
+			`,
+			cookie: &http.Cookie{Name: "Refresh", Value: "refresh_1"},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, refreshHash string) {
+				r.EXPECT().RefreshSession(ctx, refreshHash, gomock.Not(refreshHash), gomock.Any()).
+					Return(&entity.Session{ID: 1, UserID: 1, Role: entity.RoleAttendee}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/refresh #2
+not refresh cookie
+got status 401
+			`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, refreshHash string) {},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+POST /api/user/refresh #3
+revoked session
+got status 401
+			`,
+			cookie: &http.Cookie{Name: "Refresh", Value: "refresh_1"},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, refreshHash string) {
+				r.EXPECT().RefreshSession(ctx, refreshHash, gomock.Any(), gomock.Any()).
+					Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 401,
+		},
+		{
+			name: `
+POST /api/user/refresh #4
+not correct return RefreshSession
+got status 500
+			`,
+			cookie: &http.Cookie{Name: "Refresh", Value: "refresh_1"},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, refreshHash string) {
+				r.EXPECT().RefreshSession(ctx, refreshHash, gomock.Any(), gomock.Any()).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), authorization.HashRefreshToken("refresh_1"))
+
+			h := handlers.Init(handlers.Deps{Storage: repo, TokenSecretKey: "your_secret_key", TokenEXP: time.Hour, RefreshEXP: time.Hour})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.Refresh(w, r)
+			}
+
+			req, err := http.NewRequest("POST", "/api/user/refresh", nil)
+			assert.NoError(t, err)
+			if test.cookie != nil {
+				req.AddCookie(test.cookie)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if rr.Code == 200 {
+				assert.Len(t, rr.Result().Cookies(), 2)
+			}
+		})
+	}
+}
+
+func TestHandlerLogout(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		all                bool
+		headerID           string
+		headerSessionID    string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/user/logout #1 
+correct session
+got status 200
+			`,
+			headerID:        "1",
+			headerSessionID: "2",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().RevokeSession(ctx, 2).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/logout #2
+not correct session
+got status 400
+			`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/user/logout/all #3
+correct user
+got status 200
+			`,
+			all:             true,
+			headerID:        "1",
+			headerSessionID: "2",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().RevokeUserSessions(ctx, 1).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/user/logout/all #4
+not correct return RevokeUserSessions
+got status 500
+			`,
+			all:             true,
+			headerID:        "1",
+			headerSessionID: "2",
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().RevokeUserSessions(ctx, 1).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if test.all {
+					h.LogoutAll(w, r)
+				} else {
+					h.Logout(w, r)
+				}
+			}
+
+			req, err := http.NewRequest("POST", "/api/user/logout", nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+			req.Header.Set("Session_id", test.headerSessionID)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"graduation/internal/bundle"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"graduation/internal/ticket"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerTicketBundle(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, eventID int)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	keyring, err := ticket.NewKeyring(&ticket.Key{ID: "k1", Method: jwt.SigningMethodEdDSA, Private: private, Public: public})
+	assert.NoError(t, err)
+
+	tick := ticket.NewWithKeyring("", keyring)
+
+	issued := entity.Ticket{UserID: 2, EventID: 1, Exp: 1, Status: true}
+	assert.NoError(t, tick.Generate(&issued))
+
+	tests := []struct {
+		name               string
+		inputID            string
+		headerID           string
+		tick               *ticket.TicketToken
+		inputEventID       int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedTickets    []string
+	}{
+		{
+			name: `
+GET /api/event/bundle/{id} #1 
+correct inputID, headerID
+got status 200
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			tick:         tick,
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+				r.EXPECT().EventTickets(ctx, eventID).Return([]entity.Ticket{issued}, nil)
+				r.EXPECT().RevokedTickets(ctx, eventID).Return(nil, nil)
+			},
+			expectedStatusCode: 200,
+			expectedTickets:    []string{ticket.ID(issued.Token)},
+		},
+		{
+			name: `
+GET /api/event/bundle/{id} #2
+not correct inputID
+got status 400
+			`,
+			inputID:            ``,
+			headerID:           "1",
+			tick:               tick,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/event/bundle/{id} #3
+not correct headerID
+got status 400
+			`,
+			inputID:            `MQ==`,
+			headerID:           "",
+			tick:               tick,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/event/bundle/{id} #4
+event not exist
+got status 404
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			tick:         tick,
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+GET /api/event/bundle/{id} #5
+user not organizer of event
+got status 403
+			`,
+			inputID:      `MQ==`,
+			headerID:     "3",
+			tick:         tick,
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+GET /api/event/bundle/{id} #6
+not correct return EventTickets
+got status 500
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			tick:         tick,
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+				r.EXPECT().EventTickets(ctx, eventID).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+GET /api/event/bundle/{id} #7
+keyring not configured
+got status 501
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			tick:         ticket.NewWithKeyring("123", nil),
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+				r.EXPECT().EventTickets(ctx, eventID).Return(nil, nil)
+				r.EXPECT().RevokedTickets(ctx, eventID).Return(nil, nil)
+			},
+			expectedStatusCode: 501,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), test.inputEventID)
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Ticket: test.tick})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.TicketBundle(w, r)
+			}
+
+			req, err := http.NewRequest("GET", "/api/event/bundle/"+test.inputID, nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedTickets != nil {
+				b, err := bundle.Parse(rr.Body.String(), test.tick.JWKS())
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedTickets, b.Tickets)
+			}
+		})
+	}
+}
+
+func TestHandlerCheckinMerge(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, eventID int)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	scannedAt := time.Date(2024, 2, 1, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		inputID            string
+		inputBody          string
+		headerID           string
+		inputEventID       int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+POST /api/event/checkins/{id} #1 
+correct log
+got status 200
+			`,
+			inputID:      `MQ==`,
+			inputBody:    `{"token":"a","scanned_at":"2024-02-01T18:00:00Z"}` + "\n\n" + `{"token":"b","scanned_at":"2024-02-01T18:00:00Z"}` + "\n",
+			headerID:     "1",
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+				r.EXPECT().MergeCheckins(ctx, eventID, 1, []entity.Checkin{
+					{Token: "a", ScannedAt: scannedAt},
+					{Token: "b", ScannedAt: scannedAt},
+				}).Return(1, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+POST /api/event/checkins/{id} #2
+not correct log line
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputBody:          `{"token":"a"}`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/checkins/{id} #3
+not correct inputID
+got status 400
+			`,
+			inputID:            ``,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+POST /api/event/checkins/{id} #4
+user not organizer of event
+got status 403
+			`,
+			inputID:      `MQ==`,
+			headerID:     "3",
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+POST /api/event/checkins/{id} #5
+event not exist
+got status 404
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+POST /api/event/checkins/{id} #6
+not correct return MergeCheckins
+got status 500
+			`,
+			inputID:      `MQ==`,
+			inputBody:    `{"token":"a","scanned_at":"2024-02-01T18:00:00Z"}`,
+			headerID:     "1",
+			inputEventID: 1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().GetEvent(ctx, eventID).Return(&entity.Event{ID: eventID, UserID: 1}, nil)
+				r.EXPECT().MergeCheckins(ctx, eventID, 1, gomock.Any()).Return(0, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), test.inputEventID)
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.CheckinMerge(w, r)
+			}
+
+			req, err := http.NewRequest("POST", "/api/event/checkins/"+test.inputID, bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
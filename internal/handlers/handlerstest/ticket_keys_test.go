@@ -0,0 +1,95 @@
+package handlerstest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"graduation/internal/config"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage/mock"
+	"graduation/internal/ticket"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerTicketKeys(t *testing.T) {
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	keyring, err := ticket.NewKeyring(
+		&ticket.Key{ID: "new", Method: jwt.SigningMethodEdDSA, Private: private, Public: public},
+		&ticket.Key{ID: "old", Method: jwt.SigningMethodEdDSA, Public: public},
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		tick               *ticket.TicketToken
+		expectedKids       []string
+		expectedStatusCode int
+	}{
+		{
+			name: `
+GET /.well-known/ticket-keys #1 
+keyring with signing and retired key
+got status 200
+			`,
+			tick:               ticket.NewWithKeyring("", keyring),
+			expectedKids:       []string{"new", "old"},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+GET /.well-known/ticket-keys #2 
+without keyring
+got status 200
+			`,
+			tick:               ticket.NewWithKeyring("123", nil),
+			expectedKids:       []string{},
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Ticket: test.tick})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.TicketKeys(w, r)
+			}
+
+			req, err := http.NewRequest("GET", "/.well-known/ticket-keys", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+
+			var jwks ticket.JWKS
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &jwks))
+
+			kids := []string{}
+			for _, key := range jwks.Keys {
+				kids = append(kids, key.Kid)
+			}
+			assert.Equal(t, test.expectedKids, kids)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package handlerstest
+
+import (
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/qr"
+	"graduation/internal/storage"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerTicketQR(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context, userID, eventID int)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	qrCode, err := qr.Init(&config.QRCode{QRSize: 256, QRLevel: "M"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name                string
+		inputID             string
+		inputQuery          string
+		headerID            string
+		inputEventID        int
+		inputUserID         int
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #1 
+correct inputID, headerID
+got status 200
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetTicket(ctx, userID, eventID).Return(&entity.Ticket{UserID: userID, EventID: eventID, Token: "token", Status: true}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "image/png",
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #2 
+correct format svg, size, level
+got status 200
+			`,
+			inputID:      `MQ==`,
+			inputQuery:   `?format=svg&size=128&level=H`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetTicket(ctx, userID, eventID).Return(&entity.Ticket{UserID: userID, EventID: eventID, Token: "token", Status: true}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "image/svg+xml",
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #3
+not correct inputID
+got status 400
+			`,
+			inputID:            ``,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #4
+not correct headerID
+got status 400
+			`,
+			inputID:            `MQ==`,
+			headerID:           "",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #5
+not correct size
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputQuery:         `?size=5000`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #6
+not correct level
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputQuery:         `?level=X`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #7
+not correct format
+got status 400
+			`,
+			inputID:            `MQ==`,
+			inputQuery:         `?format=gif`,
+			headerID:           "1",
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #8
+not correct return GetTicket (ticket not exist)
+got status 404
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetTicket(ctx, userID, eventID).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #9
+not correct return GetTicket
+got status 500
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetTicket(ctx, userID, eventID).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+GET /api/user/tickets/{id}/qr #10
+ticket is not active
+got status 409
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, userID, eventID int) {
+				r.EXPECT().GetTicket(ctx, userID, eventID).Return(&entity.Ticket{UserID: userID, EventID: eventID, Token: "token"}, nil)
+			},
+			expectedStatusCode: 409,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background(), test.inputUserID, test.inputEventID)
+
+			h := handlers.Init(handlers.Deps{Storage: repo, QR: qrCode})
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				h.TicketQR(w, r)
+			}
+
+			req, err := http.NewRequest("GET", "/api/user/tickets/"+test.inputID+"/qr"+test.inputQuery, nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedContentType != "" {
+				assert.Equal(t, test.expectedContentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
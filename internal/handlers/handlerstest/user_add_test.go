@@ -26,17 +26,19 @@ func TestHandlerUserAdd(t *testing.T) {
 		logger.Panic(err.Error())
 	}
 
-	tick := ticket.Init(&config.TicketKey{TicketSecretKey: "123"})
+	tick, err := ticket.Init(&config.TicketKey{TicketSecretKey: "123"})
+	assert.NoError(t, err)
 
 	tests := []struct {
-		name               string
-		inputID            string
-		headerID           string
-		inputEventID       int
-		inputUserID        int
-		mockBehaviorOne    mockBehaviorOne
-		mockBehaviorTwo    mockBehaviorTwo
-		expectedStatusCode int
+		name                 string
+		inputID              string
+		headerID             string
+		inputEventID         int
+		inputUserID          int
+		mockBehaviorOne      mockBehaviorOne
+		mockBehaviorTwo      mockBehaviorTwo
+		expectedStatusCode   int
+		expectedResponseBody string
 	}{
 		{
 			name: `
@@ -49,9 +51,10 @@ got status 200
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {
-				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(nil)
+				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(0, nil)
 			},
 			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
 				r.EXPECT().GetDateEvent(ctx, eventID).Return(1, nil)
 			},
 			expectedStatusCode: 200,
@@ -72,19 +75,20 @@ got status 400
 			name: `
 POST /api/user/add #3
 not correct return AddEventUser
-got status 400
+got status 500
 			`,
 			inputID:      `MQ==`,
 			headerID:     "1",
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {
-				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(errors.New("err"))
+				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(0, errors.New("err"))
 			},
 			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
 				r.EXPECT().GetDateEvent(ctx, eventID).Return(1, nil)
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
 		},
 		{
 			name: `
@@ -98,7 +102,8 @@ got status 404
 			inputUserID:     1,
 			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {},
 			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
-				r.EXPECT().GetDateEvent(ctx, eventID).Return(0, &storage.RepError{Err: errors.New("err"), ForeignKeyViolation: true})
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
+				r.EXPECT().GetDateEvent(ctx, eventID).Return(0, &storage.NotFoundError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 404,
 		},
@@ -113,9 +118,10 @@ got status 409
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {
-				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(&storage.RepError{Err: errors.New("err"), UniqueViolation: true})
+				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(0, &storage.ConflictError{Err: errors.New("err")})
 			},
 			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
 				r.EXPECT().GetDateEvent(ctx, eventID).Return(1, nil)
 			},
 			expectedStatusCode: 409,
@@ -136,7 +142,7 @@ got status 400
 			name: `
 POST /api/user/add #7
 not correct return GetDateEvent
-got status 400
+got status 500
 			`,
 			inputID:         `MQ==`,
 			headerID:        "1",
@@ -144,9 +150,94 @@ got status 400
 			inputUserID:     1,
 			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {},
 			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
 				r.EXPECT().GetDateEvent(ctx, eventID).Return(0, errors.New("err"))
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+POST /api/user/add #8
+event is full
+got status 202
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {
+				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(3, nil)
+			},
+			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
+				r.EXPECT().GetDateEvent(ctx, eventID).Return(1, nil)
+			},
+			expectedStatusCode:   202,
+			expectedResponseBody: `{"position":3}`,
+		},
+		{
+			name: `
+POST /api/user/add #9
+mail not verified
+got status 403
+			`,
+			inputID:         `MQ==`,
+			headerID:        "1",
+			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {},
+			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(false, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name: `
+POST /api/user/add #10
+not correct return MailVerified
+got status 500
+			`,
+			inputID:         `MQ==`,
+			headerID:        "1",
+			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {},
+			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(false, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name: `
+POST /api/user/add #11
+event already started
+got status 409
+			`,
+			inputID:         `MQ==`,
+			headerID:        "1",
+			inputEventID:    1,
+			inputUserID:     1,
+			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {},
+			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
+				r.EXPECT().GetDateEvent(ctx, eventID).Return(0, &storage.ConflictError{Err: errors.New("event 1 already started")})
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name: `
+POST /api/user/add #12
+event is closed
+got status 409
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehaviorOne: func(r *mock.MockStorage, ctx context.Context, tick *entity.Ticket) {
+				r.EXPECT().AddEventUser(ctx, gomock.Any()).Return(0, &storage.ConflictError{Err: errors.New("event 1 is closed")})
+			},
+			mockBehaviorTwo: func(r *mock.MockStorage, ctx context.Context, eventID int) {
+				r.EXPECT().MailVerified(ctx, 1).Return(true, nil)
+				r.EXPECT().GetDateEvent(ctx, eventID).Return(1, nil)
+			},
+			expectedStatusCode: 409,
 		},
 	}
 
@@ -160,7 +251,7 @@ got status 400
 			test.mockBehaviorTwo(repo, context.Background(), 1)
 			test.mockBehaviorOne(repo, context.Background(), &entity.Ticket{})
 
-			h := handlers.Init(repo, tick, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo, Ticket: tick})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.UserAdd(w, r)
@@ -176,6 +267,9 @@ got status 400
 			handler(rr, req)
 
 			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedResponseBody != "" {
+				assert.Equal(t, test.expectedResponseBody, rr.Body.String())
+			}
 		})
 	}
 }
@@ -0,0 +1,352 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage/mock"
+	"graduation/internal/templates"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerUserChannelsGet(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		headerID           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: `
+GET /api/user/channels #1
+channels picked
+got status 200
+			`,
+			headerID: `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().UserChannels(ctx, 2).Return([]entity.UserChannel{
+					{Channel: entity.ChannelBot, Address: "42"},
+					{Channel: entity.ChannelWebhook, Address: "https://example.com/hook"},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `{"channels":[{"channel":"bot","address":"42"},{"channel":"webhook","address":"https://example.com/hook"}]}`,
+		},
+		{
+			name: `
+GET /api/user/channels #2
+nothing picked, account email
+got status 200
+			`,
+			headerID: `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().UserChannels(ctx, 2).Return(nil, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       `{"channels":[{"channel":"email","address":""}]}`,
+		},
+		{
+			name: `
+GET /api/user/channels #3
+not correct return UserChannels
+got status 500
+			`,
+			headerID: `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().UserChannels(ctx, 2).Return(nil, errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("GET", "/api/user/channels", nil)
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.UserChannelsGet(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerUserChannelsSet(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		headerID           string
+		webhookOff         bool
+		webhookHosts       []string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PUT /api/user/channels #1
+email of account and bot
+got status 200
+			`,
+			inputBody: `{"channels":[{"channel":"email"},{"channel":"bot","address":"42"}]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserChannels(ctx, 2, []entity.UserChannel{
+					{Channel: entity.ChannelEmail},
+					{Channel: entity.ChannelBot, Address: "42"},
+				}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/channels #2
+webhook only
+got status 200
+			`,
+			inputBody: `{"channels":[{"channel":"webhook","address":"https://example.com/hook"}]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserChannels(ctx, 2, []entity.UserChannel{
+					{Channel: entity.ChannelWebhook, Address: "https://example.com/hook"},
+				}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/channels #3
+webhook not http
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"webhook","address":"ftp://example.com"}]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #4
+not correct email
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"email","address":"user"}]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #5
+unknown channel
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"pigeon","address":"roof"}]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #6
+channel repeated
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"bot","address":"1"},{"channel":"bot","address":"2"}]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #7
+no channels
+got status 400
+			`,
+			inputBody:          `{"channels":[]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #8
+not correct return SetUserChannels
+got status 500
+			`,
+			inputBody: `{"channels":[{"channel":"email"}]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserChannels(ctx, 2, []entity.UserChannel{{Channel: entity.ChannelEmail}}).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		}, {
+			name: `
+PUT /api/user/channels #9
+webhook channel is off
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"webhook","address":"https://example.com/hook"}]}`,
+			headerID:           `2`,
+			webhookOff:         true,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #10
+webhook on a private address
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"webhook","address":"http://169.254.169.254/latest/meta-data"}]}`,
+			headerID:           `2`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #11
+webhook host not allowed
+got status 400
+			`,
+			inputBody:          `{"channels":[{"channel":"webhook","address":"https://evil.com/hook"}]}`,
+			headerID:           `2`,
+			webhookHosts:       []string{"example.com"},
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/channels #12
+webhook on an allowed subdomain
+got status 200
+			`,
+			inputBody:    `{"channels":[{"channel":"webhook","address":"https://hooks.example.com/hook"}]}`,
+			headerID:     `2`,
+			webhookHosts: []string{"example.com"},
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserChannels(ctx, 2, []entity.UserChannel{
+					{Channel: entity.ChannelWebhook, Address: "https://hooks.example.com/hook"},
+				}).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/channels #13
+email address of its own gets a verification link
+got status 200
+			`,
+			inputBody: `{"channels":[{"channel":"email","address":"work@mail.ru"}]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				work := []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru"}}
+				r.EXPECT().SetUserChannels(ctx, 2, work).Return(nil)
+				r.EXPECT().UserChannels(ctx, 2).Return(work, nil)
+				r.EXPECT().GetLocale(ctx, 2).Return("en", nil)
+				r.EXPECT().EnqueueMail(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, mails []entity.OutboxMail) error {
+					assert.Len(t, mails, 1)
+					assert.Equal(t, "work@mail.ru", mails[0].Address)
+					assert.Equal(t, entity.ChannelEmail, mails[0].Channel)
+					assert.Contains(t, mails[0].Text, "/api/user/verify?token=")
+					return nil
+				})
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/channels #14
+email address verified before
+got status 200
+			`,
+			inputBody: `{"channels":[{"channel":"email","address":"work@mail.ru"}]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetUserChannels(ctx, 2, []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru"}}).Return(nil)
+				r.EXPECT().UserChannels(ctx, 2).Return([]entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru", Verified: true}}, nil)
+				r.EXPECT().GetLocale(ctx, 2).Return("en", nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/channels #15
+not correct return EnqueueMail
+got status 500
+			`,
+			inputBody: `{"channels":[{"channel":"email","address":"work@mail.ru"}]}`,
+			headerID:  `2`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				work := []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru"}}
+				r.EXPECT().SetUserChannels(ctx, 2, work).Return(nil)
+				r.EXPECT().UserChannels(ctx, 2).Return(work, nil)
+				r.EXPECT().GetLocale(ctx, 2).Return("en", nil)
+				r.EXPECT().EnqueueMail(ctx, gomock.Any()).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	tmpl, err := templates.New("")
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo, Templates: tmpl, TokenSecretKey: "your_secret_key", Webhook: !test.webhookOff, WebhookHosts: test.webhookHosts})
+
+			req, err := http.NewRequest("PUT", "/api/user/channels", bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.UserChannelsSet(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"graduation/internal/config"
+	"graduation/internal/entity"
 	"graduation/internal/handlers"
 	"graduation/internal/logger"
 	"graduation/internal/storage"
@@ -43,7 +44,7 @@ got status 200
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID, userID int) {
-				r.EXPECT().DellEventUser(ctx, eventID, userID).Return(nil)
+				r.EXPECT().DellEventUser(ctx, eventID, userID, gomock.Any()).Return(nil, nil)
 			},
 			expectedStatusCode: 200,
 		},
@@ -62,16 +63,16 @@ got status 400
 			name: `
 POST /api/user/dell #3
 not correct return DellEventUser
-got status 400
+got status 500
 			`,
 			inputID:      `MQ==`,
 			headerID:     "1",
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID, userID int) {
-				r.EXPECT().DellEventUser(ctx, eventID, userID).Return(errors.New("err"))
+				r.EXPECT().DellEventUser(ctx, eventID, userID, gomock.Any()).Return(nil, errors.New("err"))
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
 		},
 		{
 			name: `
@@ -84,7 +85,7 @@ got status 404
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID, userID int) {
-				r.EXPECT().DellEventUser(ctx, eventID, userID).Return(&storage.RepError{Err: errors.New("err"), ForeignKeyViolation: true})
+				r.EXPECT().DellEventUser(ctx, eventID, userID, gomock.Any()).Return(nil, &storage.NotFoundError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 404,
 		},
@@ -99,7 +100,7 @@ got status 409
 			inputEventID: 1,
 			inputUserID:  1,
 			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID, userID int) {
-				r.EXPECT().DellEventUser(ctx, eventID, userID).Return(&storage.RepError{Err: errors.New("err"), UniqueViolation: true})
+				r.EXPECT().DellEventUser(ctx, eventID, userID, gomock.Any()).Return(nil, &storage.ConflictError{Err: errors.New("err")})
 			},
 			expectedStatusCode: 409,
 		},
@@ -114,6 +115,21 @@ got status 400
 			mockBehavior:       func(r *mock.MockStorage, ctx context.Context, eventID, userID int) {},
 			expectedStatusCode: 400,
 		},
+		{
+			name: `
+POST /api/user/dell #7
+seat passed to waitlist
+got status 200
+			`,
+			inputID:      `MQ==`,
+			headerID:     "1",
+			inputEventID: 1,
+			inputUserID:  1,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context, eventID, userID int) {
+				r.EXPECT().DellEventUser(ctx, eventID, userID, gomock.Any()).Return(&entity.Ticket{UserID: 2, EventID: eventID}, nil)
+			},
+			expectedStatusCode: 200,
+		},
 	}
 
 	for _, test := range tests {
@@ -124,7 +140,7 @@ got status 400
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputEventID, test.inputUserID)
 
-			h := handlers.Init(repo, nil, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.UserDell(w, r)
@@ -0,0 +1,107 @@
+package handlerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/handlers"
+	"graduation/internal/logger"
+	"graduation/internal/storage/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerUserDigest(t *testing.T) {
+	type mockBehavior func(r *mock.MockStorage, ctx context.Context)
+
+	if err := logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}); err != nil {
+		logger.Panic(err.Error())
+	}
+
+	tests := []struct {
+		name               string
+		inputBody          string
+		headerID           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: `
+PUT /api/user/digest #1
+turn on
+got status 200
+			`,
+			inputBody: `{"enabled":true}`,
+			headerID:  `5`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetSignupDigest(ctx, 5, true).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/digest #2
+turn off
+got status 200
+			`,
+			inputBody: `{"enabled":false}`,
+			headerID:  `5`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetSignupDigest(ctx, 5, false).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: `
+PUT /api/user/digest #3
+enabled missing
+got status 400
+			`,
+			inputBody:          `{}`,
+			headerID:           `5`,
+			mockBehavior:       func(r *mock.MockStorage, ctx context.Context) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name: `
+PUT /api/user/digest #4
+not correct return SetSignupDigest
+got status 500
+			`,
+			inputBody: `{"enabled":true}`,
+			headerID:  `5`,
+			mockBehavior: func(r *mock.MockStorage, ctx context.Context) {
+				r.EXPECT().SetSignupDigest(ctx, 5, true).Return(errors.New("err"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock.NewMockStorage(c)
+			test.mockBehavior(repo, context.Background())
+
+			h := handlers.Init(handlers.Deps{Storage: repo})
+
+			req, err := http.NewRequest("PUT", "/api/user/digest", bytes.NewBufferString(test.inputBody))
+			assert.NoError(t, err)
+
+			req.Header.Set("User_id", test.headerID)
+
+			rr := httptest.NewRecorder()
+
+			h.UserDigest(rr, req)
+
+			assert.Equal(t, test.expectedStatusCode, rr.Code)
+		})
+	}
+}
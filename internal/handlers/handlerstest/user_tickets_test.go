@@ -75,7 +75,7 @@ got status 400
 			name: `
 GET /api/tickets #3
 not correct return GetEvents
-got status 400
+got status 500
 			`,
 			headerID:    "1",
 			inputUserID: 1,
@@ -83,7 +83,7 @@ got status 400
 				r.EXPECT().UserTickets(ctx, userID).Return(
 					nil, errors.New("err"))
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
 		},
 	}
 
@@ -95,7 +95,7 @@ got status 400
 			repo := mock.NewMockStorage(c)
 			test.mockBehavior(repo, context.Background(), test.inputUserID)
 
-			h := handlers.Init(repo, nil, "", 0)
+			h := handlers.Init(handlers.Deps{Storage: repo})
 
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				h.UserTickets(w, r)
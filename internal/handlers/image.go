@@ -1,29 +1,65 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"graduation/internal/imaging"
 	"graduation/internal/logger"
+	"graduation/internal/ostorage"
+	"graduation/internal/problem"
 	"net/http"
+	"strings"
 )
 
-// func errorResponse(w http.ResponseWriter, message string, err error, status int) {
-// 	logger.Error("%s: %v", message, err)
-// 	w.WriteHeader(status)
-// }
+// Image serves /api/images/{filename}, so the path works as <img src>.
+// In redirect mode it answers 302 to a presigned URL of the object storage.
+// In stream mode it sends the object itself: names are never reused, so it
+// may be cached for good, and http.ServeContent answers Range requests and
+// conditional ones with 304. Blob stores without presigned URLs are
+// streamed in either mode.
+func (h *Handler) Image(w http.ResponseWriter, r *http.Request) {
+	if h.images == nil {
+		logger.Error("images not configured")
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
 
-// func goodResponse(w http.ResponseWriter, resp []byte, contentType string, status int) {
-// 	w.WriteHeader(status)
-// 	w.Header().Set("Content-Type", contentType)
-// 	w.Write(resp)
-// }
+	filename := strings.TrimPrefix(r.URL.Path, "/api/images/")
 
-func (h *Handler) Image(w http.ResponseWriter, r *http.Request) {
-	url, err := h.storage.GetImage(r.Context(), r.URL.String()[12:])
+	if h.images.ServeMode() == imaging.ServeRedirect {
+		ttl := h.images.PresignTTL()
+		url, err := h.storage.GetImage(r.Context(), filename, ttl)
+		switch {
+		case errors.Is(err, ostorage.ErrPresignNotSupported):
+			// the blob store has no URL of its own, the image is streamed
+		case err != nil:
+			logger.Error("cannot get image url: %v", err)
+			writeError(w, err)
+			return
+		default:
+			// the redirect is cached for half the life of the URL, so a
+			// cached one never points at an expired link
+			w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(ttl.Seconds()/2)))
+			http.Redirect(w, r, url, http.StatusFound)
+			return
+		}
+	}
+
+	content, object, err := h.storage.OpenImage(r.Context(), filename)
 	if err != nil {
-		logger.Error("photo not found: %v", err)
-		w.WriteHeader(http.StatusNotFound)
+		logger.Error("cannot open image: %v", err)
+		writeError(w, err)
 		return
 	}
+	defer content.Close()
+
+	if object.ContentType != "" {
+		w.Header().Set("Content-Type", object.ContentType)
+	}
+	if object.ETag != "" {
+		w.Header().Set("ETag", `"`+strings.Trim(object.ETag, `"`)+`"`)
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(url))
+	http.ServeContent(w, r, filename, object.LastModified, content)
 }
@@ -1,23 +1,79 @@
 package handlers
 
 import (
+	"graduation/internal/hasher"
+	"graduation/internal/imaging"
+	"graduation/internal/notification"
+	"graduation/internal/qr"
 	"graduation/internal/storage"
+	"graduation/internal/templates"
 	"graduation/internal/ticket"
+	"strings"
 	"time"
 )
 
 type Handler struct {
 	storage        storage.Storage
 	tick           *ticket.TicketToken
+	hash           *hasher.Hasher
+	qr             *qr.QR
+	templates      *templates.Registry
+	images         *imaging.Imaging
+	baseURL        string
+	location       *time.Location
+	webhook        bool
+	webhookHosts   []string
 	tokenSecretKey string
 	tokenEXP       time.Duration
+	refreshEXP     time.Duration
+	loginLimit     *rateLimit
+	ipLimit        *rateLimit
+	notification   *notification.Notification
 }
 
-func Init(storage storage.Storage, tick *ticket.TicketToken, tokenSecretKey string, tokenEXP time.Duration) *Handler {
+// Deps are the dependencies of the handlers. Fields a handler does not use
+// may stay zero, so tests set only what they exercise.
+type Deps struct {
+	Storage        storage.Storage
+	Ticket         *ticket.TicketToken
+	Hasher         *hasher.Hasher
+	QR             *qr.QR
+	Templates      *templates.Registry
+	Images         *imaging.Imaging
+	BaseURL        string
+	Location       *time.Location
+	Webhook        bool
+	WebhookHosts   []string
+	TokenSecretKey string
+	TokenEXP       time.Duration
+	RefreshEXP     time.Duration
+	Notification   *notification.Notification
+}
+
+func Init(deps Deps) *Handler {
 	return &Handler{
-		storage:        storage,
-		tick:           tick,
-		tokenSecretKey: tokenSecretKey,
-		tokenEXP:       tokenEXP,
+		storage:        deps.Storage,
+		tick:           deps.Ticket,
+		hash:           deps.Hasher,
+		qr:             deps.QR,
+		templates:      deps.Templates,
+		images:         deps.Images,
+		baseURL:        strings.TrimSuffix(deps.BaseURL, "/"),
+		location:       deps.Location,
+		webhook:        deps.Webhook,
+		webhookHosts:   deps.WebhookHosts,
+		tokenSecretKey: deps.TokenSecretKey,
+		tokenEXP:       deps.TokenEXP,
+		refreshEXP:     deps.RefreshEXP,
+		loginLimit:     newRateLimit(accountMailPerLogin, accountMailWindow),
+		ipLimit:        newRateLimit(accountMailPerIP, accountMailWindow),
+		notification:   deps.Notification,
+	}
+}
+
+// wake lets the notification loop send what the request queued.
+func (h *Handler) wake() {
+	if h.notification != nil {
+		h.notification.Wake()
 	}
 }
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/storage"
 
 	"net/http"
 )
@@ -12,30 +16,61 @@ type DataLogin struct {
 	Password string `json:"password"`
 }
 
+func (h *Handler) rehashPassword(ctx context.Context, userID int, password string) {
+	hash, err := h.hash.Hash(password)
+	if err != nil {
+		logger.Error("cannot rehash password: %v", err)
+		return
+	}
+
+	if err := h.storage.UpdatePassword(ctx, userID, hash); err != nil {
+		logger.Error("cannot update password: %v", err)
+	}
+}
+
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var data DataLogin
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		logger.Error("bad json: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
 		return
 	}
 
-	userID, err := h.storage.GetUser(r.Context(), data.Login, data.Password)
+	user, err := h.storage.GetUser(r.Context(), data.Login)
 	if err != nil {
-		logger.Error("bad login or password: %v", err)
-		w.WriteHeader(http.StatusUnauthorized)
+		var notFound *storage.NotFoundError
+		if errors.As(err, &notFound) {
+			logger.Error("bad login or password: %v", err)
+			problem.Write(w, http.StatusUnauthorized, "bad login or password")
+			return
+		}
+		logger.Error("cannot get user: %v", err)
+		writeError(w, err)
 		return
 	}
 
-	token, err := setAuthorization(h.tokenSecretKey, h.tokenEXP, userID)
+	ok, err := h.hash.Verify(data.Password, user.Password)
 	if err != nil {
-		logger.Error("cannot get token: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		logger.Error("cannot verify password: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
+	if !ok {
+		logger.Error("bad login or password: user %d", user.ID)
+		problem.Write(w, http.StatusUnauthorized, "bad login or password")
+		return
+	}
+
+	if h.hash.NeedsRehash(user.Password) {
+		h.rehashPassword(r.Context(), user.ID, data.Password)
+	}
 
-	http.SetCookie(w, token)
+	if err := h.startSession(r.Context(), w, user.ID, user.Role); err != nil {
+		logger.Error("cannot start session: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
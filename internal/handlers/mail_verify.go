@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"graduation/internal/authorization"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+	"strconv"
+)
+
+// VerifyMail is opened from the link in the verification email.
+func (h *Handler) VerifyMail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		logger.Error("mail token emty: %v", errors.New("token emty"))
+		problem.Write(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	claims, err := authorization.ParseMailToken(h.tokenSecretKey, token)
+	if err != nil {
+		logger.Error("mail token not valid: %v", err)
+		problem.Write(w, http.StatusUnauthorized, "token not valid or expired")
+		return
+	}
+
+	if err := h.storage.VerifyMail(r.Context(), claims.UserID, claims.Mail); err != nil {
+		logger.Error("cannot verify mail: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) VerifyResend(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	if !h.accountMailAllowed(w, r, "resend", strconv.Itoa(userID)) {
+		logger.Error("too many verification emails: user %d", userID)
+		return
+	}
+
+	verified, err := h.storage.MailVerified(r.Context(), userID)
+	if err != nil {
+		logger.Error("cannot get mail verified: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+	if verified {
+		logger.Error("mail already verified: user %d", userID)
+		problem.Write(w, http.StatusConflict, "mail is already verified")
+		return
+	}
+
+	mail, err := h.storage.GetMail(r.Context(), userID)
+	if err != nil {
+		logger.Error("cannot get mail: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	locale, err := h.storage.GetLocale(r.Context(), userID)
+	if err != nil {
+		logger.Error("cannot get locale: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if err := h.queueVerification(r.Context(), userID, mail, locale); err != nil {
+		logger.Error("cannot queue verification: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxOutboxLimit = 100
+
+type RespOutboxMail struct {
+	ID            string    `json:"id"`
+	Key           string    `json:"key"`
+	UserID        string    `json:"user_id"`
+	EventID       string    `json:"event_id,omitempty"`
+	Channel       string    `json:"channel"`
+	Address       string    `json:"address"`
+	Subject       string    `json:"subject"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type RespOutbox struct {
+	Page  int              `json:"page"`
+	Pages int              `json:"pages"`
+	Total int              `json:"total"`
+	Mails []RespOutboxMail `json:"mails"`
+}
+
+// OutboxList shows the outbox to an admin: ?status= (dead by default),
+// limit and page.
+func (h *Handler) OutboxList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	if status == "" {
+		status = entity.OutboxDead
+	}
+	if !entity.ValidOutboxStatus(status) {
+		logger.Error("not correct status: %s", status)
+		problem.Write(w, http.StatusBadRequest, "status must be pending, sent or dead")
+		return
+	}
+
+	limit, err := parseInt(query, "limit", maxOutboxLimit)
+	if err != nil || limit < 1 || limit > maxOutboxLimit {
+		logger.Error("not correct limit: %v", err)
+		problem.Write(w, http.StatusBadRequest, "limit is not valid")
+		return
+	}
+
+	page, err := parseInt(query, "page", 1)
+	if err != nil || page < 1 {
+		logger.Error("not correct page: %v", err)
+		problem.Write(w, http.StatusBadRequest, "page is not valid")
+		return
+	}
+
+	mails, total, err := h.storage.OutboxMails(r.Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		logger.Error("cannot get outbox: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	dataResp := RespOutbox{
+		Page:  page,
+		Pages: int(math.Ceil(float64(total) / float64(limit))),
+		Total: total,
+		Mails: []RespOutboxMail{},
+	}
+	for _, mail := range mails {
+		dataResp.Mails = append(dataResp.Mails, RespOutboxMail{
+			ID:            encoding.EncodeID(mail.ID),
+			Key:           mail.Key,
+			UserID:        encoding.EncodeID(mail.UserID),
+			EventID:       encodeOptionalID(mail.EventID),
+			Channel:       mail.Channel,
+			Address:       mail.Address,
+			Subject:       mail.Subject,
+			Status:        mail.Status,
+			Attempts:      mail.Attempts,
+			NextAttemptAt: mail.NextAttemptAt,
+			LastError:     mail.LastError,
+			CreatedAt:     mail.CreatedAt,
+		})
+	}
+
+	respOutbox, err := json.Marshal(dataResp)
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respOutbox)
+}
+
+// OutboxReplay queues a dead email again.
+func (h *Handler) OutboxReplay(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/outbox/"), "/replay")
+
+	mailID, err := encoding.DecodeID(id)
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	if err := h.storage.ReplayMail(r.Context(), mailID); err != nil {
+		logger.Error("cannot replay mail: %v", fmt.Errorf("mail %d: %w", mailID, err))
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
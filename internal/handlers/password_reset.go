@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/authorization"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/storage"
+	"graduation/internal/templates"
+	"graduation/internal/validation"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type DataPasswordForgot struct {
+	Login string `json:"login"`
+}
+
+type DataPasswordReset struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// forgotDuration is how long PasswordForgot takes at least, so the answer
+// does not tell whether the login exists.
+const forgotDuration = 200 * time.Millisecond
+
+// PasswordForgot queues a one-time reset token to the address of the
+// account. The answer is 200 after forgotDuration whether the login exists
+// or not, failures are only logged.
+func (h *Handler) PasswordForgot(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	var data DataPasswordForgot
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	v := validation.New()
+	v.Required("login", data.Login)
+	if err := v.Err(); err != nil {
+		logger.Error("not correct password forgot: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !h.accountMailAllowed(w, r, "forgot", data.Login) {
+		logger.Error("too many password resets: %s", data.Login)
+		return
+	}
+
+	if err := h.queuePasswordReset(r.Context(), data.Login); err != nil {
+		logger.Error("cannot queue password reset: %v", err)
+	}
+
+	time.Sleep(time.Until(start.Add(forgotDuration)))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) queuePasswordReset(ctx context.Context, login string) error {
+	user, err := h.storage.GetUser(ctx, login)
+	if err != nil {
+		return fmt.Errorf("cannot get user: %w", err)
+	}
+
+	token, hash, err := authorization.BuildOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("cannot build reset token: %w", err)
+	}
+
+	if err := h.storage.CreatePasswordReset(ctx, user.ID, hash, time.Now().Add(resetTokenEXP)); err != nil {
+		return fmt.Errorf("cannot create password reset: %w", err)
+	}
+
+	locale, err := h.storage.GetLocale(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("cannot get locale: %w", err)
+	}
+
+	return h.queueAccountMail(ctx, user.ID, user.Mail, locale, templates.Reset, "reset:"+hash, templates.Data{
+		Link:  h.baseURL + resetPath + "?token=" + url.QueryEscape(token),
+		Token: token,
+	})
+}
+
+func (h *Handler) PasswordReset(w http.ResponseWriter, r *http.Request) {
+	var data DataPasswordReset
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if err := data.validate(); err != nil {
+		logger.Error("not correct password reset: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	password, err := h.hash.Hash(data.Password)
+	if err != nil {
+		logger.Error("cannot hash password: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if err := h.storage.ResetPassword(r.Context(), authorization.HashOpaqueToken(data.Token), password); err != nil {
+		var notFound *storage.NotFoundError
+		if errors.As(err, &notFound) {
+			logger.Error("reset token not valid: %v", err)
+			problem.Write(w, http.StatusUnauthorized, "reset token not valid or already used")
+		} else {
+			logger.Error("cannot reset password: %v", err)
+			problem.Write(w, http.StatusInternalServerError, "")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
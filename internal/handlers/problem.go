@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+	"graduation/internal/problem"
+	"graduation/internal/storage"
+	"graduation/internal/validation"
+	"net/http"
+)
+
+// writeError answers with the problem for a failed call: the fields of a
+// failed validation, 404, 409 or 403 for the typed storage errors and 500
+// for anything else. The cause of a 500 stays in the log.
+func writeError(w http.ResponseWriter, err error) {
+	var (
+		invalid   validation.Errors
+		notFound  *storage.NotFoundError
+		conflict  *storage.ConflictError
+		forbidden *storage.ForbiddenError
+	)
+
+	switch {
+	case errors.As(err, &invalid):
+		problem.Invalid(invalid).Write(w)
+	case errors.As(err, &notFound):
+		problem.Write(w, http.StatusNotFound, notFound.Error())
+	case errors.As(err, &conflict):
+		problem.Write(w, http.StatusConflict, conflict.Error())
+	case errors.As(err, &forbidden):
+		problem.Write(w, http.StatusForbidden, forbidden.Error())
+	default:
+		problem.Write(w, http.StatusInternalServerError, "")
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"graduation/internal/problem"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// accountMailWindow is the window the account email limits count in.
+	accountMailWindow = 15 * time.Minute
+	// accountMailPerLogin is how many account emails one login may ask for
+	// in a window, accountMailPerIP how many one address may.
+	accountMailPerLogin = 5
+	accountMailPerIP    = 20
+)
+
+// rateLimit counts requests per key in fixed windows. It lives in the
+// process, every instance of the service counts on its own.
+type rateLimit struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string]*rateWindow
+	swept  time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimit(limit int, window time.Duration) *rateLimit {
+	return &rateLimit{limit: limit, window: window, hits: make(map[string]*rateWindow)}
+}
+
+// allow counts a request for key at now and reports whether it is within
+// the limit.
+func (l *rateLimit) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// ended windows are dropped, so keys seen once do not stay forever
+	if now.Sub(l.swept) >= l.window {
+		for k, w := range l.hits {
+			if now.Sub(w.start) >= l.window {
+				delete(l.hits, k)
+			}
+		}
+		l.swept = now
+	}
+
+	w, ok := l.hits[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.hits[key] = w
+	}
+	w.count++
+
+	return w.count <= l.limit
+}
+
+// accountMailAllowed counts an account email of action for login and for
+// the address of the request. Over either limit it answers 429.
+func (h *Handler) accountMailAllowed(w http.ResponseWriter, r *http.Request, action, login string) bool {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	now := time.Now()
+	byLogin := h.loginLimit.allow(action+":"+login, now)
+	byIP := h.ipLimit.allow(action+":"+ip, now)
+	if byLogin && byIP {
+		return true
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(accountMailWindow.Seconds())))
+	problem.Write(w, http.StatusTooManyRequests, "too many requests, try again later")
+	return false
+}
@@ -2,12 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
-	"graduation/internal/authorization"
+	"graduation/internal/entity"
 	"graduation/internal/logger"
-	"graduation/internal/storage"
-	"time"
+	"graduation/internal/problem"
 
 	"net/http"
 )
@@ -18,44 +15,46 @@ type DataRegister struct {
 	Mail     string `json:"mail"`
 }
 
-func setAuthorization(secretKey string, tokenEXP time.Duration, id int) (*http.Cookie, error) {
-	token, err := authorization.BuildJWTString(secretKey, tokenEXP, id)
-	if err != nil {
-		return nil, fmt.Errorf("cannot get token: %v", err)
-	}
-	cookie := http.Cookie{Name: "Authorization", Value: token, Path: "/api"}
-	return &cookie, nil
-}
-
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var data DataRegister
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		logger.Error("bad json: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if err := data.validate(); err != nil {
+		logger.Error("not correct register: %v", err)
+		writeError(w, err)
 		return
 	}
 
-	userID, err := h.storage.SetUser(r.Context(), data.Login, data.Password, data.Mail)
+	password, err := h.hash.Hash(data.Password)
 	if err != nil {
-		var repErr *storage.RepError
-		if errors.As(err, &repErr) && repErr.Repetition {
-			logger.Error("user already db: %v", err)
-			w.WriteHeader(http.StatusConflict)
-		} else {
-			logger.Error("cannot set user: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-		}
+		logger.Error("cannot hash password: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
-	token, err := setAuthorization(h.tokenSecretKey, h.tokenEXP, userID)
+	// every account starts as an attendee, organizers are granted by an admin
+	userID, err := h.storage.SetUser(r.Context(), data.Login, password, data.Mail, entity.RoleAttendee)
 	if err != nil {
-		logger.Error("cannot get token: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		logger.Error("cannot set user: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if err := h.startSession(r.Context(), w, userID, entity.RoleAttendee); err != nil {
+		logger.Error("cannot start session: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
-	http.SetCookie(w, token)
+
+	// the account works without the mail, the user can ask to resend it
+	if err := h.queueVerification(r.Context(), userID, data.Mail, entity.DefaultLocale); err != nil {
+		logger.Error("cannot queue verification: %v", err)
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
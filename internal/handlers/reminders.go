@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DataReminders lists how many minutes before the event reminders go out.
+type DataReminders struct {
+	Reminders []int `json:"reminders"`
+}
+
+// RespReminders is the schedule of the event and the user's own one, nil
+// when the user follows the event's schedule.
+type RespReminders struct {
+	Event []int `json:"event"`
+	User  []int `json:"user"`
+}
+
+// reminderMinutes checks and normalizes a schedule: sorted, without
+// duplicates, each offset within 1..entity.MaxReminderMinutes.
+func reminderMinutes(minutes []int) ([]int, error) {
+	if minutes == nil {
+		return nil, errors.New("reminders not set")
+	}
+
+	seen := make(map[int]bool)
+	result := []int{}
+	for _, m := range minutes {
+		if m < 1 || m > entity.MaxReminderMinutes {
+			return nil, fmt.Errorf("reminder %d out of range", m)
+		}
+		if !seen[m] {
+			seen[m] = true
+			result = append(result, m)
+		}
+	}
+
+	if len(result) > entity.MaxReminders {
+		return nil, fmt.Errorf("more than %d reminders", entity.MaxReminders)
+	}
+
+	sort.Ints(result)
+	return result, nil
+}
+
+func decodeReminders(r *http.Request) ([]int, error) {
+	var data DataReminders
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("bad json: %w", err)
+	}
+
+	return reminderMinutes(data.Reminders)
+}
+
+func (h *Handler) RemindersGet(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/reminders")
+	eventID, err := encoding.DecodeID(id)
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	if _, err := h.storage.GetEvent(r.Context(), eventID); err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	eventReminders, err := h.storage.EventReminders(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event reminders: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	userReminders, err := h.storage.UserReminders(r.Context(), eventID, userID)
+	if err != nil {
+		logger.Error("cannot get user reminders: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	respReminders, err := json.Marshal(RespReminders{Event: eventReminders, User: userReminders})
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respReminders)
+}
+
+// EventRemindersSet replaces the schedule of the event, an empty list turns
+// reminders off for everyone who did not set their own.
+func (h *Handler) EventRemindersSet(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/"), "/reminders")
+	eventID, err := encoding.DecodeID(id)
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	minutes, err := decodeReminders(r)
+	if err != nil {
+		logger.Error("not correct reminders: %v", err)
+		problem.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	if err := h.storage.SetEventReminders(r.Context(), event.ID, minutes); err != nil {
+		logger.Error("cannot set event reminders: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UserRemindersSet overrides the schedule of the event for the user, an
+// empty list opts out of reminders.
+func (h *Handler) UserRemindersSet(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(strings.TrimPrefix(r.URL.Path, "/api/user/reminders/"))
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	minutes, err := decodeReminders(r)
+	if err != nil {
+		logger.Error("not correct reminders: %v", err)
+		problem.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.storage.SetUserReminders(r.Context(), eventID, userID, minutes); err != nil {
+		logger.Error("cannot set user reminders: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UserRemindersDell returns the user to the schedule of the event.
+func (h *Handler) UserRemindersDell(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(strings.TrimPrefix(r.URL.Path, "/api/user/reminders/"))
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	if err := h.storage.DellUserReminders(r.Context(), eventID, userID); err != nil {
+		logger.Error("cannot dell user reminders: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
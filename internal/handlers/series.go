@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/rrule"
+	"graduation/internal/validation"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DataSeriesCreat struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Place        string `json:"place"`
+	Participants int    `json:"participants"`
+	Date         string `json:"date"`
+	RRule        string `json:"rrule"`
+}
+
+// DataSeriesScope names an occurrence of the series and whether an action
+// covers only it ("this") or it and all later ones ("following").
+type DataSeriesScope struct {
+	Event string `json:"event"`
+	Scope string `json:"scope"`
+}
+
+type DataSeriesUpdate struct {
+	DataSeriesScope
+	DataEventUpdate
+}
+
+type RespSeries struct {
+	ID     string   `json:"id"`
+	Events []string `json:"events"`
+}
+
+// encodeOptionalID encodes an id that may be unset, 0 becomes "".
+func encodeOptionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return encoding.EncodeID(id)
+}
+
+func newRespSeries(seriesID int, eventIDs []int) RespSeries {
+	resp := RespSeries{ID: encoding.EncodeID(seriesID), Events: []string{}}
+	for _, id := range eventIDs {
+		resp.Events = append(resp.Events, encoding.EncodeID(id))
+	}
+	return resp
+}
+
+// splitRule divides the rule of a series starting at start into the part
+// before from and the part from from on.
+func splitRule(rule *rrule.Rule, start, from time.Time) (*rrule.Rule, *rrule.Rule) {
+	before, after := *rule, *rule
+	before.Count = 0
+	before.Until = from.Add(-time.Second)
+
+	if rule.Count > 0 {
+		after.Count = rule.Count - len(rule.Between(start, start, before.Until, 0))
+	}
+
+	return &before, &after
+}
+
+// shiftRule moves a rule whose first occurrence moves from from to to.
+func shiftRule(rule *rrule.Rule, from, to time.Time) error {
+	if !rule.Until.IsZero() {
+		rule.Until = rule.Until.Add(to.Sub(from))
+	}
+
+	if len(rule.ByMonthDay) > 0 && from.Day() != to.Day() {
+		return errors.New("cannot move BYMONTHDAY series to another day")
+	}
+
+	days := int(to.Weekday()) - int(from.Weekday())
+	byDay := make([]time.Weekday, 0, len(rule.ByDay))
+	for _, day := range rule.ByDay {
+		byDay = append(byDay, time.Weekday((int(day)+days+7)%7))
+	}
+	rule.ByDay = byDay
+
+	return nil
+}
+
+func (h *Handler) SeriesCreat(w http.ResponseWriter, r *http.Request) {
+	var data DataSeriesCreat
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	date, err := data.validate(time.Now())
+	if err != nil {
+		logger.Error("not correct series: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	rule, err := rrule.Parse(data.RRule)
+	if err != nil {
+		logger.Error("cannot parse rrule: %v", err)
+		writeError(w, validation.Errors{{Field: "rrule", Message: err.Error()}})
+		return
+	}
+
+	series := entity.Series{
+		UserID:          userID,
+		Title:           data.Title,
+		Description:     data.Description,
+		Place:           data.Place,
+		MaxParticipants: data.Participants,
+		Start:           date,
+		RRule:           rule.String(),
+	}
+
+	eventIDs, err := h.storage.CreateSeries(r.Context(), &series, time.Now().Add(entity.SeriesHorizon))
+	if err != nil {
+		logger.Error("cannot creat series: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	respSeries, err := json.Marshal(newRespSeries(series.ID, eventIDs))
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respSeries)
+}
+
+// seriesOccurrence loads the series and its occurrence named in data and
+// checks that the user manages them. On failure it writes the status and
+// returns false.
+func (h *Handler) seriesOccurrence(w http.ResponseWriter, r *http.Request, id string, userID int, data *DataSeriesScope) (*entity.Series, *entity.Event, bool) {
+	seriesID, err := encoding.DecodeID(id)
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return nil, nil, false
+	}
+
+	eventID, err := encoding.DecodeID(data.Event)
+	if err != nil {
+		logger.Error("cannot get event id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "event id is not valid")
+		return nil, nil, false
+	}
+
+	if data.Scope != entity.ScopeThis && data.Scope != entity.ScopeFollowing {
+		logger.Error("not correct scope: %s", data.Scope)
+		problem.Write(w, http.StatusBadRequest, "scope must be this or following")
+		return nil, nil, false
+	}
+
+	series, err := h.storage.GetSeries(r.Context(), seriesID)
+	if err != nil {
+		logger.Error("cannot get series: %v", err)
+		writeError(w, err)
+		return nil, nil, false
+	}
+
+	if !canManageEvent(r, userID, &entity.Event{UserID: series.UserID}) {
+		logger.Error("user %d not organizer of series %d", userID, series.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the series")
+		return nil, nil, false
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil || event.SeriesID != series.ID {
+		logger.Error("event %d not in series %d: %v", eventID, series.ID, err)
+		problem.Write(w, http.StatusNotFound, "event is not in the series")
+		return nil, nil, false
+	}
+
+	return series, event, true
+}
+
+func writeSeriesError(w http.ResponseWriter, err error) {
+	logger.Error("cannot change series: %v", err)
+	writeError(w, err)
+}
+
+func (h *Handler) SeriesCancel(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/event/series/"), "/cancel")
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var data DataSeriesScope
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	series, event, ok := h.seriesOccurrence(w, r, id, userID, &data)
+	if !ok {
+		return
+	}
+
+	if data.Scope == entity.ScopeThis {
+		err = h.storage.CancelOccurrence(r.Context(), series.ID, event.ID)
+	} else {
+		var rule *rrule.Rule
+		rule, err = rrule.Parse(series.RRule)
+		if err == nil {
+			before, _ := splitRule(rule, series.Start, event.Occurrence)
+			err = h.storage.TruncateSeries(r.Context(), series.ID, event.Occurrence, before.String())
+		}
+	}
+
+	if err != nil {
+		writeSeriesError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) SeriesUpdate(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/event/series/")
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var data DataSeriesUpdate
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	series, event, ok := h.seriesOccurrence(w, r, id, userID, &data.DataSeriesScope)
+	if !ok {
+		return
+	}
+
+	if data.Scope == entity.ScopeThis {
+		h.saveEventUpdate(w, r, userID, event, &data.DataEventUpdate)
+		return
+	}
+
+	if !event.Active {
+		logger.Error("event %d closed", event.ID)
+		problem.Write(w, http.StatusConflict, "event is closed")
+		return
+	}
+
+	oldDate := event.Date
+	changes, err := applyUpdate(event, &data.DataEventUpdate, time.Now())
+	if err != nil {
+		logger.Error("not correct update: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if event.MaxParticipants < event.Participants {
+		logger.Error("max participants %d less than participants %d", event.MaxParticipants, event.Participants)
+		problem.Write(w, http.StatusConflict, "participants less than registered users")
+		return
+	}
+
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		logger.Error("cannot parse rrule: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	shift := event.Date.Sub(oldDate)
+	before, after := splitRule(rule, series.Start, event.Occurrence)
+	next := entity.Series{
+		UserID:          series.UserID,
+		Title:           series.Title,
+		Description:     series.Description,
+		Place:           series.Place,
+		MaxParticipants: series.MaxParticipants,
+		Start:           event.Occurrence.Add(shift),
+	}
+
+	for _, change := range changes {
+		switch change.Field {
+		case "title":
+			next.Title = event.Title
+		case "description":
+			next.Description = event.Description
+		case "place":
+			next.Place = event.Place
+		case "participants":
+			next.MaxParticipants = event.MaxParticipants
+		}
+	}
+
+	if err := shiftRule(after, event.Occurrence, next.Start); err != nil {
+		logger.Error("not correct update: %v", err)
+		problem.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.RRule = after.String()
+
+	eventIDs, err := h.storage.SplitSeries(r.Context(), userID, series.ID, event.Occurrence, before.String(), &next, changes, shift, h.tick)
+	if err != nil {
+		writeSeriesError(w, fmt.Errorf("cannot split series: %w", err))
+		return
+	}
+
+	respSeries, err := json.Marshal(newRespSeries(next.ID, eventIDs))
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respSeries)
+}
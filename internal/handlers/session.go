@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/authorization"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	accessCookieName  = "Authorization"
+	refreshCookieName = "Refresh"
+)
+
+func setAuthorization(secretKey string, tokenEXP time.Duration, id int, role string, sessionID int) (*http.Cookie, error) {
+	token, err := authorization.BuildJWTString(secretKey, tokenEXP, id, role, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get token: %v", err)
+	}
+	cookie := http.Cookie{Name: accessCookieName, Value: token, Path: "/api"}
+	return &cookie, nil
+}
+
+func setRefresh(token string, expiresAt time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    token,
+		Path:     "/api/user",
+		Expires:  expiresAt,
+		HttpOnly: true,
+	}
+}
+
+func clearCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: accessCookieName, Path: "/api", MaxAge: -1})
+	http.SetCookie(w, &http.Cookie{Name: refreshCookieName, Path: "/api/user", MaxAge: -1})
+}
+
+func (h *Handler) startSession(ctx context.Context, w http.ResponseWriter, userID int, role string) error {
+	refresh, refreshHash, err := authorization.BuildRefreshToken()
+	if err != nil {
+		return fmt.Errorf("cannot build refresh token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(h.refreshEXP)
+
+	sessionID, err := h.storage.CreateSession(ctx, userID, refreshHash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("cannot create session: %w", err)
+	}
+
+	token, err := setAuthorization(h.tokenSecretKey, h.tokenEXP, userID, role, sessionID)
+	if err != nil {
+		return fmt.Errorf("cannot get token: %w", err)
+	}
+
+	http.SetCookie(w, token)
+	http.SetCookie(w, setRefresh(refresh, expiresAt))
+
+	return nil
+}
+
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(refreshCookieName)
+	if err != nil {
+		logger.Error("cookies do not contain a refresh token: %v", err)
+		problem.Write(w, http.StatusUnauthorized, "refresh token is missing")
+		return
+	}
+
+	refresh, refreshHash, err := authorization.BuildRefreshToken()
+	if err != nil {
+		logger.Error("cannot build refresh token: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	expiresAt := time.Now().Add(h.refreshEXP)
+
+	session, err := h.storage.RefreshSession(r.Context(), authorization.HashRefreshToken(cookie.Value), refreshHash, expiresAt)
+	if err != nil {
+		var notFound *storage.NotFoundError
+		if errors.As(err, &notFound) {
+			logger.Error("session not active: %v", err)
+			problem.Write(w, http.StatusUnauthorized, "session not active")
+		} else {
+			logger.Error("cannot refresh session: %v", err)
+			problem.Write(w, http.StatusInternalServerError, "")
+		}
+		return
+	}
+
+	token, err := setAuthorization(h.tokenSecretKey, h.tokenEXP, session.UserID, session.Role, session.ID)
+	if err != nil {
+		logger.Error("cannot get token: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	http.SetCookie(w, token)
+	http.SetCookie(w, setRefresh(refresh, expiresAt))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.Atoi(r.Header.Get("Session_id"))
+	if err != nil {
+		logger.Error("cannot get session id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "session id is missing")
+		return
+	}
+
+	if err := h.storage.RevokeSession(r.Context(), sessionID); err != nil {
+		logger.Error("cannot revoke session: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	clearCookies(w)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	if err := h.storage.RevokeUserSessions(r.Context(), userID); err != nil {
+		logger.Error("cannot revoke sessions: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	clearCookies(w)
+
+	w.WriteHeader(http.StatusOK)
+}
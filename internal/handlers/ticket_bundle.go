@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"graduation/internal/bundle"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RespMerge struct {
+	EventID  string `json:"event_id"`
+	Received int    `json:"received"`
+	Merged   int    `json:"merged"`
+}
+
+func (h *Handler) TicketBundle(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(r.URL.String()[18:])
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	tickets, err := h.storage.EventTickets(r.Context(), event.ID)
+	if err != nil {
+		logger.Error("cannot get tickets: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	revoked, err := h.storage.RevokedTickets(r.Context(), event.ID)
+	if err != nil {
+		logger.Error("cannot get revoked tickets: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	signed, err := bundle.New(event.ID, tickets, revoked, h.tick.JWKS(), time.Now()).Sign(h.tick)
+	if err != nil {
+		logger.Error("cannot sign bundle: %v", err)
+		problem.Write(w, http.StatusNotImplemented, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwt")
+	w.Header().Set("Content-Disposition", `attachment; filename="event-`+strconv.Itoa(event.ID)+`.bundle"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(signed))
+}
+
+func (h *Handler) CheckinMerge(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(r.URL.String()[20:])
+	if err != nil {
+		logger.Error("cannot get id from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var checkins []entity.Checkin
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var checkin entity.Checkin
+		if err := json.Unmarshal(scanner.Bytes(), &checkin); err != nil || checkin.Token == "" || checkin.ScannedAt.IsZero() {
+			logger.Error("not correct log line: %v", err)
+			problem.Write(w, http.StatusBadRequest, "log line must be JSON with token and scanned_at")
+			return
+		}
+		checkins = append(checkins, checkin)
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Error("cannot read body: %v", err)
+		problem.Write(w, http.StatusBadRequest, "cannot read request body")
+		return
+	}
+
+	event, err := h.storage.GetEvent(r.Context(), eventID)
+	if err != nil {
+		logger.Error("cannot get event: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	if !canManageEvent(r, userID, event) {
+		logger.Error("user %d not organizer of event %d", userID, event.ID)
+		problem.Write(w, http.StatusForbidden, "user is not the organizer of the event")
+		return
+	}
+
+	merged, err := h.storage.MergeCheckins(r.Context(), event.ID, userID, checkins)
+	if err != nil {
+		logger.Error("cannot merge checkins: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	respMerge, err := json.Marshal(RespMerge{
+		EventID:  encoding.EncodeID(event.ID),
+		Received: len(checkins),
+		Merged:   merged,
+	})
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respMerge)
+}
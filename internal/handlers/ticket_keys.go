@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+)
+
+func (h *Handler) TicketKeys(w http.ResponseWriter, r *http.Request) {
+	respKeys, err := json.Marshal(h.tick.JWKS())
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respKeys)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"graduation/internal/encoding"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/qr"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) TicketQR(w http.ResponseWriter, r *http.Request) {
+	eventID, err := encoding.DecodeID(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/tickets/"), "/qr"))
+	if err != nil {
+		logger.Error("cannot get eventID from url: %v", err)
+		problem.Write(w, http.StatusBadRequest, "event id is not valid")
+		return
+	}
+
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	size := h.qr.Size()
+	if param := r.URL.Query().Get("size"); param != "" {
+		size, err = strconv.Atoi(param)
+		if err != nil || !qr.ValidSize(size) {
+			logger.Error("not correct size: %s", param)
+			problem.Write(w, http.StatusBadRequest, "size is not valid")
+			return
+		}
+	}
+
+	level := h.qr.Level()
+	if param := r.URL.Query().Get("level"); param != "" {
+		level, err = qr.ParseLevel(param)
+		if err != nil {
+			logger.Error("not correct level: %v", err)
+			problem.Write(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "png" && format != "svg" {
+		logger.Error("not correct format: %s", format)
+		problem.Write(w, http.StatusBadRequest, "format must be png or svg")
+		return
+	}
+
+	ticket, err := h.storage.GetTicket(r.Context(), userID, eventID)
+	if err != nil {
+		logger.Error("cannot get ticket: %v", err)
+		writeError(w, err)
+		return
+	}
+	if !ticket.Status {
+		logger.Error("ticket of user %d for event %d is not active", userID, eventID)
+		problem.Write(w, http.StatusConflict, "ticket is not active")
+		return
+	}
+
+	var image []byte
+	contentType := "image/png"
+	if format == "svg" {
+		image, err = qr.SVG(ticket.Token, size, level)
+		contentType = "image/svg+xml"
+	} else {
+		image, err = qr.PNG(ticket.Token, size, level)
+	}
+	if err != nil {
+		logger.Error("cannot render qr: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(image)
+}
@@ -1,40 +1,50 @@
 package handlers
 
 import (
-	"errors"
+	"encoding/json"
 	"graduation/internal/encoding"
 	"graduation/internal/entity"
 	"graduation/internal/logger"
-	"graduation/internal/storage"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 )
 
+type RespWaitlist struct {
+	Position int `json:"position"`
+}
+
 func (h *Handler) UserAdd(w http.ResponseWriter, r *http.Request) {
 	eventID, err := encoding.DecodeID(r.URL.String()[14:])
 	if err != nil {
 		logger.Error("cannot get eventID from url: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "event id is not valid")
 		return
 	}
 
 	userID, err := strconv.Atoi(r.Header.Get("User_id"))
 	if err != nil {
 		logger.Error("cannot get user id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	verified, err := h.storage.MailVerified(r.Context(), userID)
+	if err != nil {
+		logger.Error("cannot get mail verified: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+	if !verified {
+		logger.Error("mail not verified: user %d", userID)
+		problem.Write(w, http.StatusForbidden, "mail is not verified")
 		return
 	}
 
 	hour, err := h.storage.GetDateEvent(r.Context(), eventID)
 	if err != nil {
-		var repErr *storage.RepError
-		if errors.As(err, &repErr) && repErr.ForeignKeyViolation {
-			logger.Error("event not exist: %v", err)
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			logger.Error("cannot get  date event: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-		}
+		logger.Error("cannot get  date event: %v", err)
+		writeError(w, err)
 		return
 	}
 
@@ -46,19 +56,29 @@ func (h *Handler) UserAdd(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.tick.Generate(&ticket); err != nil {
 		logger.Error("cannot creat ticket: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
-	if err := h.storage.AddEventUser(r.Context(), &ticket); err != nil {
-		var repErr *storage.RepError
-		if errors.As(err, &repErr) && repErr.UniqueViolation {
-			logger.Error("user already add event: %v", err)
-			w.WriteHeader(http.StatusConflict)
-		} else {
-			logger.Error("cannot add event user: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
+	position, err := h.storage.AddEventUser(r.Context(), &ticket)
+	if err != nil {
+		logger.Error("cannot add event user: %v", err)
+		writeError(w, err)
+		return
+	}
+	h.wake()
+
+	if position > 0 {
+		respWaitlist, err := json.Marshal(RespWaitlist{Position: position})
+		if err != nil {
+			logger.Error("cannot json to byte: %v", err)
+			problem.Write(w, http.StatusInternalServerError, "")
+			return
 		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(respWaitlist)
 		return
 	}
 
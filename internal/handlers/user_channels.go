@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/channel"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+	"net/mail"
+	"strconv"
+)
+
+// maxBotAddress is the longest chat id a bot channel accepts.
+const maxBotAddress = 64
+
+type DataUserChannel struct {
+	Channel string `json:"channel"`
+	Address string `json:"address"`
+}
+
+// DataUserChannels lists where the user gets notifications. An email
+// channel without address uses the account email.
+type DataUserChannels struct {
+	Channels []DataUserChannel `json:"channels"`
+}
+
+func (h *Handler) validChannelAddress(name, address string) error {
+	switch name {
+	case entity.ChannelEmail:
+		if address == "" {
+			return nil
+		}
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("not correct email: %w", err)
+		}
+	case entity.ChannelWebhook:
+		if !h.webhook {
+			return errors.New("channel webhook is off")
+		}
+		if err := channel.CheckWebhookURL(address, h.webhookHosts); err != nil {
+			return err
+		}
+	case entity.ChannelBot:
+		if address == "" || len(address) > maxBotAddress {
+			return errors.New("not correct bot chat id")
+		}
+	default:
+		return fmt.Errorf("unknown channel: %s", name)
+	}
+
+	return nil
+}
+
+func (h *Handler) UserChannelsGet(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	channels, err := h.storage.UserChannels(r.Context(), userID)
+	if err != nil {
+		logger.Error("cannot get user channels: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if len(channels) == 0 {
+		channels = []entity.UserChannel{{Channel: entity.ChannelEmail}}
+	}
+
+	dataResp := DataUserChannels{Channels: []DataUserChannel{}}
+	for _, channel := range channels {
+		dataResp.Channels = append(dataResp.Channels, DataUserChannel{Channel: channel.Channel, Address: channel.Address})
+	}
+
+	respChannels, err := json.Marshal(dataResp)
+	if err != nil {
+		logger.Error("cannot json to byte: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respChannels)
+}
+
+// UserChannelsSet replaces the channels of the user, each channel at most
+// once. A new email address gets a verification link and is used once it
+// is opened.
+func (h *Handler) UserChannelsSet(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var data DataUserChannels
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if len(data.Channels) == 0 {
+		logger.Error("channels emty: %v", errors.New("no channels"))
+		problem.Write(w, http.StatusBadRequest, "channels are required")
+		return
+	}
+
+	seen := make(map[string]bool)
+	channels := make([]entity.UserChannel, 0, len(data.Channels))
+	for _, channel := range data.Channels {
+		if seen[channel.Channel] {
+			logger.Error("channel %s repeated", channel.Channel)
+			problem.Write(w, http.StatusBadRequest, "channel "+channel.Channel+" is repeated")
+			return
+		}
+		seen[channel.Channel] = true
+
+		if err := h.validChannelAddress(channel.Channel, channel.Address); err != nil {
+			logger.Error("not correct channel: %v", err)
+			problem.Write(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		channels = append(channels, entity.UserChannel{Channel: channel.Channel, Address: channel.Address})
+	}
+
+	if err := h.storage.SetUserChannels(r.Context(), userID, channels); err != nil {
+		logger.Error("cannot set user channels: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if err := h.verifyChannels(r.Context(), userID, channels); err != nil {
+		logger.Error("cannot verify channels: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifyChannels queues verification links to the email addresses of
+// channels that are not verified yet.
+func (h *Handler) verifyChannels(ctx context.Context, userID int, channels []entity.UserChannel) error {
+	pending := false
+	for _, channel := range channels {
+		pending = pending || channel.Unverified()
+	}
+	if !pending {
+		return nil
+	}
+
+	// the storage knows which addresses were verified before
+	saved, err := h.storage.UserChannels(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cannot get user channels: %w", err)
+	}
+	locale, err := h.storage.GetLocale(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cannot get locale: %w", err)
+	}
+
+	for _, channel := range saved {
+		if !channel.Unverified() {
+			continue
+		}
+		if err := h.queueVerification(ctx, userID, channel.Address, locale); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,10 +1,9 @@
 package handlers
 
 import (
-	"errors"
 	"graduation/internal/encoding"
 	"graduation/internal/logger"
-	"graduation/internal/storage"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 )
@@ -13,33 +12,24 @@ func (h *Handler) UserDell(w http.ResponseWriter, r *http.Request) {
 	eventID, err := encoding.DecodeID(r.URL.String()[15:])
 	if err != nil {
 		logger.Error("cannot get eventID from url: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "event id is not valid")
 		return
 	}
 
 	userID, err := strconv.Atoi(r.Header.Get("User_id"))
 	if err != nil {
 		logger.Error("cannot get user id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
 		return
 	}
 
-	if err := h.storage.DellEventUser(r.Context(), eventID, userID); err != nil {
-		var repErr *storage.RepError
-		if errors.As(err, &repErr) {
-			if repErr.UniqueViolation {
-				logger.Error("user not add event: %v", err)
-				w.WriteHeader(http.StatusConflict)
-			} else if repErr.ForeignKeyViolation {
-				logger.Error("event not exist: %v", err)
-				w.WriteHeader(http.StatusNotFound)
-			}
-		} else {
-			logger.Error("cannot dell user from event: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-		}
+	_, err = h.storage.DellEventUser(r.Context(), eventID, userID, h.tick)
+	if err != nil {
+		logger.Error("cannot dell user from event: %v", err)
+		writeError(w, err)
 		return
 	}
+	h.wake()
 
 	w.WriteHeader(http.StatusOK)
 }
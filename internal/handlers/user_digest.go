@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"graduation/internal/validation"
+	"net/http"
+	"strconv"
+)
+
+type DataUserDigest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// UserDigest turns the organizer's daily digest of new signups on or off.
+func (h *Handler) UserDigest(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var data DataUserDigest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if data.Enabled == nil {
+		logger.Error("enabled missing")
+		writeError(w, validation.Errors{{Field: "enabled", Message: "is required"}})
+		return
+	}
+
+	if err := h.storage.SetSignupDigest(r.Context(), userID, *data.Enabled); err != nil {
+		logger.Error("cannot set signup digest: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"graduation/internal/encoding"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 )
@@ -12,14 +13,14 @@ func (h *Handler) UserEvents(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(r.Header.Get("User_id"))
 	if err != nil {
 		logger.Error("cannot get user id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
 		return
 	}
 
 	events, err := h.storage.GetUserEvents(r.Context(), userID)
 	if err != nil {
 		logger.Error("cannot get events: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	dataResp := []RespEvent{}
@@ -33,6 +34,7 @@ func (h *Handler) UserEvents(w http.ResponseWriter, r *http.Request) {
 			MaxParticipants: event.MaxParticipants,
 			Date:            event.Date,
 			Active:          event.Active,
+			Images:          respImages(event.Images),
 		})
 		for _, image := range event.Images {
 			dataResp[index].Photo = append(dataResp[index].Photo, image.Filename)
@@ -42,7 +44,7 @@ func (h *Handler) UserEvents(w http.ResponseWriter, r *http.Request) {
 	respEvents, err := json.Marshal(dataResp)
 	if err != nil {
 		logger.Error("cannot json to byte: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
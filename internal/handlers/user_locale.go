@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/problem"
+	"net/http"
+	"strconv"
+)
+
+type DataUserLocale struct {
+	Locale string `json:"locale"`
+}
+
+// UserLocale sets the language of the user's notifications.
+func (h *Handler) UserLocale(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("User_id"))
+	if err != nil {
+		logger.Error("cannot get user id: %v", err)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
+		return
+	}
+
+	var data DataUserLocale
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Error("bad json: %v", err)
+		problem.Write(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	if !entity.ValidLocale(data.Locale) {
+		logger.Error("not correct locale: %s", data.Locale)
+		problem.Write(w, http.StatusBadRequest, "locale must be en or ru")
+		return
+	}
+
+	if err := h.storage.SetLocale(r.Context(), userID, data.Locale); err != nil {
+		logger.Error("cannot set locale: %v", err)
+		problem.Write(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
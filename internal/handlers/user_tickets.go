@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"graduation/internal/encoding"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
 	"net/http"
 	"strconv"
 )
@@ -18,14 +19,14 @@ func (h *Handler) UserTickets(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(r.Header.Get("User_id"))
 	if err != nil {
 		logger.Error("cannot get user id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "user id is missing")
 		return
 	}
 
 	tickets, err := h.storage.UserTickets(r.Context(), userID)
 	if err != nil {
 		logger.Error("cannot get tickets: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
@@ -41,7 +42,7 @@ func (h *Handler) UserTickets(w http.ResponseWriter, r *http.Request) {
 	respTickets, err := json.Marshal(dataResp)
 	if err != nil {
 		logger.Error("cannot json to byte: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
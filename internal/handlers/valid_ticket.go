@@ -6,6 +6,7 @@ import (
 	"graduation/internal/encoding"
 	"graduation/internal/entity"
 	"graduation/internal/logger"
+	"graduation/internal/problem"
 	"net/http"
 	"time"
 )
@@ -23,7 +24,7 @@ func (h *Handler) ValidTicket(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.String()[17:]
 	if token == "" {
 		logger.Error("token from url emty: %v", errors.New("token emty"))
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "token is required")
 		return
 	}
 
@@ -33,14 +34,14 @@ func (h *Handler) ValidTicket(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.tick.Validate(&ticket); err != nil {
 		logger.Error("cannot validate ticket: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, "ticket is not valid")
 		return
 	}
 
 	event, err := h.storage.GetEvent(r.Context(), ticket.EventID)
 	if err != nil {
 		logger.Error("cannot event: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
@@ -56,7 +57,7 @@ func (h *Handler) ValidTicket(w http.ResponseWriter, r *http.Request) {
 	respEvent, err := json.Marshal(dataResp)
 	if err != nil {
 		logger.Error("cannot json to byte: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.Write(w, http.StatusInternalServerError, "")
 		return
 	}
 
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"graduation/internal/validation"
+	"time"
+)
+
+// Limits of the text fields of requests, in characters.
+const (
+	maxLoginLength       = 64
+	maxPasswordLength    = 128
+	maxMailLength        = 254
+	maxTitleLength       = 200
+	maxPlaceLength       = 200
+	maxDescriptionLength = 5000
+)
+
+func (d *DataRegister) validate() error {
+	v := validation.New()
+
+	v.Required("login", d.Login)
+	v.MaxLength("login", d.Login, maxLoginLength)
+	v.Required("password", d.Password)
+	v.MaxLength("password", d.Password, maxPasswordLength)
+	v.Required("mail", d.Mail)
+	v.MaxLength("mail", d.Mail, maxMailLength)
+	v.Mail("mail", d.Mail)
+
+	return v.Err()
+}
+
+// validateEvent checks the fields an event and a series have in common and
+// returns the parsed date.
+func validateEvent(v *validation.Validator, title, description, place string, participants int, date string, now time.Time) time.Time {
+	v.Required("title", title)
+	v.MaxLength("title", title, maxTitleLength)
+	v.MaxLength("description", description, maxDescriptionLength)
+	v.Required("place", place)
+	v.MaxLength("place", place, maxPlaceLength)
+	v.Positive("participants", participants)
+
+	parsed, ok := v.Date("date", date, eventDateLayout)
+	if ok {
+		v.Future("date", parsed, now)
+	}
+
+	return parsed
+}
+
+func (d *DataEventCreat) validate(now time.Time) (time.Time, error) {
+	v := validation.New()
+	date := validateEvent(v, d.Title, d.Description, d.Place, d.Participants, d.Date, now)
+	return date, v.Err()
+}
+
+func (d *DataSeriesCreat) validate(now time.Time) (time.Time, error) {
+	v := validation.New()
+	date := validateEvent(v, d.Title, d.Description, d.Place, d.Participants, d.Date, now)
+	v.Required("rrule", d.RRule)
+	return date, v.Err()
+}
+
+func (d *DataPasswordReset) validate() error {
+	v := validation.New()
+
+	v.Required("token", d.Token)
+	v.Required("password", d.Password)
+	v.MaxLength("password", d.Password, maxPasswordLength)
+
+	return v.Err()
+}
@@ -0,0 +1,57 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptHash struct {
+	cost int
+}
+
+func newBcrypt() *bcryptHash {
+	return &bcryptHash{cost: bcrypt.DefaultCost}
+}
+
+func (b *bcryptHash) Name() string {
+	return "bcrypt"
+}
+
+func (b *bcryptHash) Match(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func (b *bcryptHash) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+func (b *bcryptHash) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot compare: %w", err)
+	}
+
+	return true, nil
+}
+
+func (b *bcryptHash) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost != b.cost
+}
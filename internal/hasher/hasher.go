@@ -0,0 +1,64 @@
+package hasher
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PlainPrefix marks passwords stored before hashing was introduced. The
+// migration adds it to every legacy row, so plaintext is never guessed from
+// the shape of a value.
+const PlainPrefix = "plain:"
+
+var errUnknownScheme = errors.New("unknown password scheme")
+
+func (h *Hasher) Hash(password string) (string, error) {
+	encoded, err := h.current.Hash(password)
+	if err != nil {
+		return "", fmt.Errorf("cannot hash %s: %w", h.current.Name(), err)
+	}
+
+	return encoded, nil
+}
+
+func (h *Hasher) algorithm(encoded string) Algorithm {
+	for _, algorithm := range h.algorithms {
+		if algorithm.Match(encoded) {
+			return algorithm
+		}
+	}
+
+	return nil
+}
+
+// Verify checks the password against a stored value. Values marked with
+// PlainPrefix are compared as plaintext.
+func (h *Hasher) Verify(password, encoded string) (bool, error) {
+	if plain, ok := strings.CutPrefix(encoded, PlainPrefix); ok {
+		return subtle.ConstantTimeCompare([]byte(password), []byte(plain)) == 1, nil
+	}
+
+	algorithm := h.algorithm(encoded)
+	if algorithm == nil {
+		return false, errUnknownScheme
+	}
+
+	ok, err := algorithm.Verify(password, encoded)
+	if err != nil {
+		return false, fmt.Errorf("cannot verify %s: %w", algorithm.Name(), err)
+	}
+
+	return ok, nil
+}
+
+// NeedsRehash reports whether a stored value is plaintext, uses another
+// algorithm or was produced with outdated parameters.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if !h.current.Match(encoded) {
+		return true
+	}
+
+	return h.current.NeedsRehash(encoded)
+}
@@ -0,0 +1,94 @@
+package hasher_test
+
+import (
+	"graduation/internal/config"
+	"graduation/internal/hasher"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasher(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+	}{
+		{
+			name:      "argon2id",
+			algorithm: "argon2id",
+		},
+		{
+			name:      "bcrypt",
+			algorithm: "bcrypt",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: test.algorithm})
+			assert.NoError(t, err)
+
+			encoded, err := hash.Hash("password")
+			assert.NoError(t, err)
+			assert.NotEqual(t, "password", encoded)
+
+			ok, err := hash.Verify("password", encoded)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = hash.Verify("other", encoded)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.False(t, hash.NeedsRehash(encoded))
+		})
+	}
+
+	t.Run("plaintext", func(t *testing.T) {
+		hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+		assert.NoError(t, err)
+
+		ok, err := hash.Verify("password", hasher.PlainPrefix+"password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, hash.NeedsRehash(hasher.PlainPrefix+"password"))
+
+		// a legacy password shaped like a hash is still plaintext
+		ok, err = hash.Verify("$argon2id$secret", hasher.PlainPrefix+"$argon2id$secret")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = hash.Verify("password", hasher.PlainPrefix+"other")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("unmarked plaintext", func(t *testing.T) {
+		hash, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+		assert.NoError(t, err)
+
+		ok, err := hash.Verify("password", "password")
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("other algorithm", func(t *testing.T) {
+		bcrypt, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "bcrypt"})
+		assert.NoError(t, err)
+		argon2id, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "argon2id"})
+		assert.NoError(t, err)
+
+		encoded, err := bcrypt.Hash("password")
+		assert.NoError(t, err)
+
+		ok, err := argon2id.Verify("password", encoded)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, argon2id.NeedsRehash(encoded))
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, err := hasher.Init(&config.PasswordHash{HashAlgorithm: "md5"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,31 @@
+package hasher
+
+import (
+	"fmt"
+	"graduation/internal/config"
+)
+
+type Algorithm interface {
+	Name() string
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	Match(encoded string) bool
+	NeedsRehash(encoded string) bool
+}
+
+type Hasher struct {
+	current    Algorithm
+	algorithms []Algorithm
+}
+
+func Init(conf *config.PasswordHash) (*Hasher, error) {
+	algorithms := []Algorithm{newArgon2id(), newBcrypt()}
+
+	for _, algorithm := range algorithms {
+		if algorithm.Name() == conf.HashAlgorithm {
+			return &Hasher{current: algorithm, algorithms: algorithms}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown hash algorithm: %s", conf.HashAlgorithm)
+}
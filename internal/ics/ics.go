@@ -0,0 +1,118 @@
+package ics
+
+import (
+	"bytes"
+	"graduation/internal/encoding"
+	"graduation/internal/entity"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodID     = "-//EVENT.NE//graduation//EN"
+	uidDomain  = "event.ne"
+	dateLayout = "20060102T150405Z"
+	// lineLimit is the RFC 5545 limit of a content line in octets, without CRLF.
+	lineLimit = 75
+	// eventDuration is how long events look in calendars, they have no end.
+	eventDuration = time.Hour
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`)
+
+const (
+	MethodPublish = "PUBLISH"
+	// MethodCancel tells calendar clients to remove the events.
+	MethodCancel = "CANCEL"
+)
+
+// Calendar is a set of events, Method is MethodPublish when empty. Event
+// dates are wall-clock time in Location, UTC when nil.
+type Calendar struct {
+	Name     string
+	Method   string
+	Location *time.Location
+	Events   []entity.Event
+}
+
+// UID is stable for an event, so calendar clients update the entry when the
+// event is edited instead of adding a copy.
+func UID(event *entity.Event) string {
+	return "event-" + encoding.EncodeID(event.ID) + "@" + uidDomain
+}
+
+func (c *Calendar) Encode(now time.Time) []byte {
+	var buf bytes.Buffer
+	line := func(name, value string) {
+		writeFolded(&buf, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	method := c.Method
+	if method == "" {
+		method = MethodPublish
+	}
+	line("METHOD", method)
+	if c.Name != "" {
+		line("X-WR-CALNAME", textEscaper.Replace(c.Name))
+	}
+
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	for _, event := range c.Events {
+		// the date is read as it was entered and placed in loc
+		d := event.Date
+		start := time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute(), d.Second(), 0, loc)
+
+		line("BEGIN", "VEVENT")
+		line("UID", UID(&event))
+		line("DTSTAMP", now.UTC().Format(dateLayout))
+		line("DTSTART", start.UTC().Format(dateLayout))
+		line("DTEND", start.Add(eventDuration).UTC().Format(dateLayout))
+		line("SUMMARY", textEscaper.Replace(event.Title))
+		if method == MethodCancel {
+			line("STATUS", "CANCELLED")
+		}
+		if event.Description != "" {
+			line("DESCRIPTION", textEscaper.Replace(event.Description))
+		}
+		if event.Place != "" {
+			line("LOCATION", textEscaper.Replace(event.Place))
+		}
+		line("BEGIN", "VALARM")
+		line("ACTION", "DISPLAY")
+		line("DESCRIPTION", textEscaper.Replace(event.Title))
+		line("TRIGGER", "-PT1H")
+		line("END", "VALARM")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// writeFolded splits a content line into 75 octet pieces, continuation
+// lines start with a space. Multi-byte runes are never split.
+func writeFolded(buf *bytes.Buffer, content string) {
+	limit := lineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		buf.WriteString(content[:cut])
+		buf.WriteString("\r\n ")
+		content = content[cut:]
+		limit = lineLimit - 1
+	}
+	buf.WriteString(content)
+	buf.WriteString("\r\n")
+}
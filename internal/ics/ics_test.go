@@ -0,0 +1,66 @@
+package ics_test
+
+import (
+	"graduation/internal/entity"
+	"graduation/internal/ics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarEncode(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := entity.Event{
+		ID:          1,
+		Title:       "Concert; jazz, blues",
+		Description: "Line one\nLine two " + strings.Repeat("длинное описание ", 10),
+		Place:       `Hall \ 2`,
+		Date:        time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC),
+		Active:      true,
+	}
+
+	data := string((&ics.Calendar{Name: "EVENT.NE", Events: []entity.Event{event}}).Encode(now))
+
+	assert.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(data, "END:VCALENDAR\r\n"))
+	assert.Contains(t, data, "UID:event-MQ==@event.ne\r\n")
+	assert.Contains(t, data, "DTSTAMP:20240301T100000Z\r\n")
+	assert.Contains(t, data, "DTSTART:20240310T183000Z\r\n")
+	assert.Contains(t, data, "DTEND:20240310T193000Z\r\n")
+	assert.Contains(t, data, `SUMMARY:Concert\; jazz\, blues`+"\r\n")
+	assert.Contains(t, data, `LOCATION:Hall \\ 2`+"\r\n")
+	assert.Contains(t, data, "X-WR-CALNAME:EVENT.NE\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+
+	unfolded := strings.ReplaceAll(data, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:Line one\nLine two `+strings.Repeat("длинное описание ", 10)+"\r\n")
+}
+
+func TestCalendarEncodeLocation(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	// dates come from the database without a zone
+	event := entity.Event{ID: 1, Title: "Concert", Date: time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC)}
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	data := string((&ics.Calendar{Location: moscow, Events: []entity.Event{event}}).Encode(now))
+
+	assert.Contains(t, data, "DTSTART:20240310T153000Z\r\n")
+	assert.Contains(t, data, "DTEND:20240310T163000Z\r\n")
+	assert.Contains(t, data, "DTSTAMP:20240301T100000Z\r\n")
+}
+
+func TestCalendarEncodeCancel(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := entity.Event{ID: 1, Title: "Concert", Date: time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC)}
+
+	data := string((&ics.Calendar{Method: ics.MethodCancel, Events: []entity.Event{event}}).Encode(now))
+
+	assert.Contains(t, data, "METHOD:CANCEL\r\n")
+	assert.Contains(t, data, "STATUS:CANCELLED\r\n")
+	assert.Contains(t, data, "UID:event-MQ==@event.ne\r\n")
+}
@@ -0,0 +1,44 @@
+package imaging
+
+import "bytes"
+
+const (
+	MIMEJPEG = "image/jpeg"
+	MIMEPNG  = "image/png"
+	MIMEGIF  = "image/gif"
+	MIMEWebP = "image/webp"
+)
+
+// Detect returns the MIME type of data from its first bytes, the file name
+// and the type claimed by the client are not trusted. It is empty for
+// anything but JPEG, PNG, GIF and WebP.
+func Detect(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return MIMEJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return MIMEPNG
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return MIMEGIF
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return MIMEWebP
+	}
+
+	return ""
+}
+
+// Ext is the file extension for images stored with mime.
+func Ext(mime string) string {
+	switch mime {
+	case MIMEJPEG:
+		return ".jpg"
+	case MIMEPNG:
+		return ".png"
+	case MIMEGIF:
+		return ".gif"
+	case MIMEWebP:
+		return ".webp"
+	}
+
+	return ""
+}
@@ -0,0 +1,57 @@
+package imaging
+
+import (
+	"errors"
+)
+
+var errGIFTruncated = errors.New("gif truncated")
+
+// gifFrames counts the frames of a GIF by walking its blocks without
+// decompressing them, so a small file with thousands of frames is rejected
+// before gif.DecodeAll allocates every one.
+func gifFrames(data []byte) (int, error) {
+	// header and logical screen descriptor
+	i := 13
+	if len(data) < i {
+		return 0, errGIFTruncated
+	}
+	if flags := data[10]; flags&0x80 != 0 {
+		i += 3 << (flags&0x07 + 1)
+	}
+
+	frames := 0
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // extension: label, then sub-blocks
+			i += 2
+		case 0x2c: // image descriptor, optional color table, LZW code size
+			if i+10 > len(data) {
+				return 0, errGIFTruncated
+			}
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << (flags&0x07 + 1)
+			}
+			i++
+			frames++
+		case 0x3b: // trailer
+			return frames, nil
+		default:
+			return 0, errors.New("unknown gif block")
+		}
+
+		for {
+			if i >= len(data) {
+				return 0, errGIFTruncated
+			}
+			size := int(data[i])
+			i += 1 + size
+			if size == 0 {
+				break
+			}
+		}
+	}
+
+	return frames, nil
+}
@@ -0,0 +1,212 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"graduation/internal/config"
+	"graduation/internal/imaging"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newImaging(t *testing.T) *imaging.Imaging {
+	img, err := imaging.Init(&config.Images{ImageMaxSize: 1 << 20, ImageMaxPixels: 2_000_000, ImageServeMode: imaging.ServeStream, ImagePresignTTL: time.Hour})
+	require.NoError(t, err)
+	return img
+}
+
+func testImage(w, h int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: alpha})
+		}
+	}
+	return img
+}
+
+// withOrientation puts an EXIF segment with the orientation tag right after
+// the SOI marker of a JPEG.
+func withOrientation(t *testing.T, data []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("II*\x00")
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	var out bytes.Buffer
+	out.Write(data[:2])
+	out.Write([]byte{0xff, 0xe1})
+	require.NoError(t, binary.Write(&out, binary.BigEndian, uint16(len(segment)+2)))
+	out.Write(segment)
+	out.Write(data[2:])
+	return out.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		data string
+		mime string
+	}{
+		{"\xff\xd8\xff\xe0", imaging.MIMEJPEG},
+		{"\x89PNG\r\n\x1a\n....", imaging.MIMEPNG},
+		{"GIF89a", imaging.MIMEGIF},
+		{"RIFF\x00\x00\x00\x00WEBPVP8 ", imaging.MIMEWebP},
+		{"<svg></svg>", ""},
+		{"%PDF-1.7", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.mime, imaging.Detect([]byte(test.data)), "%q", test.data)
+	}
+}
+
+func TestProcessJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(1000, 500, 255), nil))
+	data := withOrientation(t, buf.Bytes(), 6)
+
+	result, err := newImaging(t).Process(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, imaging.MIMEJPEG, result.MIME)
+	assert.Equal(t, 500, result.Width)
+	assert.Equal(t, 1000, result.Height)
+	assert.False(t, bytes.Contains(result.Data, []byte("Exif")))
+
+	require.Len(t, result.Thumbnails, 1)
+	assert.Equal(t, imaging.MIMEJPEG, result.Thumbnails[0].MIME)
+	assert.Equal(t, 320, result.Thumbnails[0].Width)
+	assert.Equal(t, 640, result.Thumbnails[0].Height)
+
+	config, err := jpeg.DecodeConfig(bytes.NewReader(result.Thumbnails[0].Data))
+	require.NoError(t, err)
+	assert.Equal(t, 320, config.Width)
+}
+
+func TestProcessPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(1200, 600, 128)))
+
+	result, err := newImaging(t).Process(buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, imaging.MIMEPNG, result.MIME)
+	assert.Equal(t, 1200, result.Width)
+	require.Len(t, result.Thumbnails, 2)
+	assert.Equal(t, 320, result.Thumbnails[0].Width)
+	assert.Equal(t, 160, result.Thumbnails[0].Height)
+	assert.Equal(t, 960, result.Thumbnails[1].Width)
+
+	img, err := png.Decode(bytes.NewReader(result.Data))
+	require.NoError(t, err)
+	_, _, _, a := img.At(10, 10).RGBA()
+	assert.Less(t, a, uint32(0xffff))
+}
+
+func TestProcessWebP(t *testing.T) {
+	data, err := os.ReadFile("testdata/gopher.webp")
+	require.NoError(t, err)
+	require.Equal(t, imaging.MIMEWebP, imaging.Detect(data))
+
+	result, err := newImaging(t).Process(data)
+	require.NoError(t, err)
+
+	// WebP cannot be encoded, it is stored and named as PNG
+	assert.Equal(t, imaging.MIMEPNG, result.MIME)
+	assert.Equal(t, ".png", imaging.Ext(result.MIME))
+	_, err = png.Decode(bytes.NewReader(result.Data))
+	assert.NoError(t, err)
+	for _, thumbnail := range result.Thumbnails {
+		assert.Equal(t, imaging.MIMEPNG, thumbnail.MIME)
+	}
+}
+
+func TestProcessGIF(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	g := &gif.GIF{
+		Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 100, 50), palette), image.NewPaletted(image.Rect(0, 0, 100, 50), palette)},
+		Delay: []int{10, 10},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+
+	result, err := newImaging(t).Process(buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, imaging.MIMEGIF, result.MIME)
+	assert.Equal(t, 100, result.Width)
+	assert.Empty(t, result.Thumbnails)
+
+	decoded, err := gif.DecodeAll(bytes.NewReader(result.Data))
+	require.NoError(t, err)
+	assert.Len(t, decoded.Image, 2)
+}
+
+func TestProcessGIFFrames(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	frame := image.NewPaletted(image.Rect(0, 0, 1000, 500), palette)
+	g := &gif.GIF{Image: []*image.Paletted{frame, frame, frame, frame, frame}, Delay: []int{10, 10, 10, 10, 10}}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+
+	// every frame fits, all five together do not
+	_, err := newImaging(t).Process(buf.Bytes())
+	assert.True(t, errors.Is(err, imaging.ErrTooLarge))
+
+	_, err = newImaging(t).Process(buf.Bytes()[:buf.Len()-20])
+	assert.True(t, errors.Is(err, imaging.ErrNotImage))
+}
+
+func TestProcessRejects(t *testing.T) {
+	img := newImaging(t)
+
+	_, err := img.Process([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.True(t, errors.Is(err, imaging.ErrNotImage))
+
+	_, err = img.Process([]byte("\xff\xd8\xff\xe0 broken"))
+	assert.True(t, errors.Is(err, imaging.ErrNotImage))
+
+	_, err = img.Process(make([]byte, 2<<20))
+	assert.True(t, errors.Is(err, imaging.ErrTooLarge))
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2000, 1001))))
+	_, err = img.Process(buf.Bytes())
+	assert.True(t, errors.Is(err, imaging.ErrTooLarge))
+}
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		conf  config.Images
+		valid bool
+	}{
+		{config.Images{ImageMaxSize: 1, ImageMaxPixels: 1, ImageServeMode: imaging.ServeRedirect, ImagePresignTTL: time.Hour}, true},
+		{config.Images{ImageMaxSize: 1, ImageMaxPixels: 1, ImageServeMode: imaging.ServeStream, ImagePresignTTL: imaging.MaxPresignTTL}, true},
+		{config.Images{ImageMaxSize: 0, ImageMaxPixels: 1, ImageServeMode: imaging.ServeRedirect, ImagePresignTTL: time.Hour}, false},
+		{config.Images{ImageMaxSize: 1, ImageMaxPixels: 0, ImageServeMode: imaging.ServeRedirect, ImagePresignTTL: time.Hour}, false},
+		{config.Images{ImageMaxSize: 1, ImageMaxPixels: 1, ImageServeMode: "proxy", ImagePresignTTL: time.Hour}, false},
+		{config.Images{ImageMaxSize: 1, ImageMaxPixels: 1, ImageServeMode: imaging.ServeRedirect, ImagePresignTTL: 8 * 24 * time.Hour}, false},
+	}
+
+	for _, test := range tests {
+		_, err := imaging.Init(&test.conf)
+		assert.Equal(t, test.valid, err == nil, "%+v", test.conf)
+	}
+}
@@ -0,0 +1,63 @@
+// Package imaging checks uploaded event images, strips their metadata and
+// makes thumbnails.
+package imaging
+
+import (
+	"fmt"
+	"graduation/internal/config"
+	"time"
+)
+
+// How images are served.
+const (
+	ServeRedirect = "redirect"
+	ServeStream   = "stream"
+)
+
+// MaxPresignTTL is the longest lifetime of a presigned S3 URL.
+const MaxPresignTTL = 7 * 24 * time.Hour
+
+type Imaging struct {
+	maxSize    int64
+	maxPixels  int
+	serveMode  string
+	presignTTL time.Duration
+}
+
+func Init(conf *config.Images) (*Imaging, error) {
+	if conf.ImageMaxSize <= 0 {
+		return nil, fmt.Errorf("image max size must be positive: %d", conf.ImageMaxSize)
+	}
+
+	if conf.ImageMaxPixels <= 0 {
+		return nil, fmt.Errorf("image max pixels must be positive: %d", conf.ImageMaxPixels)
+	}
+
+	if conf.ImageServeMode != ServeRedirect && conf.ImageServeMode != ServeStream {
+		return nil, fmt.Errorf("unknown image serve mode: %s", conf.ImageServeMode)
+	}
+
+	if conf.ImagePresignTTL < time.Second || conf.ImagePresignTTL > MaxPresignTTL {
+		return nil, fmt.Errorf("image presign ttl must be between 1s and %s: %s", MaxPresignTTL, conf.ImagePresignTTL)
+	}
+
+	return &Imaging{
+		maxSize:    conf.ImageMaxSize,
+		maxPixels:  conf.ImageMaxPixels,
+		serveMode:  conf.ImageServeMode,
+		presignTTL: conf.ImagePresignTTL,
+	}, nil
+}
+
+// MaxSize is the largest accepted file in bytes.
+func (i *Imaging) MaxSize() int64 {
+	return i.maxSize
+}
+
+func (i *Imaging) ServeMode() string {
+	return i.serveMode
+}
+
+func (i *Imaging) PresignTTL() time.Duration {
+	return i.presignTTL
+}
@@ -0,0 +1,206 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const jpegQuality = 85
+
+// thumbnailWidths are made for every image wider than them.
+var thumbnailWidths = []int{320, 960}
+
+var (
+	ErrNotImage = errors.New("not a JPEG, PNG, GIF or WebP image")
+	ErrTooLarge = errors.New("image too large")
+)
+
+// Image is an encoded image ready for the object storage.
+type Image struct {
+	Data   []byte
+	MIME   string
+	Width  int
+	Height int
+}
+
+// Result is the cleaned image and its thumbnails, smallest first.
+type Result struct {
+	Image
+	Thumbnails []Image
+}
+
+// Process decodes data and encodes it again, which drops EXIF and every
+// other piece of metadata. A JPEG is turned upright first and stays a JPEG,
+// a GIF keeps its frames, PNG and WebP are stored as PNG. Thumbnails are
+// JPEG for a JPEG and PNG otherwise.
+func (i *Imaging) Process(data []byte) (*Result, error) {
+	if int64(len(data)) > i.maxSize {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d", ErrTooLarge, len(data), i.maxSize)
+	}
+
+	mime := Detect(data)
+	conf, err := decodeConfig(mime, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	if conf.Width*conf.Height > i.maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d, at most %d pixels", ErrTooLarge, conf.Width, conf.Height, i.maxPixels)
+	}
+
+	if mime == MIMEGIF {
+		frames, err := gifFrames(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+		}
+		if frames*conf.Width*conf.Height > i.maxPixels {
+			return nil, fmt.Errorf("%w: %d frames of %dx%d, at most %d pixels", ErrTooLarge, frames, conf.Width, conf.Height, i.maxPixels)
+		}
+
+		return processGIF(data)
+	}
+
+	img, err := decode(mime, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	src := toNRGBA(img)
+	out := MIMEPNG
+	if mime == MIMEJPEG {
+		src = orient(src, orientation(data))
+		out = MIMEJPEG
+	}
+
+	original, err := encode(src, out)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode image: %w", err)
+	}
+
+	thumbnails, err := makeThumbnails(src, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Image: *original, Thumbnails: thumbnails}, nil
+}
+
+func processGIF(data []byte) (*Result, error) {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		return nil, fmt.Errorf("cannot encode gif: %w", err)
+	}
+
+	first := toNRGBA(g.Image[0])
+	thumbnails, err := makeThumbnails(first, MIMEPNG)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Image: Image{
+			Data:   buf.Bytes(),
+			MIME:   MIMEGIF,
+			Width:  g.Config.Width,
+			Height: g.Config.Height,
+		},
+		Thumbnails: thumbnails,
+	}, nil
+}
+
+func makeThumbnails(src *image.NRGBA, mime string) ([]Image, error) {
+	var thumbnails []Image
+	for _, width := range thumbnailWidths {
+		if width >= src.Bounds().Dx() {
+			break
+		}
+
+		thumbnail, err := encode(resize(src, width), mime)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode thumbnail: %w", err)
+		}
+		thumbnails = append(thumbnails, *thumbnail)
+	}
+
+	return thumbnails, nil
+}
+
+func decodeConfig(mime string, data []byte) (image.Config, error) {
+	r := bytes.NewReader(data)
+	switch mime {
+	case MIMEJPEG:
+		return jpeg.DecodeConfig(r)
+	case MIMEPNG:
+		return png.DecodeConfig(r)
+	case MIMEGIF:
+		return gif.DecodeConfig(r)
+	case MIMEWebP:
+		return webp.DecodeConfig(r)
+	}
+
+	return image.Config{}, errors.New("unknown format")
+}
+
+func decode(mime string, data []byte) (image.Image, error) {
+	r := bytes.NewReader(data)
+	switch mime {
+	case MIMEJPEG:
+		return jpeg.Decode(r)
+	case MIMEPNG:
+		return png.Decode(r)
+	case MIMEWebP:
+		return webp.Decode(r)
+	}
+
+	return nil, errors.New("unknown format")
+}
+
+func encode(img *image.NRGBA, mime string) (*Image, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if mime == MIMEJPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		Data:   buf.Bytes(),
+		MIME:   mime,
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}, nil
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+func resize(src *image.NRGBA, width int) *image.NRGBA {
+	b := src.Bounds()
+	height := max(1, (b.Dy()*width+b.Dx()/2)/b.Dx())
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
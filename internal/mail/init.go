@@ -8,7 +8,8 @@ import (
 )
 
 type mailData struct {
-	from string
+	from     string
+	fromName string
 }
 
 type Mail struct {
@@ -28,14 +29,18 @@ func (m *Mail) CheckConnection() error {
 	return nil
 }
 
-func Init(conf *config.SMTP) (*Mail, error) {
-	mail := Mail{
+func New(conf *config.SMTP) *Mail {
+	return &Mail{
 		Con:      gomail.NewDialer(conf.SMTPServer, conf.SMTPPort, conf.SMTPUsername, conf.SMTPPassword),
-		mailData: mailData{from: conf.From},
+		mailData: mailData{from: conf.From, fromName: conf.FromName},
 	}
+}
+
+func Init(conf *config.SMTP) (*Mail, error) {
+	mail := New(conf)
 	if err := mail.CheckConnection(); err != nil {
 		return nil, fmt.Errorf("cannot connect: %w", err)
 	}
 
-	return &mail, nil
+	return mail, nil
 }
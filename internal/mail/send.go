@@ -2,22 +2,47 @@ package mail
 
 import (
 	"fmt"
+	"io"
 
 	"gopkg.in/gomail.v2"
 )
 
-func (m *Mail) Send(to, body string, urls []string) error {
+// QRName is the inline attachment name, templates refer to it as cid:ticket-qr.png.
+const QRName = "ticket-qr.png"
+
+// CalendarName is the name of the attached iCalendar file.
+const CalendarName = "event.ics"
+
+// SendMessage sends a multipart/alternative email: text for clients without
+// HTML and body. The QR code goes inline, calendar as an attachment; either
+// may be nil.
+func (m *Mail) SendMessage(to, subject, body, text string, qr, calendar []byte) error {
 	message := gomail.NewMessage()
-	message.SetAddressHeader("From", m.from, "EVENT.NE")
+	message.SetAddressHeader("From", m.from, m.fromName)
 	message.SetAddressHeader("To", to, "")
-	message.SetHeader("Subject", "Event in 3 hours")
+	message.SetHeader("Subject", subject)
 	// for i, image := range urls {
 	// 	cid := "image" + strconv.Itoa(i)
 	// 	body = strings.Replace(body, image, "cid:"+cid, 1)
 	// 	message.Embed(image, gomail.Rename(cid))
 	// 	message.EmbedURL(image, gomail.Rename(cid))
 	// }
-	message.SetBody("text/html", body)
+	message.SetBody("text/plain", text)
+	message.AddAlternative("text/html", body)
+	if qr != nil {
+		message.Embed(QRName, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(qr)
+			return err
+		}))
+	}
+	if calendar != nil {
+		message.Attach(CalendarName,
+			gomail.SetHeader(map[string][]string{"Content-Type": {"text/calendar; charset=utf-8"}}),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(calendar)
+				return err
+			}))
+	}
 
 	if err := m.Con.DialAndSend(message); err != nil {
 		return fmt.Errorf("failed to send mail: %w", err)
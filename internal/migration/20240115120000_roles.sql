@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'attendee';
+
+UPDATE users SET role = 'organizer'
+WHERE id IN (SELECT DISTINCT user_id FROM event);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS role;
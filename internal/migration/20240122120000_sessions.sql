@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS sessions (
+	id 				SERIAL PRIMARY KEY,
+	user_id			INT REFERENCES users(id) ON DELETE CASCADE,
+	refresh_hash	TEXT NOT NULL,
+	expires_at		timestamp NOT NULL,
+	created_at		timestamp DEFAULT now(),
+	revoked 		BOOLEAN DEFAULT FALSE,
+	UNIQUE 			(refresh_hash)
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS sessions;
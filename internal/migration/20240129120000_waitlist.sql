@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS waitlist (
+	id 			SERIAL PRIMARY KEY,
+	event_id	INT REFERENCES event(id) ON DELETE CASCADE,
+	user_id		INT REFERENCES users(id) ON DELETE CASCADE,
+	created_at	timestamp DEFAULT now(),
+	UNIQUE 		(event_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS waitlist;
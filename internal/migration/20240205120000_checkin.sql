@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE ticket ADD COLUMN IF NOT EXISTS redeemed_at timestamp;
+ALTER TABLE ticket ADD COLUMN IF NOT EXISTS redeemed_by INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_token_idx ON ticket (token);
+
+-- +goose Down
+DROP INDEX IF EXISTS ticket_token_idx;
+ALTER TABLE ticket DROP COLUMN IF EXISTS redeemed_by;
+ALTER TABLE ticket DROP COLUMN IF EXISTS redeemed_at;
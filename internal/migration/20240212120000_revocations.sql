@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS ticket_revocation (
+	token		TEXT PRIMARY KEY,
+	event_id	INT REFERENCES event(id) ON DELETE CASCADE,
+	revoked_at	timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS ticket_revocation_event_idx ON ticket_revocation (event_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS ticket_revocation;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS event_audit (
+	id 			SERIAL PRIMARY KEY,
+	event_id	INT REFERENCES event(id) ON DELETE CASCADE,
+	user_id		INT REFERENCES users(id) ON DELETE SET NULL,
+	field		TEXT NOT NULL,
+	old_value	TEXT NOT NULL,
+	new_value	TEXT NOT NULL,
+	changed_at	timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS event_audit_event_idx ON event_audit (event_id);
+
+CREATE TABLE IF NOT EXISTS event_change (
+	id 			SERIAL PRIMARY KEY,
+	event_id	INT REFERENCES event(id) ON DELETE CASCADE,
+	user_id		INT REFERENCES users(id) ON DELETE CASCADE,
+	changes		JSONB NOT NULL,
+	created_at	timestamp NOT NULL DEFAULT now(),
+	send 		BOOLEAN DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS event_change_send_idx ON event_change (send) WHERE send = FALSE;
+
+-- +goose Down
+DROP TABLE IF EXISTS event_change;
+DROP TABLE IF EXISTS event_audit;
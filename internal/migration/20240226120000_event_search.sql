@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE event ADD COLUMN IF NOT EXISTS search tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(place, '')), 'B') ||
+		setweight(to_tsvector('russian', coalesce(description, '')), 'C')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS event_search_idx ON event USING GIN (search);
+CREATE INDEX IF NOT EXISTS event_active_date_idx ON event (date) WHERE active = true;
+
+-- +goose Down
+DROP INDEX IF EXISTS event_active_date_idx;
+DROP INDEX IF EXISTS event_search_idx;
+ALTER TABLE event DROP COLUMN IF EXISTS search;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS calendar_token (
+	user_id		INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	token_hash	TEXT NOT NULL UNIQUE,
+	created_at	timestamp NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS calendar_token;
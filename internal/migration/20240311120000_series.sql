@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS series (
+	id 					SERIAL PRIMARY KEY,
+	user_id				INT REFERENCES users(id) ON DELETE CASCADE,
+	title 				TEXT NOT NULL,
+	description 		TEXT NOT NULL,
+	place 				TEXT NOT NULL,
+	max_participants	INT DEFAULT 0,
+	start 				timestamp NOT NULL,
+	rrule				TEXT NOT NULL,
+	materialized_until	timestamp NOT NULL,
+	active 				BOOLEAN DEFAULT TRUE
+);
+
+ALTER TABLE event ADD COLUMN IF NOT EXISTS series_id INT REFERENCES series(id) ON DELETE SET NULL;
+ALTER TABLE event ADD COLUMN IF NOT EXISTS occurrence timestamp;
+
+CREATE UNIQUE INDEX IF NOT EXISTS event_series_occurrence_idx ON event (series_id, occurrence);
+
+CREATE TABLE IF NOT EXISTS series_exception (
+	series_id	INT REFERENCES series(id) ON DELETE CASCADE,
+	occurrence	timestamp NOT NULL,
+	PRIMARY KEY	(series_id, occurrence)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS series_exception;
+DROP INDEX IF EXISTS event_series_occurrence_idx;
+ALTER TABLE event DROP COLUMN IF EXISTS occurrence;
+ALTER TABLE event DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox (
+	id 					SERIAL PRIMARY KEY,
+	key 				TEXT NOT NULL UNIQUE,
+	user_id				INT NOT NULL,
+	event_id			INT NOT NULL DEFAULT 0,
+	mail 				TEXT NOT NULL,
+	subject 			TEXT NOT NULL,
+	body 				TEXT NOT NULL,
+	token 				TEXT NOT NULL DEFAULT '',
+	status 				TEXT NOT NULL DEFAULT 'pending',
+	attempts 			INT NOT NULL DEFAULT 0,
+	next_attempt_at 	timestamp NOT NULL DEFAULT now(),
+	last_error 			TEXT NOT NULL DEFAULT '',
+	created_at 			timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS outbox_status_idx ON outbox (status, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
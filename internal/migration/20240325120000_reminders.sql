@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS event_reminder (
+	event_id	INT PRIMARY KEY REFERENCES event(id) ON DELETE CASCADE,
+	minutes		INT[] NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reminder_override (
+	event_id	INT NOT NULL,
+	user_id		INT NOT NULL,
+	minutes		INT[] NOT NULL,
+	PRIMARY KEY	(event_id, user_id),
+	FOREIGN KEY	(event_id, user_id) REFERENCES record(event_id, user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS reminder_sent (
+	event_id	INT NOT NULL,
+	user_id		INT NOT NULL,
+	minutes		INT NOT NULL,
+	PRIMARY KEY	(event_id, user_id, minutes),
+	FOREIGN KEY	(event_id, user_id) REFERENCES record(event_id, user_id) ON DELETE CASCADE
+);
+
+DROP TABLE IF EXISTS today;
+
+-- +goose Down
+CREATE TABLE IF NOT EXISTS today (
+	id 			SERIAL PRIMARY KEY,
+	event_id	INT REFERENCES event(id) ON DELETE CASCADE,
+	user_id		INT REFERENCES users(id) ON DELETE CASCADE,
+	date 		timestamp,
+	send 		BOOLEAN DEFAULT FALSE,
+	UNIQUE 		(event_id, user_id)
+);
+
+DROP TABLE IF EXISTS reminder_sent;
+DROP TABLE IF EXISTS reminder_override;
+DROP TABLE IF EXISTS event_reminder;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_channel (
+	user_id		INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	channel		TEXT NOT NULL,
+	address		TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY	(user_id, channel)
+);
+
+ALTER TABLE outbox RENAME COLUMN mail TO address;
+ALTER TABLE outbox ADD COLUMN channel TEXT NOT NULL DEFAULT 'email';
+ALTER TABLE outbox ADD COLUMN text TEXT NOT NULL DEFAULT '';
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_key_key;
+ALTER TABLE outbox ADD CONSTRAINT outbox_key_channel_key UNIQUE (key, channel);
+
+-- +goose Down
+DELETE FROM outbox WHERE channel <> 'email';
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_key_channel_key;
+ALTER TABLE outbox ADD CONSTRAINT outbox_key_key UNIQUE (key);
+ALTER TABLE outbox DROP COLUMN text;
+ALTER TABLE outbox DROP COLUMN channel;
+ALTER TABLE outbox RENAME COLUMN address TO mail;
+
+DROP TABLE IF EXISTS user_channel;
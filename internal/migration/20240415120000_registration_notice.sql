@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS registration_notice (
+	id 			SERIAL PRIMARY KEY,
+	event_id	INT NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+	user_id		INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	kind 		TEXT NOT NULL,
+	created_at 	timestamp NOT NULL DEFAULT now(),
+	send 		BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS registration_notice_unsent_idx ON registration_notice (id) WHERE send = FALSE;
+CREATE INDEX IF NOT EXISTS registration_notice_created_idx ON registration_notice (event_id, created_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS signup_digest BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS digest_sent_at timestamp NOT NULL DEFAULT now();
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS calendar TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS calendar;
+
+ALTER TABLE users DROP COLUMN IF EXISTS digest_sent_at;
+ALTER TABLE users DROP COLUMN IF EXISTS signup_digest;
+
+DROP TABLE IF EXISTS registration_notice;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mail_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- users registered before verification keep access to events
+UPDATE users SET mail_verified = TRUE;
+
+CREATE TABLE IF NOT EXISTS password_reset (
+	token_hash	TEXT PRIMARY KEY,
+	user_id		INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	expires_at	timestamp NOT NULL,
+	used_at 	timestamp
+);
+
+CREATE INDEX IF NOT EXISTS password_reset_user_idx ON password_reset (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS password_reset;
+
+ALTER TABLE users DROP COLUMN IF EXISTS mail_verified;
@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE photo
+	ADD COLUMN IF NOT EXISTS mime	TEXT NOT NULL DEFAULT 'application/octet-stream',
+	ADD COLUMN IF NOT EXISTS width	INT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS height	INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS photo_thumbnail (
+	id 			SERIAL PRIMARY KEY,
+	photo_id	INT NOT NULL REFERENCES photo(id) ON DELETE CASCADE,
+	name 		TEXT NOT NULL,
+	mime		TEXT NOT NULL,
+	width		INT NOT NULL,
+	height		INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS photo_thumbnail_photo_idx ON photo_thumbnail (photo_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS photo_thumbnail;
+
+ALTER TABLE photo
+	DROP COLUMN IF EXISTS mime,
+	DROP COLUMN IF EXISTS width,
+	DROP COLUMN IF EXISTS height;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE photo
+	ADD COLUMN IF NOT EXISTS position	INT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cover		BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- existing photos keep upload order, the first one of each event is the cover
+UPDATE photo SET position = numbered.position
+FROM (
+	SELECT id, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY id) - 1 AS position
+	FROM photo
+) AS numbered
+WHERE photo.id = numbered.id;
+
+UPDATE photo SET cover = TRUE WHERE position = 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS photo_cover_idx ON photo (event_id) WHERE cover;
+
+-- +goose Down
+DROP INDEX IF EXISTS photo_cover_idx;
+
+ALTER TABLE photo
+	DROP COLUMN IF EXISTS position,
+	DROP COLUMN IF EXISTS cover;
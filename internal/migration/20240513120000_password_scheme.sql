@@ -0,0 +1,9 @@
+-- +goose Up
+-- passwords stored before hashing get an explicit scheme, so a plaintext
+-- password that looks like a hash is never parsed as one
+UPDATE users SET password = 'plain:' || password
+WHERE password !~ '^\$argon2id\$v=[0-9]+\$m=[0-9]+,t=[0-9]+,p=[0-9]+\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$'
+	AND password !~ '^\$2[aby]\$[0-9]{2}\$[./A-Za-z0-9]{53}$';
+
+-- +goose Down
+UPDATE users SET password = SUBSTRING(password FROM 7) WHERE password LIKE 'plain:%';
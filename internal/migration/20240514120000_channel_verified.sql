@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE user_channel ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE user_channel DROP COLUMN IF EXISTS verified;
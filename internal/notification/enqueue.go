@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+)
+
+// enqueue queues notice for every channel the user picked, by email to mail
+// when the user picked none the service can deliver over. An email address
+// that is not verified yet is skipped.
+func (n *Notification) enqueue(ctx context.Context, mail string, notice entity.OutboxMail) error {
+	channels, err := n.storage.UserChannels(ctx, notice.UserID)
+	if err != nil {
+		return fmt.Errorf("cannot get user channels: %w", err)
+	}
+
+	var mails []entity.OutboxMail
+	for _, ch := range channels {
+		if _, ok := n.channels[ch.Channel]; !ok {
+			logger.Error("channel %s of user %d is off", ch.Channel, notice.UserID)
+			continue
+		}
+		if ch.Unverified() {
+			logger.Error("email channel of user %d is not verified", notice.UserID)
+			continue
+		}
+
+		m := notice
+		m.Channel, m.Address = ch.Channel, ch.Address
+		if ch.Channel == entity.ChannelEmail && ch.Address == "" {
+			m.Address = mail
+		}
+		mails = append(mails, m)
+	}
+
+	if len(mails) == 0 {
+		notice.Channel, notice.Address = entity.ChannelEmail, mail
+		mails = append(mails, notice)
+	}
+
+	return n.storage.EnqueueMail(ctx, mails)
+}
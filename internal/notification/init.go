@@ -1,18 +1,55 @@
 package notification
 
 import (
+	"graduation/internal/channel"
 	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/mail"
+	"graduation/internal/qr"
 	"graduation/internal/storage"
+	"graduation/internal/templates"
+	"strings"
+	"time"
 )
 
 type Notification struct {
-	storage storage.Storage
-	conf    *config.SMTP
+	storage   storage.Storage
+	channels  map[string]channel.Channel
+	qr        *qr.QR
+	templates *templates.Registry
+	baseURL   string
+	location  *time.Location
+	queued    chan struct{}
 }
 
-func Init(st storage.Storage, conf *config.SMTP) *Notification {
+func Init(st storage.Storage, smtp *config.SMTP, conf *config.Channels, qr *qr.QR, tmpl *templates.Registry, baseURL string, loc *time.Location) *Notification {
+	channels := map[string]channel.Channel{
+		entity.ChannelEmail: channel.NewSMTP(mail.New(smtp)),
+	}
+	if conf.WebhookEnabled {
+		channels[entity.ChannelWebhook] = channel.NewWebhook(conf.WebhookSecret, conf.WebhookHosts())
+	}
+	if conf.BotToken != "" {
+		channels[entity.ChannelBot] = channel.NewBot(conf.BotAPIURL, conf.BotToken)
+	}
+
 	return &Notification{
-		storage: st,
-		conf:    conf,
+		storage:   st,
+		channels:  channels,
+		qr:        qr,
+		templates: tmpl,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		location:  loc,
+		queued:    make(chan struct{}, 1),
+	}
+}
+
+// Wake tells the loop that notices or emails were queued, so they go out
+// without waiting for the tickers. A wake-up that is already pending covers
+// this one. Notices queued by other processes are found by polling.
+func (n *Notification) Wake() {
+	select {
+	case n.queued <- struct{}{}:
+	default:
 	}
 }
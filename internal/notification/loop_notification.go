@@ -2,50 +2,82 @@ package notification
 
 import (
 	"context"
+	"graduation/internal/entity"
 	"graduation/internal/logger"
-	"graduation/internal/mail"
 	"time"
 )
 
-func (n *Notification) LoopNotification() {
-	tickerSend := time.NewTicker(1 * time.Hour)
+// startOfDay is midnight of the day of date, events of earlier days are
+// finished.
+func startOfDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
+
+// LoopNotification queues and delivers notifications until ctx is
+// cancelled. It stops between emails, the rest of a batch stays in the
+// outbox.
+func (n *Notification) LoopNotification(ctx context.Context) {
+	timerReminder := time.NewTimer(0)
 	tickerGet := time.NewTicker(2 * time.Hour)
-	defer tickerSend.Stop()
+	tickerChange := time.NewTicker(1 * time.Minute)
+	tickerOutbox := time.NewTicker(30 * time.Second)
+	tickerDigest := time.NewTicker(1 * time.Hour)
+	defer timerReminder.Stop()
 	defer tickerGet.Stop()
+	defer tickerChange.Stop()
+	defer tickerOutbox.Stop()
+	defer tickerDigest.Stop()
 
-	var con *mail.Mail
-	con, err := mail.Init(n.conf)
-	if err != nil {
-		logger.Error("cannot init mail: %v", err)
-	}
-
-	if err := n.storage.EventsToday(context.Background(), time.Now()); err != nil {
-		logger.Error("cannot get evens: %v", err)
+	if err := n.storage.CloseFinishedEvents(context.Background(), startOfDay(time.Now())); err != nil {
+		logger.Error("cannot close events: %v", err)
 	}
 
-	if err := n.sendNotification(con); err != nil {
-		logger.Error("cannot send message: %v", err)
+	if err := n.storage.MaterializeSeries(context.Background(), time.Now().Add(entity.SeriesHorizon)); err != nil {
+		logger.Error("cannot materialize series: %v", err)
 	}
 
 	logger.Info("Start Notification")
 	for {
 		select {
+		case <-ctx.Done():
+			logger.Info("Stop Notification")
+			return
 		case <-tickerGet.C:
 			date := time.Now()
-			if err := n.storage.EventsToday(context.Background(), date.Add(6*time.Hour)); err != nil {
-				logger.Error("cannot get evens: %v", err)
-			}
-		case <-tickerSend.C:
-			if err := con.CheckConnection(); err != nil {
-				con, err = mail.Init(n.conf)
-				if err != nil {
-					logger.Error("cannot send message: %v", err)
-				}
-				logger.Error("cannot: %v", err)
-			} else {
-				if err := n.sendNotification(con); err != nil {
-					logger.Error("cannot send message: %v", err)
-				}
+			if err := n.storage.CloseFinishedEvents(context.Background(), startOfDay(date)); err != nil {
+				logger.Error("cannot close events: %v", err)
+			}
+			if err := n.storage.MaterializeSeries(context.Background(), date.Add(entity.SeriesHorizon)); err != nil {
+				logger.Error("cannot materialize series: %v", err)
+			}
+		case <-tickerChange.C:
+			if err := n.enqueueChanges(); err != nil {
+				logger.Error("cannot queue changes: %v", err)
+			}
+			if err := n.enqueueRegistrations(); err != nil {
+				logger.Error("cannot queue registrations: %v", err)
+			}
+		case <-n.queued:
+			if err := n.enqueueRegistrations(); err != nil {
+				logger.Error("cannot queue registrations: %v", err)
+			}
+			if err := n.deliverOutbox(ctx); err != nil {
+				logger.Error("cannot deliver outbox: %v", err)
+			}
+		case <-tickerDigest.C:
+			if err := n.enqueueDigests(time.Now()); err != nil {
+				logger.Error("cannot queue digests: %v", err)
+			}
+		case <-timerReminder.C:
+			now := time.Now()
+			if err := n.enqueueReminders(now); err != nil {
+				logger.Error("cannot queue reminders: %v", err)
+			}
+			timerReminder.Reset(n.nextReminder(now))
+		case <-tickerOutbox.C:
+			if err := n.deliverOutbox(ctx); err != nil {
+				logger.Error("cannot deliver outbox: %v", err)
 			}
 		}
 	}
@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"context"
+	"graduation/internal/channel"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/storage/mock"
+	"graduation/internal/templates"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChannel records the messages it is asked to send and fails while err
+// is set.
+type fakeChannel struct {
+	mu   sync.Mutex
+	sent []channel.Message
+	err  error
+}
+
+func (f *fakeChannel) Send(_ context.Context, _ string, msg channel.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// newTestNotification delivers email through a fakeChannel and keeps
+// everything else in st.
+func newTestNotification(t *testing.T, st *mock.MockStorage) *Notification {
+	require.NoError(t, logger.InitLogger(config.Logger{LoggerFilePath: "file.log", LoggerFileFlag: false, LoggerMultiFlag: false}))
+
+	tmpl, err := templates.New("")
+	require.NoError(t, err)
+
+	return &Notification{
+		storage:   st,
+		channels:  map[string]channel.Channel{entity.ChannelEmail: &fakeChannel{}},
+		templates: tmpl,
+		baseURL:   "https://example.com",
+		queued:    make(chan struct{}, 1),
+	}
+}
+
+func TestWake(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	n := newTestNotification(t, mock.NewMockStorage(c))
+
+	// wake-ups before the loop reads one are merged
+	n.Wake()
+	n.Wake()
+	select {
+	case <-n.queued:
+	default:
+		t.Fatal("loop is not woken")
+	}
+	select {
+	case <-n.queued:
+		t.Fatal("loop is woken twice")
+	default:
+	}
+}
+
+func TestEnqueueUnverifiedEmail(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+
+	tests := []struct {
+		name     string
+		channels []entity.UserChannel
+		want     string
+	}{
+		{name: "verified", channels: []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru", Verified: true}}, want: "work@mail.ru"},
+		{name: "not verified", channels: []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru"}}, want: "ivan@mail.ru"},
+		{name: "account", channels: []entity.UserChannel{{Channel: entity.ChannelEmail}}, want: "ivan@mail.ru"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			st.EXPECT().UserChannels(gomock.Any(), 2).Return(test.channels, nil)
+			st.EXPECT().EnqueueMail(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, mails []entity.OutboxMail) error {
+				require.Len(t, mails, 1)
+				assert.Equal(t, test.want, mails[0].Address)
+				return nil
+			})
+
+			require.NoError(t, n.enqueue(context.Background(), "ivan@mail.ru", entity.OutboxMail{Key: "key", UserID: 2}))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/channel"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"sync"
+	"time"
+)
+
+const (
+	// outboxBatch is how many emails one delivery run claims.
+	outboxBatch = 100
+	// outboxWorkers is how many emails are sent at the same time.
+	outboxWorkers = 4
+	// outboxLease hides claimed emails from other runs while they are sent.
+	outboxLease = 5 * time.Minute
+	// maxAttempts failed attempts move an email to the dead letters.
+	maxAttempts = 8
+
+	backoffBase = time.Minute
+	backoffMax  = 6 * time.Hour
+)
+
+// backoff is the wait after the given failed attempt: 1, 2, 4... minutes up
+// to backoffMax.
+func backoff(attempt int) time.Duration {
+	wait := backoffBase
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= backoffMax {
+			return backoffMax
+		}
+	}
+	return wait
+}
+
+// deliverOutbox sends the due emails of the outbox with a pool of workers.
+// Cancelling stop lets the workers finish the emails they are sending and
+// leaves the rest for the next run.
+func (n *Notification) deliverOutbox(stop context.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxLease)
+	defer cancel()
+
+	mails, err := n.storage.ClaimMail(ctx, outboxBatch, outboxLease)
+	if err != nil {
+		return fmt.Errorf("cannot claim mail: %w", err)
+	}
+
+	jobs := make(chan entity.OutboxMail)
+	errCh := make(chan error, len(mails))
+
+	var wg sync.WaitGroup
+	for i := 0; i < outboxWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if err := n.deliver(ctx, job); err != nil {
+					errCh <- err
+				}
+			}
+		}()
+	}
+
+	for _, job := range mails {
+		if stop.Err() != nil {
+			break
+		}
+		jobs <- job
+	}
+	close(jobs)
+
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliver sends one email and records the result. A failed send is
+// retried after backoff, after maxAttempts it is dead.
+func (n *Notification) deliver(ctx context.Context, job entity.OutboxMail) error {
+	err := n.send(ctx, job)
+	if err == nil {
+		if err := n.storage.MailSent(ctx, job.ID); err != nil {
+			return fmt.Errorf("cannot mark mail %d sent: %w", job.ID, err)
+		}
+		return nil
+	}
+
+	attempt := job.Attempts + 1
+	if attempt >= maxAttempts {
+		logger.Error("mail %d dead after %d attempts: %v", job.ID, attempt, err)
+		if err := n.storage.MailDead(ctx, job.ID, err.Error()); err != nil {
+			return fmt.Errorf("cannot mark mail %d dead: %w", job.ID, err)
+		}
+		return nil
+	}
+
+	logger.Error("cannot send mail %d, attempt %d: %v", job.ID, attempt, err)
+	if err := n.storage.MailRetry(ctx, job.ID, err.Error(), time.Now().Add(backoff(attempt))); err != nil {
+		return fmt.Errorf("cannot retry mail %d: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+func (n *Notification) send(ctx context.Context, job entity.OutboxMail) error {
+	ch, ok := n.channels[job.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is off", job.Channel)
+	}
+
+	var png []byte
+	if job.Token != "" {
+		var err error
+		png, err = n.qr.TicketPNG(job.Token)
+		if err != nil {
+			return fmt.Errorf("cannot render qr: %w", err)
+		}
+	}
+
+	var calendar []byte
+	if job.Calendar != "" {
+		calendar = []byte(job.Calendar)
+	}
+
+	return ch.Send(ctx, job.Address, channel.Message{
+		Key:      job.Key,
+		EventID:  job.EventID,
+		Subject:  job.Subject,
+		HTML:     job.Body,
+		Text:     job.Text,
+		Token:    job.Token,
+		QR:       png,
+		Calendar: calendar,
+	})
+}
@@ -0,0 +1,212 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/channel"
+	"graduation/internal/entity"
+	"graduation/internal/storage/mock"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Minute},
+		{attempt: 2, want: 2 * time.Minute},
+		{attempt: 3, want: 4 * time.Minute},
+		{attempt: 9, want: 256 * time.Minute},
+		{attempt: 10, want: backoffMax},
+		{attempt: 50, want: backoffMax},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.attempt), func(t *testing.T) {
+			assert.Equal(t, test.want, backoff(test.attempt))
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	type mockBehavior func(st *mock.MockStorage, start time.Time)
+
+	tests := []struct {
+		name         string
+		job          entity.OutboxMail
+		sendErr      error
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "sent",
+			job:  entity.OutboxMail{ID: 1, Channel: entity.ChannelEmail},
+			mockBehavior: func(st *mock.MockStorage, _ time.Time) {
+				st.EXPECT().MailSent(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			name:    "first failure waits backoffBase",
+			job:     entity.OutboxMail{ID: 1, Channel: entity.ChannelEmail},
+			sendErr: errors.New("timeout"),
+			mockBehavior: func(st *mock.MockStorage, start time.Time) {
+				st.EXPECT().MailRetry(gomock.Any(), 1, "timeout", gomock.Any()).DoAndReturn(func(_ context.Context, _ int, _ string, next time.Time) error {
+					assert.WithinRange(t, next, start.Add(backoffBase), time.Now().Add(backoffBase))
+					return nil
+				})
+			},
+		},
+		{
+			name:    "later failure waits longer",
+			job:     entity.OutboxMail{ID: 1, Channel: entity.ChannelEmail, Attempts: 3},
+			sendErr: errors.New("timeout"),
+			mockBehavior: func(st *mock.MockStorage, start time.Time) {
+				st.EXPECT().MailRetry(gomock.Any(), 1, "timeout", gomock.Any()).DoAndReturn(func(_ context.Context, _ int, _ string, next time.Time) error {
+					assert.WithinRange(t, next, start.Add(8*time.Minute), time.Now().Add(8*time.Minute))
+					return nil
+				})
+			},
+		},
+		{
+			name:    "last failure is dead",
+			job:     entity.OutboxMail{ID: 1, Channel: entity.ChannelEmail, Attempts: maxAttempts - 1},
+			sendErr: errors.New("bounced"),
+			mockBehavior: func(st *mock.MockStorage, _ time.Time) {
+				st.EXPECT().MailDead(gomock.Any(), 1, "bounced").Return(nil)
+			},
+		},
+		{
+			name: "channel turned off is retried",
+			job:  entity.OutboxMail{ID: 1, Channel: entity.ChannelWebhook},
+			mockBehavior: func(st *mock.MockStorage, _ time.Time) {
+				st.EXPECT().MailRetry(gomock.Any(), 1, "channel webhook is off", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "storage error",
+			job:  entity.OutboxMail{ID: 1, Channel: entity.ChannelEmail},
+			mockBehavior: func(st *mock.MockStorage, _ time.Time) {
+				st.EXPECT().MailSent(gomock.Any(), 1).Return(errors.New("err"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			st := mock.NewMockStorage(c)
+			n := newTestNotification(t, st)
+			email := &fakeChannel{err: test.sendErr}
+			n.channels[entity.ChannelEmail] = email
+
+			test.mockBehavior(st, time.Now())
+
+			err := n.deliver(context.Background(), test.job)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// poolChannel holds every message for a while and remembers how many were
+// sent at the same time.
+type poolChannel struct {
+	mu     sync.Mutex
+	active int
+	peak   int
+	sent   int
+}
+
+func (p *poolChannel) Send(_ context.Context, _ string, _ channel.Message) error {
+	p.mu.Lock()
+	p.active++
+	p.peak = max(p.peak, p.active)
+	p.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	p.mu.Lock()
+	p.active--
+	p.sent++
+	p.mu.Unlock()
+	return nil
+}
+
+func outboxMails(count int) []entity.OutboxMail {
+	mails := make([]entity.OutboxMail, count)
+	for i := range mails {
+		mails[i] = entity.OutboxMail{ID: i + 1, Channel: entity.ChannelEmail}
+	}
+	return mails
+}
+
+func TestDeliverOutbox(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+	pool := &poolChannel{}
+	n.channels[entity.ChannelEmail] = pool
+
+	st.EXPECT().ClaimMail(gomock.Any(), outboxBatch, outboxLease).Return(outboxMails(20), nil)
+	st.EXPECT().MailSent(gomock.Any(), gomock.Any()).Return(nil).Times(20)
+
+	require.NoError(t, n.deliverOutbox(context.Background()))
+	assert.Equal(t, 20, pool.sent)
+	assert.Equal(t, outboxWorkers, pool.peak)
+}
+
+func TestDeliverOutboxErrors(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+
+	st.EXPECT().ClaimMail(gomock.Any(), outboxBatch, outboxLease).Return(outboxMails(3), nil)
+	st.EXPECT().MailSent(gomock.Any(), 1).Return(nil)
+	st.EXPECT().MailSent(gomock.Any(), 2).Return(errors.New("first"))
+	st.EXPECT().MailSent(gomock.Any(), 3).Return(errors.New("second"))
+
+	err := n.deliverOutbox(context.Background())
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "cannot mark mail 2 sent: first")
+	assert.ErrorContains(t, err, "cannot mark mail 3 sent: second")
+
+	st.EXPECT().ClaimMail(gomock.Any(), outboxBatch, outboxLease).Return(nil, errors.New("err"))
+	assert.Error(t, n.deliverOutbox(context.Background()))
+}
+
+func TestDeliverOutboxStopped(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+	email := &fakeChannel{}
+	n.channels[entity.ChannelEmail] = email
+
+	stop, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the claimed emails come back after the lease
+	st.EXPECT().ClaimMail(gomock.Any(), outboxBatch, outboxLease).Return(outboxMails(3), nil)
+
+	require.NoError(t, n.deliverOutbox(stop))
+	assert.Empty(t, email.sent)
+}
@@ -0,0 +1,119 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/ics"
+	"graduation/internal/templates"
+	"strconv"
+	"time"
+)
+
+const (
+	// registrationBatch limits how many registration notices one run moves
+	// to the outbox.
+	registrationBatch = 100
+	// digestEvery is how often an organizer gets the digest of new signups.
+	digestEvery = 24 * time.Hour
+)
+
+var noticeTemplates = map[string]string{
+	entity.NoticeRegistration: templates.Registration,
+	entity.NoticeCancellation: templates.Cancellation,
+	entity.NoticePromotion:    templates.Promotion,
+}
+
+// enqueueRegistrations puts registration, cancellation and waitlist emails
+// into the outbox. They go by email, the only channel that carries the
+// ticket and the calendar file.
+func (n *Notification) enqueueRegistrations() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	notices, err := n.storage.GetRegistrationNotices(ctx, registrationBatch)
+	if err != nil {
+		return fmt.Errorf("cannot get registration notices: %w", err)
+	}
+
+	for _, notice := range notices {
+		name, ok := noticeTemplates[notice.Kind]
+		if !ok {
+			return fmt.Errorf("unknown registration notice: %s", notice.Kind)
+		}
+
+		event, err := n.storage.GetEvent(ctx, notice.EventID)
+		if err != nil {
+			return fmt.Errorf("cannot get event: %w", err)
+		}
+
+		msg, err := n.templates.Render(notice.Locale, name, templates.Data{Event: event, Token: notice.Token})
+		if err != nil {
+			return fmt.Errorf("cannot render %s: %w", notice.Kind, err)
+		}
+
+		calendar := ics.Calendar{Name: event.Title, Location: n.location, Events: []entity.Event{*event}}
+		if notice.Kind == entity.NoticeCancellation {
+			calendar.Method = ics.MethodCancel
+		}
+
+		err = n.storage.EnqueueMail(ctx, []entity.OutboxMail{{
+			Key:      notice.Kind + ":" + strconv.Itoa(notice.ID),
+			UserID:   notice.UserID,
+			EventID:  notice.EventID,
+			Channel:  entity.ChannelEmail,
+			Address:  notice.Mail,
+			Subject:  msg.Subject,
+			Body:     msg.HTML,
+			Text:     msg.Text,
+			Token:    notice.Token,
+			Calendar: string(calendar.Encode(time.Now())),
+		}})
+		if err != nil {
+			return fmt.Errorf("cannot enqueue %s: %w", notice.Kind, err)
+		}
+
+		if err := n.storage.RegistrationNoticeSent(ctx, notice.ID); err != nil {
+			return fmt.Errorf("cannot update registration notice: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// enqueueDigests puts the due digests of new signups into the outbox.
+func (n *Notification) enqueueDigests(now time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	digests, err := n.storage.DueDigests(ctx, now, digestEvery)
+	if err != nil {
+		return fmt.Errorf("cannot get digests: %w", err)
+	}
+
+	for _, digest := range digests {
+		msg, err := n.templates.Render(digest.Locale, templates.Digest, templates.Data{Digest: &digest})
+		if err != nil {
+			return fmt.Errorf("cannot render digest: %w", err)
+		}
+
+		err = n.storage.EnqueueMail(ctx, []entity.OutboxMail{{
+			Key:     fmt.Sprintf("digest:%d:%d", digest.UserID, now.Unix()),
+			UserID:  digest.UserID,
+			Channel: entity.ChannelEmail,
+			Address: digest.Mail,
+			Subject: msg.Subject,
+			Body:    msg.HTML,
+			Text:    msg.Text,
+		}})
+		if err != nil {
+			return fmt.Errorf("cannot enqueue digest: %w", err)
+		}
+
+		if err := n.storage.DigestSent(ctx, digest.UserID, now); err != nil {
+			return fmt.Errorf("cannot update digest: %w", err)
+		}
+	}
+
+	return nil
+}
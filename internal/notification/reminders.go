@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/templates"
+	"net/url"
+	"time"
+)
+
+const (
+	// reminderBatch limits how many due reminders one run queues.
+	reminderBatch = 500
+	// reminderRecheck is the longest the scheduler sleeps, so it notices
+	// new registrations and changed schedules.
+	reminderRecheck = 15 * time.Minute
+	// reminderMinWait keeps the scheduler from spinning on reminders that
+	// cannot be queued.
+	reminderMinWait = 10 * time.Second
+	// reminderImageTTL keeps image links working while the reminder waits
+	// in the outbox and in the inbox, it is the longest S3 allows.
+	reminderImageTTL = 7 * 24 * time.Hour
+)
+
+// enqueueReminders puts the due reminders into the outbox. When several
+// reminders of an attendee are due at once, only the latest one is sent.
+func (n *Notification) enqueueReminders(now time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	reminders, err := n.storage.DueReminders(ctx, now, reminderBatch)
+	if err != nil {
+		return fmt.Errorf("cannot get reminders: %w", err)
+	}
+
+	events := make(map[int]*entity.Event)
+	for start := 0; start < len(reminders); {
+		first := reminders[start]
+
+		// Reminders come ordered by minutes, the first of an attendee is
+		// the closest to the event.
+		minutes := []int{}
+		end := start
+		for ; end < len(reminders) && reminders[end].EventID == first.EventID && reminders[end].UserID == first.UserID; end++ {
+			minutes = append(minutes, reminders[end].Minutes)
+		}
+		start = end
+
+		event, ok := events[first.EventID]
+		if !ok {
+			event, err = n.reminderEvent(ctx, first.EventID)
+			if err != nil {
+				return err
+			}
+			events[first.EventID] = event
+		}
+
+		msg, err := n.templates.Render(first.Locale, templates.Reminder, templates.Data{Event: event, Minutes: first.Minutes})
+		if err != nil {
+			return fmt.Errorf("cannot render reminder: %w", err)
+		}
+
+		// A new date or a new registration clears the sent reminders, the
+		// key changes with them so the outbox does not drop the new ones.
+		err = n.enqueue(ctx, first.Mail, entity.OutboxMail{
+			Key:     fmt.Sprintf("reminder:%d:%d:%d", first.RecordID, first.Date.Unix(), first.Minutes),
+			UserID:  first.UserID,
+			EventID: first.EventID,
+			Subject: msg.Subject,
+			Body:    msg.HTML,
+			Text:    msg.Text,
+			Token:   first.Token,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot enqueue reminder: %w", err)
+		}
+
+		if err := n.storage.ReminderSent(ctx, first.EventID, first.UserID, minutes); err != nil {
+			return fmt.Errorf("cannot mark reminders sent: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reminderEvent is the event with links to its images. Blob stores without
+// presigned URLs are linked through /api/images on the public address.
+func (n *Notification) reminderEvent(ctx context.Context, eventID int) (*entity.Event, error) {
+	event, err := n.storage.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get event: %w", err)
+	}
+
+	for index, image := range event.Images {
+		link, err := n.storage.GetImage(ctx, image.Filename, reminderImageTTL)
+		if errors.Is(err, ostorage.ErrPresignNotSupported) {
+			link = n.baseURL + "/api/images/" + url.PathEscape(image.Filename)
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot get url: %w", err)
+		}
+		event.Images[index].Filename = link
+	}
+
+	return event, nil
+}
+
+// nextReminder is how long the scheduler sleeps after now.
+func (n *Notification) nextReminder(now time.Time) time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	next, err := n.storage.NextReminder(ctx, now)
+	if err != nil || next.IsZero() {
+		return reminderRecheck
+	}
+
+	wait := next.Sub(now)
+	if wait < reminderMinWait {
+		return reminderMinWait
+	}
+	if wait > reminderRecheck {
+		return reminderRecheck
+	}
+	return wait
+}
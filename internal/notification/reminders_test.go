@@ -0,0 +1,198 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage/memory"
+	"graduation/internal/storage/mock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderImagesWithoutPresign(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+	now := time.Now()
+
+	st.EXPECT().DueReminders(gomock.Any(), now, reminderBatch).Return([]entity.Reminder{
+		{EventID: 1, UserID: 2, Minutes: 60, Mail: "ivan@mail.ru", Locale: entity.LocaleEN, Token: "token"},
+	}, nil)
+	st.EXPECT().GetEvent(gomock.Any(), 1).Return(&entity.Event{
+		ID:     1,
+		Title:  "Meetup",
+		Date:   now.Add(time.Hour),
+		Images: []entity.Image{{Filename: "a b.png"}},
+	}, nil)
+	st.EXPECT().GetImage(gomock.Any(), "a b.png", reminderImageTTL).Return("", fmt.Errorf("cannot presign image: %w", ostorage.ErrPresignNotSupported))
+	st.EXPECT().UserChannels(gomock.Any(), 2).Return(nil, nil)
+	st.EXPECT().EnqueueMail(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, mails []entity.OutboxMail) error {
+		require.Len(t, mails, 1)
+		assert.Contains(t, mails[0].Body, `src="https://example.com/api/images/a%20b.png"`)
+		return nil
+	})
+	st.EXPECT().ReminderSent(gomock.Any(), 1, 2, []int{60}).Return(nil)
+
+	assert.NoError(t, n.enqueueReminders(now))
+}
+
+func TestEnqueueRemindersGroups(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+	now := time.Now()
+	date := time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC)
+
+	st.EXPECT().DueReminders(gomock.Any(), now, reminderBatch).Return([]entity.Reminder{
+		{EventID: 1, UserID: 2, RecordID: 4, Minutes: 60, Date: date, Mail: "ivan@mail.ru", Locale: entity.LocaleEN},
+		{EventID: 1, UserID: 2, RecordID: 4, Minutes: 180, Date: date, Mail: "ivan@mail.ru", Locale: entity.LocaleEN},
+		{EventID: 1, UserID: 3, RecordID: 5, Minutes: 60, Date: date, Mail: "petr@mail.ru", Locale: entity.LocaleRU},
+		{EventID: 2, UserID: 2, RecordID: 6, Minutes: 10, Date: date, Mail: "ivan@mail.ru", Locale: entity.LocaleEN},
+	}, nil)
+	// every event is read once
+	st.EXPECT().GetEvent(gomock.Any(), 1).Return(&entity.Event{ID: 1, Title: "Meetup", Date: now.Add(time.Hour)}, nil)
+	st.EXPECT().GetEvent(gomock.Any(), 2).Return(&entity.Event{ID: 2, Title: "Talk", Date: now.Add(10 * time.Minute)}, nil)
+	st.EXPECT().UserChannels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+
+	var keys []string
+	st.EXPECT().EnqueueMail(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, mails []entity.OutboxMail) error {
+		require.Len(t, mails, 1)
+		assert.Equal(t, entity.ChannelEmail, mails[0].Channel)
+		keys = append(keys, mails[0].Key)
+		return nil
+	}).Times(3)
+
+	// the closest reminder is sent, the earlier ones are marked with it
+	st.EXPECT().ReminderSent(gomock.Any(), 1, 2, []int{60, 180}).Return(nil)
+	st.EXPECT().ReminderSent(gomock.Any(), 1, 3, []int{60}).Return(nil)
+	st.EXPECT().ReminderSent(gomock.Any(), 2, 2, []int{10}).Return(nil)
+
+	require.NoError(t, n.enqueueReminders(now))
+	assert.Equal(t, []string{"reminder:4:1710095400:60", "reminder:5:1710095400:60", "reminder:6:1710095400:10"}, keys)
+}
+
+// tokens signs tickets with unique tokens.
+type tokens struct{ n int }
+
+func (g *tokens) Generate(tick *entity.Ticket) error {
+	g.n++
+	tick.Token = fmt.Sprintf("token-%d", g.n)
+	return nil
+}
+
+func TestEnqueueRemindersRescheduled(t *testing.T) {
+	ctx := context.Background()
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := memory.New(ostorage.NewMemory())
+	n := newTestNotification(t, mock.NewMockStorage(c))
+	n.storage = st
+	gen := &tokens{}
+	now := time.Now().UTC().Truncate(time.Second)
+
+	organizer, err := st.SetUser(ctx, "organizer", "hash", "organizer@mail.ru", entity.RoleOrganizer)
+	require.NoError(t, err)
+	attendee, err := st.SetUser(ctx, "ivan", "hash", "ivan@mail.ru", entity.RoleAttendee)
+	require.NoError(t, err)
+	event := &entity.Event{UserID: organizer, Title: "Meetup", MaxParticipants: 10, Date: now.Add(2 * time.Hour), Active: true}
+	require.NoError(t, st.CreateEvent(ctx, event))
+
+	register := func() {
+		tick := &entity.Ticket{EventID: event.ID, UserID: attendee, Exp: 2}
+		require.NoError(t, gen.Generate(tick))
+		_, err := st.AddEventUser(ctx, tick)
+		require.NoError(t, err)
+	}
+	queued := func() []string {
+		mails, _, err := st.OutboxMails(ctx, entity.OutboxPending, 10, 0)
+		require.NoError(t, err)
+		var keys []string
+		for _, mail := range mails {
+			keys = append(keys, mail.Key)
+		}
+		return keys
+	}
+
+	register()
+	require.NoError(t, n.enqueueReminders(now))
+	require.Len(t, queued(), 1)
+
+	// the new date brings the reminder back, it is queued again
+	moved := *event
+	moved.Date = event.Date.Add(30 * time.Minute)
+	require.NoError(t, st.UpdateEvent(ctx, organizer, &moved, nil, gen))
+	require.NoError(t, n.enqueueReminders(now))
+	require.Len(t, queued(), 2)
+
+	// so does a new registration
+	_, err = st.DellEventUser(ctx, event.ID, attendee, gen)
+	require.NoError(t, err)
+	register()
+	require.NoError(t, n.enqueueReminders(now))
+	keys := queued()
+	require.Len(t, keys, 3)
+	assert.NotEqual(t, keys[0], keys[1])
+	assert.NotEqual(t, keys[1], keys[2])
+	assert.NotEqual(t, keys[0], keys[2])
+}
+
+func TestEnqueueRemindersFailed(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	st := mock.NewMockStorage(c)
+	n := newTestNotification(t, st)
+	now := time.Now()
+
+	// a reminder that is not queued stays due for the next run
+	st.EXPECT().DueReminders(gomock.Any(), now, reminderBatch).Return([]entity.Reminder{
+		{EventID: 1, UserID: 2, Minutes: 60, Mail: "ivan@mail.ru", Locale: entity.LocaleEN},
+	}, nil)
+	st.EXPECT().GetEvent(gomock.Any(), 1).Return(&entity.Event{ID: 1, Title: "Meetup", Date: now.Add(time.Hour)}, nil)
+	st.EXPECT().UserChannels(gomock.Any(), 2).Return(nil, nil)
+	st.EXPECT().EnqueueMail(gomock.Any(), gomock.Any()).Return(errors.New("err"))
+
+	assert.Error(t, n.enqueueReminders(now))
+}
+
+func TestNextReminder(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		next time.Time
+		err  error
+		want time.Duration
+	}{
+		{name: "storage error", err: errors.New("err"), want: reminderRecheck},
+		{name: "nothing scheduled", want: reminderRecheck},
+		{name: "overdue", next: now.Add(-time.Minute), want: reminderMinWait},
+		{name: "soon", next: now.Add(5 * time.Minute), want: 5 * time.Minute},
+		{name: "far", next: now.Add(24 * time.Hour), want: reminderRecheck},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			st := mock.NewMockStorage(c)
+			n := newTestNotification(t, st)
+			st.EXPECT().NextReminder(gomock.Any(), now).Return(test.next, test.err)
+
+			assert.Equal(t, test.want, n.nextReminder(now))
+		})
+	}
+}
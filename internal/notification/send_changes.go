@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/templates"
+	"strconv"
+	"time"
+)
+
+// changeBatch limits how many queued "event changed" notices one tick moves
+// to the outbox.
+const changeBatch = 100
+
+func (n *Notification) enqueueChanges() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	notices, err := n.storage.GetChangeNotices(ctx, changeBatch)
+	if err != nil {
+		return fmt.Errorf("cannot get change notices: %w", err)
+	}
+
+	for _, notice := range notices {
+		event, err := n.storage.GetEvent(ctx, notice.EventID)
+		if err != nil {
+			return fmt.Errorf("cannot get event: %w", err)
+		}
+
+		msg, err := n.templates.Render(notice.Locale, templates.Changed, templates.Data{Event: event, Changes: notice.Changes})
+		if err != nil {
+			return fmt.Errorf("cannot render change: %w", err)
+		}
+
+		err = n.enqueue(ctx, notice.Mail, entity.OutboxMail{
+			Key:     "change:" + strconv.Itoa(notice.ID),
+			UserID:  notice.UserID,
+			EventID: notice.EventID,
+			Subject: msg.Subject,
+			Body:    msg.HTML,
+			Text:    msg.Text,
+			Token:   notice.Token,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot enqueue change: %w", err)
+		}
+
+		if err := n.storage.ChangeNoticeSent(ctx, notice.ID); err != nil {
+			return fmt.Errorf("cannot update change notice: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package ostorage keeps event images in an object storage: MinIO, a local
+// directory or memory.
+package ostorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/config"
+	"io"
+	"time"
+)
+
+const (
+	BackendMinIO      = "minio"
+	BackendFilesystem = "fs"
+	BackendMemory     = "memory"
+)
+
+var (
+	// ErrNotExist is returned for objects missing from the store.
+	ErrNotExist = errors.New("object not exist")
+	// ErrPresignNotSupported is returned by stores without their own HTTP
+	// endpoint, their objects are streamed by the service.
+	ErrPresignNotSupported = errors.New("presigned URLs not supported")
+)
+
+// Object describes an object of the store. ContentType and ETag are only
+// set by Get.
+type Object struct {
+	Name         string
+	ContentType  string
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+// BlobStore keeps objects by name. Put replaces an object with the same
+// name, Delete of a missing object is not an error.
+type BlobStore interface {
+	Put(ctx context.Context, name string, content []byte, contentType string) error
+	// Get returns the content of the object, it must be closed.
+	Get(ctx context.Context, name string) (io.ReadSeekCloser, *Object, error)
+	Delete(ctx context.Context, name string) error
+	Presign(ctx context.Context, name string, expires time.Duration) (string, error)
+	List(ctx context.Context) ([]Object, error)
+}
+
+// Init opens the store chosen by conf.StorageBackend.
+func Init(conf *config.ObjectStorage) (BlobStore, error) {
+	switch conf.StorageBackend {
+	case BackendMinIO:
+		return Connect(conf)
+	case BackendFilesystem:
+		return NewFilesystem(conf.StoragePath)
+	case BackendMemory:
+		return NewMemory(), nil
+	}
+
+	return nil, fmt.Errorf("unknown object storage backend: %s", conf.StorageBackend)
+}
+
+var (
+	_ BlobStore = (*MinIO)(nil)
+	_ BlobStore = (*Filesystem)(nil)
+	_ BlobStore = (*Memory)(nil)
+)
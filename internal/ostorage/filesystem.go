@@ -0,0 +1,151 @@
+package ostorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filesystem keeps objects as files of one directory. It needs no server,
+// so development machines can run without MinIO.
+type Filesystem struct {
+	root string
+}
+
+func NewFilesystem(root string) (*Filesystem, error) {
+	if root == "" {
+		return nil, errors.New("directory of the blob store is empty")
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot creat directory: %w", err)
+	}
+
+	return &Filesystem{root: root}, nil
+}
+
+// path returns the file of the object. Names are flat, anything that could
+// leave the directory or clash with temporary files is rejected.
+func (s *Filesystem) path(objectName string) (string, error) {
+	if objectName == "" || strings.HasPrefix(objectName, ".") || strings.ContainsAny(objectName, `/\`) {
+		return "", fmt.Errorf("invalid object name: %q", objectName)
+	}
+
+	return filepath.Join(s.root, objectName), nil
+}
+
+func (s *Filesystem) Put(_ context.Context, objectName string, fileContent []byte, _ string) error {
+	path, err := s.path(objectName)
+	if err != nil {
+		return err
+	}
+
+	// the file is written aside and renamed, so readers never see a part of it
+	file, err := os.CreateTemp(s.root, ".put-*")
+	if err != nil {
+		return fmt.Errorf("cannot creat file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(fileContent); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("cannot set file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Filesystem) Delete(_ context.Context, objectName string) error {
+	path, err := s.path(objectName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot dell file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Filesystem) Presign(_ context.Context, _ string, _ time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+// Get returns the content of the object. The content type follows the
+// extension of the name, the ETag changes with every write.
+func (s *Filesystem) Get(_ context.Context, objectName string) (io.ReadSeekCloser, *Object, error) {
+	path, err := s.path(objectName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrNotExist, objectName)
+		}
+		return nil, nil, fmt.Errorf("cannot open file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("cannot stat file: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(objectName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return file, &Object{
+		Name:         objectName,
+		ContentType:  contentType,
+		ETag:         fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size()),
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (s *Filesystem) List(_ context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list files: %w", err)
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("cannot stat file: %w", err)
+		}
+		objects = append(objects, Object{Name: entry.Name(), Size: info.Size(), LastModified: info.ModTime()})
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+
+	return objects, nil
+}
@@ -0,0 +1,98 @@
+package ostorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	content []byte
+	Object
+}
+
+// Memory keeps objects in memory, for tests and throwaway runs.
+type Memory struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemory() *Memory {
+	return &Memory{objects: make(map[string]memoryObject)}
+}
+
+func (s *Memory) Put(_ context.Context, objectName string, fileContent []byte, contentType string) error {
+	content := bytes.Clone(fileContent)
+	sum := md5.Sum(content)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[objectName] = memoryObject{
+		content: content,
+		Object: Object{
+			Name:         objectName,
+			ContentType:  contentType,
+			ETag:         hex.EncodeToString(sum[:]),
+			Size:         int64(len(content)),
+			LastModified: time.Now().UTC(),
+		},
+	}
+
+	return nil
+}
+
+func (s *Memory) Delete(_ context.Context, objectName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, objectName)
+
+	return nil
+}
+
+func (s *Memory) Presign(_ context.Context, _ string, _ time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *Memory) Get(_ context.Context, objectName string) (io.ReadSeekCloser, *Object, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[objectName]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotExist, objectName)
+	}
+
+	// content is never changed after Put, readers may share it
+	info := object.Object
+	return nopCloser{bytes.NewReader(object.content)}, &info, nil
+}
+
+func (s *Memory) List(_ context.Context) ([]Object, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objects := make([]Object, 0, len(s.objects))
+	for _, object := range s.objects {
+		objects = append(objects, Object{Name: object.Name, Size: object.Size, LastModified: object.LastModified})
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+
+	return objects, nil
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
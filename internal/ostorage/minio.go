@@ -0,0 +1,128 @@
+package ostorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"graduation/internal/config"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinIO keeps objects in a bucket of a MinIO or another S3 compatible
+// server.
+type MinIO struct {
+	client     *minio.Client
+	bucketName string
+}
+
+func Connect(conf *config.ObjectStorage) (*MinIO, error) {
+	client, err := minio.New(conf.StorageEndpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(conf.StorageAccessKey, conf.StorageSecretKey, ""),
+		Secure: conf.StorageUseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect: %w", err)
+	}
+
+	storage := MinIO{client: client, bucketName: conf.StorageBucketName}
+	if err := storage.creatBucket(); err != nil {
+		return nil, fmt.Errorf("cannot creat bucket: %w", err)
+	}
+
+	return &storage, nil
+}
+
+func (s *MinIO) creatBucket() error {
+	exists, err := s.client.BucketExists(context.Background(), s.bucketName)
+	if err != nil {
+		return fmt.Errorf("cannot exists: %w", err)
+	}
+
+	if !exists {
+		err = s.client.MakeBucket(context.Background(), s.bucketName, minio.MakeBucketOptions{})
+		if err != nil {
+			return fmt.Errorf("cannot creat bucket: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *MinIO) Put(ctx context.Context, objectName string, fileContent []byte, contentType string) error {
+	_, err := s.client.PutObject(
+		ctx,
+		s.bucketName,
+		objectName,
+		bytes.NewReader(fileContent),
+		int64(len(fileContent)),
+		minio.PutObjectOptions{ContentType: contentType},
+	)
+
+	if err != nil {
+		return fmt.Errorf("cannot set file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *MinIO) Delete(ctx context.Context, objectName string) error {
+	err := s.client.RemoveObject(
+		ctx,
+		s.bucketName,
+		objectName,
+		minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot set file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *MinIO) Presign(ctx context.Context, objectName string, expires time.Duration) (string, error) {
+	presignedURL, err := s.client.PresignedGetObject(ctx, s.bucketName, objectName, expires, nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot presign: %w", err)
+	}
+
+	return presignedURL.String(), nil
+}
+
+func (s *MinIO) Get(ctx context.Context, objectName string) (io.ReadSeekCloser, *Object, error) {
+	object, err := s.client.GetObject(ctx, s.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get object: %w", err)
+	}
+
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil, fmt.Errorf("%w: %s", ErrNotExist, objectName)
+		}
+		return nil, nil, fmt.Errorf("cannot stat object: %w", err)
+	}
+
+	return object, &Object{
+		Name:         info.Key,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (s *MinIO) List(ctx context.Context) ([]Object, error) {
+	var objects []Object
+	for info := range s.client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{Recursive: true}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("cannot list objects: %w", info.Err)
+		}
+		objects = append(objects, Object{Name: info.Key, Size: info.Size, LastModified: info.LastModified})
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,126 @@
+package ostorage_test
+
+import (
+	"context"
+	"graduation/internal/config"
+	"graduation/internal/ostorage"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlobStore(t *testing.T, store ostorage.BlobStore) {
+	ctx := context.Background()
+
+	_, _, err := store.Get(ctx, "abc.png")
+	assert.ErrorIs(t, err, ostorage.ErrNotExist)
+
+	require.NoError(t, store.Put(ctx, "abc.png", []byte("0123456789"), "image/png"))
+	require.NoError(t, store.Put(ctx, "abc_320.jpg", []byte("thumb"), "image/jpeg"))
+
+	content, object, err := store.Get(ctx, "abc.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	assert.Equal(t, "0123456789", string(data))
+	assert.Equal(t, "abc.png", object.Name)
+	assert.Equal(t, "image/png", object.ContentType)
+	assert.Equal(t, int64(10), object.Size)
+	assert.NotEmpty(t, object.ETag)
+	assert.False(t, object.LastModified.IsZero())
+
+	content, _, err = store.Get(ctx, "abc.png")
+	require.NoError(t, err)
+	_, err = content.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+	data, err = io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	assert.Equal(t, "6789", string(data))
+
+	objects, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "abc.png", objects[0].Name)
+	assert.Equal(t, int64(10), objects[0].Size)
+	assert.Equal(t, "abc_320.jpg", objects[1].Name)
+
+	require.NoError(t, store.Put(ctx, "abc.png", []byte("new"), "image/png"))
+	content, object, err = store.Get(ctx, "abc.png")
+	require.NoError(t, err)
+	data, err = io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	assert.Equal(t, "new", string(data))
+	assert.Equal(t, int64(3), object.Size)
+
+	require.NoError(t, store.Delete(ctx, "abc.png"))
+	require.NoError(t, store.Delete(ctx, "abc.png"))
+	_, _, err = store.Get(ctx, "abc.png")
+	assert.ErrorIs(t, err, ostorage.ErrNotExist)
+
+	objects, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "abc_320.jpg", objects[0].Name)
+
+	_, err = store.Presign(ctx, "abc_320.jpg", time.Hour)
+	assert.ErrorIs(t, err, ostorage.ErrPresignNotSupported)
+}
+
+func TestMemory(t *testing.T) {
+	testBlobStore(t, ostorage.NewMemory())
+}
+
+func TestFilesystem(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "images")
+
+	store, err := ostorage.NewFilesystem(dir)
+	require.NoError(t, err)
+	testBlobStore(t, store)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left")
+	assert.Equal(t, "abc_320.jpg", entries[0].Name())
+}
+
+func TestFilesystemName(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := ostorage.NewFilesystem(filepath.Join(dir, "images"))
+	require.NoError(t, err)
+
+	for _, name := range []string{"", ".", "..", "../abc.png", "a/b.png", `a\b.png`, ".put-1"} {
+		assert.Error(t, store.Put(ctx, name, []byte("x"), "image/png"), name)
+		_, _, err := store.Get(ctx, name)
+		assert.Error(t, err, name)
+		assert.Error(t, store.Delete(ctx, name), name)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "abc.png"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestInit(t *testing.T) {
+	store, err := ostorage.Init(&config.ObjectStorage{StorageBackend: ostorage.BackendMemory})
+	require.NoError(t, err)
+	assert.IsType(t, &ostorage.Memory{}, store)
+
+	store, err = ostorage.Init(&config.ObjectStorage{StorageBackend: ostorage.BackendFilesystem, StoragePath: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &ostorage.Filesystem{}, store)
+
+	_, err = ostorage.Init(&config.ObjectStorage{StorageBackend: ostorage.BackendFilesystem})
+	assert.Error(t, err)
+
+	_, err = ostorage.Init(&config.ObjectStorage{StorageBackend: "s3"})
+	assert.Error(t, err)
+}
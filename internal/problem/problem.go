@@ -0,0 +1,53 @@
+// Package problem writes error responses as RFC 7807 problem details.
+package problem
+
+import (
+	"encoding/json"
+	"graduation/internal/validation"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is the body of an error response. Type is always about:blank, so
+// Title is the HTTP status text and Detail says what went wrong.
+type Problem struct {
+	Type   string                  `json:"type"`
+	Title  string                  `json:"title"`
+	Status int                     `json:"status"`
+	Detail string                  `json:"detail,omitempty"`
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Invalid is the 400 answer to a request body that failed validation.
+func Invalid(errs validation.Errors) *Problem {
+	p := New(http.StatusBadRequest, "request has invalid fields")
+	p.Errors = errs
+	return p
+}
+
+func (p *Problem) Write(w http.ResponseWriter) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		w.WriteHeader(p.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	w.Write(body)
+}
+
+// Write answers with status and detail, detail may be empty.
+func Write(w http.ResponseWriter, status int, detail string) {
+	New(status, detail).Write(w)
+}
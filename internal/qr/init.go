@@ -0,0 +1,58 @@
+package qr
+
+import (
+	"fmt"
+	"graduation/internal/config"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	minSize = 64
+	maxSize = 1024
+)
+
+type QR struct {
+	size  int
+	level qrcode.RecoveryLevel
+}
+
+func ParseLevel(level string) (qrcode.RecoveryLevel, error) {
+	switch level {
+	case "L":
+		return qrcode.Low, nil
+	case "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	}
+
+	return 0, fmt.Errorf("unknown error correction level: %s", level)
+}
+
+func ValidSize(size int) bool {
+	return size >= minSize && size <= maxSize
+}
+
+func Init(conf *config.QRCode) (*QR, error) {
+	level, err := ParseLevel(conf.QRLevel)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse level: %w", err)
+	}
+
+	if !ValidSize(conf.QRSize) {
+		return nil, fmt.Errorf("size must be between %d and %d: %d", minSize, maxSize, conf.QRSize)
+	}
+
+	return &QR{size: conf.QRSize, level: level}, nil
+}
+
+func (q *QR) Size() int {
+	return q.size
+}
+
+func (q *QR) Level() qrcode.RecoveryLevel {
+	return q.level
+}
@@ -0,0 +1,47 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func PNG(content string, size int, level qrcode.RecoveryLevel) ([]byte, error) {
+	png, err := qrcode.Encode(content, level, size)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode: %w", err)
+	}
+
+	return png, nil
+}
+
+func SVG(content string, size int, level qrcode.RecoveryLevel) ([]byte, error) {
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode: %w", err)
+	}
+
+	bitmap := code.Bitmap()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, len(bitmap), len(bitmap))
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, len(bitmap), len(bitmap))
+	for y, row := range bitmap {
+		for x, black := range row {
+			if black {
+				fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="1" height="1" fill="#000000"/>`, x, y)
+			}
+		}
+	}
+	buf.WriteString(`</svg>`)
+
+	return buf.Bytes(), nil
+}
+
+// TicketPNG renders a ticket with the configured size and level, the form
+// that is embedded into emails.
+func (q *QR) TicketPNG(token string) ([]byte, error) {
+	return PNG(token, q.size, q.level)
+}
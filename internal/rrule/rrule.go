@@ -0,0 +1,274 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules that event
+// series use: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL,
+// BYDAY without ordinals and BYMONTHDAY. Weeks start on Monday.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+const (
+	untilLayout     = "20060102T150405Z"
+	untilDateLayout = "20060102"
+	// maxPeriods stops the expansion of rules whose filters never match.
+	maxPeriods = 100000
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+func Parse(s string) (*Rule, error) {
+	rule := Rule{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("bad rule part: %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			switch freq := Frequency(strings.ToUpper(value)); freq {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = freq
+			default:
+				return nil, fmt.Errorf("unsupported FREQ: %s", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("bad INTERVAL: %s", value)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("bad COUNT: %s", value)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := time.Parse(untilLayout, value)
+			if err != nil {
+				date, dateErr := time.Parse(untilDateLayout, value)
+				if dateErr != nil {
+					return nil, fmt.Errorf("bad UNTIL: %w", err)
+				}
+				until = date.Add(24*time.Hour - time.Second)
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY: %s", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("bad BYMONTHDAY: %s", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+			}
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				return nil, errors.New("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, errors.New("COUNT and UNTIL are mutually exclusive")
+	}
+	if rule.Freq == Yearly && (len(rule.ByDay) > 0 || len(rule.ByMonthDay) > 0) {
+		return nil, errors.New("BYDAY and BYMONTHDAY are not supported with FREQ=YEARLY")
+	}
+
+	return &rule, nil
+}
+
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			for name, day := range weekdays {
+				if day == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of a series starting at dtstart that fall
+// into [from, to], at most limit of them when limit > 0. COUNT is counted from
+// dtstart, not from from.
+func (r *Rule) Between(dtstart, from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	count := 0
+
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.period(dtstart, period)
+		if candidates == nil {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Before(dtstart) {
+				continue
+			}
+			if candidate.After(to) || (!r.Until.IsZero() && candidate.After(r.Until)) {
+				return occurrences
+			}
+
+			count++
+			if r.Count > 0 && count > r.Count {
+				return occurrences
+			}
+
+			if !candidate.Before(from) {
+				occurrences = append(occurrences, candidate)
+				if limit > 0 && len(occurrences) == limit {
+					return occurrences
+				}
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// period returns the sorted candidates of the n-th period of the rule.
+func (r *Rule) period(dtstart time.Time, n int) []time.Time {
+	step := n * r.Interval
+	hour, minute, second := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, dtstart.Location())
+	}
+
+	var candidates []time.Time
+	switch r.Freq {
+	case Daily:
+		day := dtstart.AddDate(0, 0, step)
+		if r.matchDay(day) {
+			candidates = append(candidates, day)
+		}
+	case Weekly:
+		monday := dtstart.AddDate(0, 0, -((int(dtstart.Weekday())+6)%7)+7*step)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{dtstart.Weekday()}
+		}
+		for _, weekday := range days {
+			candidates = append(candidates, monday.AddDate(0, 0, (int(weekday)+6)%7))
+		}
+	case Monthly:
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(step), 1)
+		last := first.AddDate(0, 1, -1).Day()
+		for day := 1; day <= last; day++ {
+			candidate := at(first.Year(), first.Month(), day)
+			if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+				if day == dtstart.Day() {
+					candidates = append(candidates, candidate)
+				}
+				continue
+			}
+			if r.matchDay(candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case Yearly:
+		candidate := at(dtstart.Year()+step, dtstart.Month(), dtstart.Day())
+		if candidate.Day() == dtstart.Day() {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	return candidates
+}
+
+func (r *Rule) matchDay(day time.Time) bool {
+	if len(r.ByDay) > 0 {
+		found := false
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay == day.Day() || (monthDay < 0 && last+monthDay+1 == day.Day()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
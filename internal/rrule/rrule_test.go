@@ -0,0 +1,142 @@
+package rrule_test
+
+import (
+	"graduation/internal/rrule"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestRuleBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		dtstart  string
+		from     string
+		to       string
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "weekly",
+			rule:     "FREQ=WEEKLY",
+			dtstart:  "2024-03-05 19:00",
+			from:     "2024-03-01 00:00",
+			to:       "2024-03-31 00:00",
+			expected: []string{"2024-03-05 19:00", "2024-03-12 19:00", "2024-03-19 19:00", "2024-03-26 19:00"},
+		},
+		{
+			name:     "weekly by day with count",
+			rule:     "RRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=3",
+			dtstart:  "2024-03-05 19:00",
+			from:     "2024-03-01 00:00",
+			to:       "2024-12-31 00:00",
+			expected: []string{"2024-03-05 19:00", "2024-03-07 19:00", "2024-03-12 19:00"},
+		},
+		{
+			name:     "count is counted from dtstart",
+			rule:     "FREQ=DAILY;COUNT=5",
+			dtstart:  "2024-03-01 10:00",
+			from:     "2024-03-04 00:00",
+			to:       "2024-03-31 00:00",
+			expected: []string{"2024-03-04 10:00", "2024-03-05 10:00"},
+		},
+		{
+			name:     "every second week until",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;UNTIL=20240402T000000Z",
+			dtstart:  "2024-03-04 09:00",
+			from:     "2024-03-01 00:00",
+			to:       "2024-12-31 00:00",
+			expected: []string{"2024-03-04 09:00", "2024-03-18 09:00", "2024-04-01 09:00"},
+		},
+		{
+			name:     "monthly skips short months",
+			rule:     "FREQ=MONTHLY",
+			dtstart:  "2024-01-31 18:00",
+			from:     "2024-01-01 00:00",
+			to:       "2024-05-31 23:59",
+			expected: []string{"2024-01-31 18:00", "2024-03-31 18:00", "2024-05-31 18:00"},
+		},
+		{
+			name:     "monthly last day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart:  "2024-01-31 18:00",
+			from:     "2024-01-01 00:00",
+			to:       "2024-04-30 23:59",
+			expected: []string{"2024-01-31 18:00", "2024-02-29 18:00", "2024-03-31 18:00", "2024-04-30 18:00"},
+		},
+		{
+			name:     "yearly leap day",
+			rule:     "FREQ=YEARLY",
+			dtstart:  "2024-02-29 12:00",
+			from:     "2024-01-01 00:00",
+			to:       "2032-12-31 00:00",
+			expected: []string{"2024-02-29 12:00", "2028-02-29 12:00", "2032-02-29 12:00"},
+		},
+		{
+			name:     "limit",
+			rule:     "FREQ=DAILY",
+			dtstart:  "2024-03-01 10:00",
+			from:     "2024-03-01 00:00",
+			to:       "2025-03-01 00:00",
+			limit:    2,
+			expected: []string{"2024-03-01 10:00", "2024-03-02 10:00"},
+		},
+		{
+			name:     "daily on weekdays",
+			rule:     "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			dtstart:  "2024-03-08 08:00",
+			from:     "2024-03-01 00:00",
+			to:       "2024-03-12 00:00",
+			expected: []string{"2024-03-08 08:00", "2024-03-11 08:00"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := rrule.Parse(test.rule)
+			require.NoError(t, err)
+
+			var got []string
+			for _, occurrence := range rule.Between(date(test.dtstart), date(test.from), date(test.to), test.limit) {
+				got = append(got, occurrence.Format("2006-01-02 15:04"))
+			}
+
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, bad := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101T000000Z",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=WEEKLY;BYSETPOS=1",
+	} {
+		_, err := rrule.Parse(bad)
+		assert.Error(t, err, bad)
+	}
+
+	rule, err := rrule.Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;UNTIL=20240601")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;UNTIL=20240601T235959Z;BYDAY=MO,FR", rule.String())
+
+	again, err := rrule.Parse(rule.String())
+	require.NoError(t, err)
+	assert.Equal(t, rule, again)
+}
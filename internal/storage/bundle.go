@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graduation/internal/entity"
+)
+
+func (s *storageData) EventTickets(ctx context.Context, eventID int) ([]entity.Ticket, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT token, user_id, active, redeemed_at
+		FROM ticket
+		WHERE event_id = $1
+	`, eventID)
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []entity.Ticket
+	for rows.Next() {
+		ticket := entity.Ticket{EventID: eventID}
+		var redeemedAt sql.NullTime
+		if err := rows.Scan(&ticket.Token, &ticket.UserID, &ticket.Status, &redeemedAt); err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		ticket.RedeemedAt = redeemedAt.Time
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, nil
+}
+
+func (s *storageData) RevokedTickets(ctx context.Context, eventID int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT token
+		FROM ticket_revocation
+		WHERE event_id = $1
+	`, eventID)
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get revocations: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// MergeCheckins applies a scanner log to the ticket table. The earliest scan
+// of a ticket wins, so merging the same log twice or logs from several doors
+// in any order gives the same result. Returns the number of updated tickets.
+func (s *storageData) MergeCheckins(ctx context.Context, eventID, scannerID int, checkins []entity.Checkin) (int, error) {
+	var merged int
+	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, checkin := range checkins {
+			rows, err := tx.ExecContext(ctx, `
+				UPDATE ticket
+					SET redeemed_at = $3, redeemed_by = $4
+					WHERE token = $1 AND event_id = $2 AND active = true
+						AND (redeemed_at IS NULL OR redeemed_at > $3)
+			`, checkin.Token, eventID, checkin.ScannedAt, scannerID)
+			if err != nil {
+				return fmt.Errorf("cannot UPDATE ticket: %w", err)
+			}
+
+			rowsAffected, err := rows.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("cannot get rows: %w", err)
+			}
+			merged += int(rowsAffected)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("cannot merge: %w", err)
+	}
+
+	return merged, nil
+}
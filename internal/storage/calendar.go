@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SetCalendarToken stores the hash of a user's calendar feed token. A user has
+// one token, setting a new one invalidates the old feed URL.
+func (s *storageData) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO calendar_token (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+			SET token_hash = EXCLUDED.token_hash, created_at = now()
+	`, userID, tokenHash)
+	if err != nil {
+		return fmt.Errorf("cannot set calendar token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *storageData) CalendarUser(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT user_id
+		FROM calendar_token
+		WHERE token_hash = $1
+	`, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, &NotFoundError{Err: errors.New("calendar token not exist")}
+		}
+		return 0, fmt.Errorf("cannot get calendar token: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+)
+
+// RedeemTicket marks an active ticket as used. The condition on redeemed_at
+// makes the update atomic, so only the first scan of a ticket succeeds.
+func (s *storageData) RedeemTicket(ctx context.Context, token string, scannerID int) (*entity.Ticket, error) {
+	ticket := &entity.Ticket{Token: token}
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE ticket
+			SET redeemed_at = now(), redeemed_by = $2
+			WHERE token = $1 AND active = true AND redeemed_at IS NULL
+		RETURNING user_id, event_id, active, redeemed_at, redeemed_by
+	`, token, scannerID).Scan(&ticket.UserID, &ticket.EventID, &ticket.Status, &ticket.RedeemedAt, &ticket.RedeemedBy)
+	if err == nil {
+		return ticket, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cannot redeem ticket: %w", err)
+	}
+
+	var redeemedAt sql.NullTime
+	var active bool
+	err = s.db.QueryRowContext(ctx, `
+		SELECT active, redeemed_at
+		FROM ticket
+		WHERE token = $1
+	`, token).Scan(&active, &redeemedAt)
+	if err != nil || !active {
+		return nil, &NotFoundError{Err: errors.New("ticket not exist")}
+	}
+
+	return nil, &ConflictError{Err: fmt.Errorf("ticket already redeemed at %v", redeemedAt.Time)}
+}
+
+func (s *storageData) CheckinCount(ctx context.Context, eventID int) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM ticket
+		WHERE event_id = $1 AND redeemed_at IS NOT NULL
+	`, eventID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("cannot count checkin: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+// NotFoundError means the row the caller asked about does not exist.
+type NotFoundError struct {
+	Err error
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError means the change clashes with the current state: a
+// duplicate, a ticket redeemed twice, fewer seats than registrations.
+type ConflictError struct {
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// ForbiddenError means the row exists but belongs to another user.
+type ForbiddenError struct {
+	Err error
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ForbiddenError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/rrule"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// eventWhere builds the WHERE clause shared by the page and the COUNT query,
+// so the total always matches the rows a page is cut from.
+func eventWhere(f *entity.EventFilter) (string, []any) {
+	args := []any{f.From, f.To}
+	where := []string{"active = true", "date BETWEEN $1 AND $2"}
+
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if f.Query != "" {
+		where = append(where, "search @@ websearch_to_tsquery('russian', "+arg(f.Query)+")")
+	}
+	if f.OrganizerID != 0 {
+		where = append(where, "user_id = "+arg(f.OrganizerID))
+	}
+	if f.Place != "" {
+		where = append(where, "place ILIKE '%' || "+arg(likeEscaper.Replace(f.Place))+" || '%'")
+	}
+	if f.MinSeats > 0 {
+		where = append(where, "max_participants - participants >= "+arg(f.MinSeats))
+	}
+
+	return strings.Join(where, " AND "), args
+}
+
+func eventOrder(f *entity.EventFilter, args []any) (string, []any) {
+	switch f.Sort {
+	case entity.SortDateDesc:
+		return "date DESC, id", args
+	case entity.SortSeats:
+		return "max_participants - participants DESC, date, id", args
+	case entity.SortPopularity:
+		return "participants DESC, date, id", args
+	case entity.SortRelevance:
+		if f.Query != "" {
+			args = append(args, f.Query)
+			return "ts_rank(search, websearch_to_tsquery('russian', $" + strconv.Itoa(len(args)) + ")) DESC, date, id", args
+		}
+	}
+
+	return "date, id", args
+}
+
+// virtualOccurrences returns the occurrences of active series in the listing
+// period up to f.SeriesUntil that lie past the events the series created.
+func (s *storageData) virtualOccurrences(ctx context.Context, f *entity.EventFilter) (seriesIDs, occurrences []string, err error) {
+	until := f.To
+	if f.SeriesUntil.Before(until) {
+		until = f.SeriesUntil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, start, rrule, materialized_until
+		FROM series
+		WHERE active = true AND materialized_until < $1 AND ($2 = 0 OR user_id = $2)
+	`, until, f.OrganizerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get series: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var series entity.Series
+		if err := rows.Scan(&series.ID, &series.Start, &series.RRule, &series.MaterializedUntil); err != nil {
+			return nil, nil, fmt.Errorf("cannot scan: %w", err)
+		}
+
+		rule, err := rrule.Parse(series.RRule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse rule of series %d: %w", series.ID, err)
+		}
+
+		from := f.From
+		if after := series.MaterializedUntil.Add(time.Second); after.After(from) {
+			from = after
+		}
+
+		for _, occurrence := range rule.Between(series.Start, from, until, maxOccurrences) {
+			seriesIDs = append(seriesIDs, strconv.Itoa(series.ID))
+			occurrences = append(occurrences, occurrence.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	return seriesIDs, occurrences, rows.Err()
+}
+
+// eventSource is what the listing selects from: the events, and with
+// f.SeriesUntil set also the occurrences of series that have no event yet.
+// These are listed with a zero id and are never written, so a listing that
+// looks far ahead does not create rows.
+func (s *storageData) eventSource(ctx context.Context, f *entity.EventFilter, args []any) (string, []any, error) {
+	if f.SeriesUntil.IsZero() {
+		return "event", args, nil
+	}
+
+	seriesIDs, occurrences, err := s.virtualOccurrences(ctx, f)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(seriesIDs) == 0 {
+		return "event", args, nil
+	}
+
+	args = append(args, strings.Join(seriesIDs, ","), strings.Join(occurrences, ","))
+	return `(
+		SELECT id, user_id, title, description, place, participants, max_participants, date, active, series_id, occurrence, search
+		FROM event
+		UNION ALL
+		SELECT 0, series.user_id, series.title, series.description, series.place, 0, series.max_participants,
+			virtual.occurrence, true, series.id, virtual.occurrence,
+			setweight(to_tsvector('russian', series.title), 'A') ||
+			setweight(to_tsvector('russian', series.place), 'B') ||
+			setweight(to_tsvector('russian', series.description), 'C')
+		FROM unnest(
+			string_to_array($` + strconv.Itoa(len(args)-1) + `, ',')::int[],
+			string_to_array($` + strconv.Itoa(len(args)) + `, ',')::timestamp[]
+		) AS virtual (series_id, occurrence)
+		JOIN series ON series.id = virtual.series_id
+		WHERE NOT EXISTS (
+				SELECT 1 FROM series_exception
+				WHERE series_exception.series_id = virtual.series_id AND series_exception.occurrence = virtual.occurrence
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM event
+				WHERE event.series_id = virtual.series_id AND event.occurrence = virtual.occurrence
+			)
+	) AS event`, args, nil
+}
+
+func (s *storageData) GetEvents(ctx context.Context, f *entity.EventFilter) ([]entity.Event, int, error) {
+	where, args := eventWhere(f)
+
+	source, args, err := s.eventSource(ctx, f, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot list series: %w", err)
+	}
+
+	var count int
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM `+source+`
+		WHERE `+where, args...).Scan(&count)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot get count event: %w", err)
+	}
+
+	order, args := eventOrder(f, args)
+	args = append(args, f.Limit, (f.Page-1)*f.Limit)
+
+	rowsE, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, title, description, place, participants, max_participants, date, active,
+			COALESCE(series_id, 0), COALESCE(occurrence, date)
+		FROM `+source+`
+		WHERE `+where+`
+		ORDER BY `+order+`, series_id
+		LIMIT $`+strconv.Itoa(len(args)-1)+` OFFSET $`+strconv.Itoa(len(args)), args...)
+	if err != nil || rowsE.Err() != nil {
+		return nil, 0, fmt.Errorf("cannot get events: %w", err)
+	}
+	defer rowsE.Close()
+
+	var events []entity.Event
+	for rowsE.Next() {
+		var event entity.Event
+		err := rowsE.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.Title,
+			&event.Description,
+			&event.Place,
+			&event.Participants,
+			&event.MaxParticipants,
+			&event.Date,
+			&event.Active,
+			&event.SeriesID,
+			&event.Occurrence)
+		if err != nil {
+			return nil, 0, fmt.Errorf("cannot scan: %w", err)
+		}
+
+		if event.ID != 0 {
+			event.Images, err = s.GetImages(ctx, event.ID)
+			if err != nil {
+				return nil, 0, fmt.Errorf("cannot get images: %w", err)
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events, count, nil
+}
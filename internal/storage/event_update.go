@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"time"
+)
+
+func lockEventRow(ctx context.Context, tx *sql.Tx, eventID int) (int, time.Time, error) {
+	var participants int
+	var date time.Time
+	err := tx.QueryRowContext(ctx, `
+		SELECT participants, date
+		FROM event
+		WHERE id = $1
+		FOR UPDATE
+	`, eventID).Scan(&participants, &date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, time.Time{}, &NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
+		}
+		return 0, time.Time{}, fmt.Errorf("cannot lock event: %w", err)
+	}
+
+	return participants, date, nil
+}
+
+// reissueTickets signs new tickets when the event moves, so their expiry
+// follows the new date. Old tokens go to ticket_revocation.
+func reissueTickets(ctx context.Context, tx *sql.Tx, eventID int, date time.Time, gen TicketGenerator) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT user_id, token
+		FROM ticket
+		WHERE event_id = $1
+	`, eventID)
+	if err != nil || rows.Err() != nil {
+		return fmt.Errorf("cannot get tickets: %w", err)
+	}
+
+	var tickets []entity.Ticket
+	for rows.Next() {
+		ticket := entity.Ticket{EventID: eventID, Exp: int(time.Until(date).Hours() + 0.5)}
+		if err := rows.Scan(&ticket.UserID, &ticket.Token); err != nil {
+			rows.Close()
+			return fmt.Errorf("cannot scan: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	rows.Close()
+
+	for _, ticket := range tickets {
+		old := ticket.Token
+		if err := gen.Generate(&ticket); err != nil {
+			return fmt.Errorf("cannot generate ticket: %w", err)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE ticket
+				SET token = $3, date = $4
+				WHERE event_id = $1 AND user_id = $2
+		`, eventID, ticket.UserID, ticket.Token, date)
+		if err != nil {
+			return fmt.Errorf("cannot UPDATE ticket: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO ticket_revocation (token, event_id)
+			VALUES ($1, $2)
+			ON CONFLICT (token) DO NOTHING
+		`, old, eventID)
+		if err != nil {
+			return fmt.Errorf("cannot revoke ticket: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateEvent saves edited fields of e, writes the audit and queues an
+// "event changed" notice for every registered attendee, all in one
+// transaction. Shrinking max_participants below participants is a
+// ConflictError, raising it promotes the waitlist into the new seats.
+func (s *storageData) UpdateEvent(ctx context.Context, userID int, e *entity.Event, changes []entity.EventChange, gen TicketGenerator) error {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("cannot json to byte: %w", err)
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		participants, oldDate, err := lockEventRow(ctx, tx, e.ID)
+		if err != nil {
+			return err
+		}
+
+		if e.MaxParticipants < participants {
+			return &ConflictError{Err: fmt.Errorf("max participants %d less than participants %d", e.MaxParticipants, participants)}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE event
+				SET title = $2, description = $3, place = $4, max_participants = $5, date = $6
+				WHERE id = $1
+		`, e.ID, e.Title, e.Description, e.Place, e.MaxParticipants, e.Date)
+		if err != nil {
+			return fmt.Errorf("cannot UPDATE event: %w", err)
+		}
+
+		if !oldDate.Equal(e.Date) {
+			if err := reissueTickets(ctx, tx, e.ID, e.Date, gen); err != nil {
+				return fmt.Errorf("cannot reissue tickets: %w", err)
+			}
+
+			_, err := tx.ExecContext(ctx, `
+				DELETE FROM reminder_sent WHERE event_id = $1
+			`, e.ID)
+			if err != nil {
+				return fmt.Errorf("cannot reset reminders: %w", err)
+			}
+		}
+
+		for _, change := range changes {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO event_audit (event_id, user_id, field, old_value, new_value)
+				VALUES ($1, $2, $3, $4, $5)
+			`, e.ID, userID, change.Field, change.Old, change.New)
+			if err != nil {
+				return fmt.Errorf("cannot INSERT audit: %w", err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO event_change (event_id, user_id, changes)
+				SELECT event_id, user_id, $2
+				FROM record
+				WHERE event_id = $1
+		`, e.ID, body)
+		if err != nil {
+			return fmt.Errorf("cannot INSERT event change: %w", err)
+		}
+
+		// promoted users get a promotion notice, not the change notice
+		for seats := e.MaxParticipants - participants; seats > 0; seats-- {
+			promoted, err := promoteWaitlist(ctx, tx, e.ID, gen)
+			if err != nil {
+				return fmt.Errorf("cannot promote waitlist: %w", err)
+			}
+			if promoted == nil {
+				break
+			}
+
+			if err := addNotice(ctx, tx, e.ID, promoted.UserID, entity.NoticePromotion); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("cannot update event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *storageData) GetChangeNotices(ctx context.Context, limit int) ([]entity.ChangeNotice, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT event_change.id, event_change.event_id, event_change.user_id, users.mail, users.locale, COALESCE(ticket.token, ''), event_change.changes
+		FROM event_change
+		JOIN users ON users.id = event_change.user_id
+		LEFT JOIN ticket ON ticket.event_id = event_change.event_id AND ticket.user_id = event_change.user_id
+		WHERE event_change.send = FALSE
+		ORDER BY event_change.id
+		LIMIT $1
+	`, limit)
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get event changes: %w", err)
+	}
+	defer rows.Close()
+
+	var notices []entity.ChangeNotice
+	for rows.Next() {
+		var notice entity.ChangeNotice
+		var changes []byte
+		err := rows.Scan(&notice.ID, &notice.EventID, &notice.UserID, &notice.Mail, &notice.Locale, &notice.Token, &changes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+
+		if err := json.Unmarshal(changes, &notice.Changes); err != nil {
+			return nil, fmt.Errorf("cannot parse changes: %w", err)
+		}
+		notices = append(notices, notice)
+	}
+
+	return notices, nil
+}
+
+func (s *storageData) ChangeNoticeSent(ctx context.Context, noticeID int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE event_change SET send = TRUE WHERE id = $1
+	`, noticeID)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE event change: %w", err)
+	}
+
+	return nil
+}
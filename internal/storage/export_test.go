@@ -0,0 +1,12 @@
+package storage
+
+import (
+	"database/sql"
+	"graduation/internal/ostorage"
+)
+
+// NewTestStorage wraps an open database for the tests of package
+// storage_test.
+func NewTestStorage(db *sql.DB, ost ostorage.BlobStore) Storage {
+	return &storageData{db: db, ost: ost}
+}
@@ -0,0 +1,420 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/ostorage"
+	"io"
+	"time"
+)
+
+// AddEventImages stores images of an existing event after the ones it
+// has. The rows and the objects go together: when an upload fails the rows
+// are rolled back and the objects already uploaded are removed.
+func (s *storageData) AddEventImages(ctx context.Context, eventID int, images []entity.Image) error {
+	return s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockEventImages(ctx, tx, eventID); err != nil {
+			return err
+		}
+
+		return s.setEventImages(ctx, tx, eventID, images)
+	})
+}
+
+// lockEventImages locks the event row, so changes of its images do not
+// interleave.
+func lockEventImages(ctx context.Context, tx *sql.Tx, eventID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM event WHERE id = $1 FOR UPDATE
+	`, eventID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
+		}
+		return fmt.Errorf("cannot lock event: %w", err)
+	}
+
+	return nil
+}
+
+// setEventImages appends images to the event, the first image of an event
+// without a cover becomes the cover.
+func (s *storageData) setEventImages(ctx context.Context, tx *sql.Tx, eventID int, images []entity.Image) error {
+	for index := range images {
+		image := &images[index]
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO photo (event_id, name, mime, width, height, position, cover)
+			VALUES ($1, $2, $3, $4, $5,
+				(SELECT COALESCE(MAX(position) + 1, 0) FROM photo WHERE event_id = $1),
+				NOT EXISTS (SELECT 1 FROM photo WHERE event_id = $1 AND cover))
+			RETURNING id, cover
+		`, eventID, image.Filename, image.MIME, image.Width, image.Height).Scan(&image.ID, &image.Cover)
+		if err != nil {
+			return fmt.Errorf("cannot set photo db: %w", err)
+		}
+
+		for _, thumbnail := range image.Thumbnails {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO photo_thumbnail (photo_id, name, mime, width, height)
+				VALUES ($1, $2, $3, $4, $5)
+			`, image.ID, thumbnail.Filename, thumbnail.MIME, thumbnail.Width, thumbnail.Height)
+			if err != nil {
+				return fmt.Errorf("cannot set thumbnail db: %w", err)
+			}
+		}
+	}
+
+	return s.putImages(ctx, images)
+}
+
+// putImages uploads the originals and thumbnails, on failure nothing of
+// this call is left in the bucket.
+func (s *storageData) putImages(ctx context.Context, images []entity.Image) error {
+	var put []string
+	set := func(name string, data []byte, mime string) error {
+		if err := s.ost.Put(ctx, name, data, mime); err != nil {
+			for _, name := range put {
+				s.ost.Delete(ctx, name)
+			}
+			return fmt.Errorf("cannot set photo ost: %w", err)
+		}
+		put = append(put, name)
+		return nil
+	}
+
+	for _, image := range images {
+		if err := set(image.Filename, image.Data, image.MIME); err != nil {
+			return err
+		}
+		for _, thumbnail := range image.Thumbnails {
+			if err := set(thumbnail.Filename, thumbnail.Data, thumbnail.MIME); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetImages returns the images of an event in display order with their
+// thumbnails, smallest first.
+func (s *storageData) GetImages(ctx context.Context, eventID int) ([]entity.Image, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.name, p.mime, p.width, p.height, p.cover, t.name, t.mime, t.width, t.height
+		FROM photo p
+		LEFT JOIN photo_thumbnail t ON t.photo_id = p.id
+		WHERE p.event_id = $1
+		ORDER BY p.position, p.id, t.width
+	`, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get images: %w", err)
+	}
+	defer rows.Close()
+
+	var images []entity.Image
+	for rows.Next() {
+		var image entity.Image
+		var thumbnailName, thumbnailMIME sql.NullString
+		var thumbnailWidth, thumbnailHeight sql.NullInt64
+		err := rows.Scan(&image.ID, &image.Filename, &image.MIME, &image.Width, &image.Height, &image.Cover,
+			&thumbnailName, &thumbnailMIME, &thumbnailWidth, &thumbnailHeight)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+
+		if len(images) == 0 || images[len(images)-1].ID != image.ID {
+			images = append(images, image)
+		}
+		if thumbnailName.Valid {
+			last := &images[len(images)-1]
+			last.Thumbnails = append(last.Thumbnails, entity.Thumbnail{
+				Filename: thumbnailName.String,
+				MIME:     thumbnailMIME.String,
+				Width:    int(thumbnailWidth.Int64),
+				Height:   int(thumbnailHeight.Int64),
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read images: %w", err)
+	}
+
+	return images, nil
+}
+
+// objectNames lists the objects of images, originals and thumbnails.
+func objectNames(images []entity.Image) []string {
+	var names []string
+	for _, image := range images {
+		names = append(names, image.Filename)
+		for _, thumbnail := range image.Thumbnails {
+			names = append(names, thumbnail.Filename)
+		}
+	}
+
+	return names
+}
+
+// removeObjects deletes objects whose rows are already gone. A failure only
+// leaves an orphan for CollectImageGarbage, so it is logged and not returned.
+func (s *storageData) removeObjects(ctx context.Context, names []string) {
+	// the rows are committed, a client gone by now must not stop the cleanup
+	ctx = context.WithoutCancel(ctx)
+	for _, name := range names {
+		if err := s.ost.Delete(ctx, name); err != nil {
+			logger.Error("cannot dell object %s: %v", name, err)
+		}
+	}
+}
+
+// DellEventImage removes an image of the event with its thumbnails. The
+// rows go first, the objects after the commit, so an image is never listed
+// without its file. When the cover is removed the next image takes over.
+func (s *storageData) DellEventImage(ctx context.Context, eventID int, filename string) error {
+	var names []string
+	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockEventImages(ctx, tx, eventID); err != nil {
+			return err
+		}
+
+		var photoID int
+		var cover bool
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM photo
+			WHERE event_id = $1 AND name = $2
+			RETURNING id, cover
+		`, eventID, filename).Scan(&photoID, &cover)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+			}
+			return fmt.Errorf("cannot dell photo: %w", err)
+		}
+
+		names = append(names, filename)
+		rows, err := tx.QueryContext(ctx, `
+			SELECT name FROM photo_thumbnail WHERE photo_id = $1
+		`, photoID)
+		if err != nil {
+			return fmt.Errorf("cannot get thumbnails: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return fmt.Errorf("cannot scan: %w", err)
+			}
+			names = append(names, name)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("cannot read thumbnails: %w", err)
+		}
+
+		if !cover {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE photo SET cover = TRUE
+			WHERE id = (
+				SELECT id FROM photo
+				WHERE event_id = $1
+				ORDER BY position, id
+				LIMIT 1
+			)
+		`, eventID)
+		if err != nil {
+			return fmt.Errorf("cannot set cover: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot dell image: %w", err)
+	}
+
+	s.removeObjects(ctx, names)
+	return nil
+}
+
+// ReorderEventImages puts the images of the event in the order of
+// filenames, which must name each of them exactly once.
+func (s *storageData) ReorderEventImages(ctx context.Context, eventID int, filenames []string) error {
+	return s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockEventImages(ctx, tx, eventID); err != nil {
+			return err
+		}
+
+		var count int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM photo WHERE event_id = $1
+		`, eventID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("cannot count photos: %w", err)
+		}
+		if count != len(filenames) {
+			return &ConflictError{Err: fmt.Errorf("event %d has %d images, the order lists %d", eventID, count, len(filenames))}
+		}
+
+		for position, filename := range filenames {
+			res, err := tx.ExecContext(ctx, `
+				UPDATE photo SET position = $3
+				WHERE event_id = $1 AND name = $2
+			`, eventID, filename, position)
+			if err != nil {
+				return fmt.Errorf("cannot set position: %w", err)
+			}
+
+			rows, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("cannot get rows: %w", err)
+			}
+			if rows == 0 {
+				return &NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetEventCover makes the image the cover of the event instead of the
+// current one.
+func (s *storageData) SetEventCover(ctx context.Context, eventID int, filename string) error {
+	return s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockEventImages(ctx, tx, eventID); err != nil {
+			return err
+		}
+
+		var id int
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM photo WHERE event_id = $1 AND name = $2
+		`, eventID, filename).Scan(&id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+			}
+			return fmt.Errorf("cannot get photo: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE photo SET cover = FALSE WHERE event_id = $1 AND cover
+		`, eventID)
+		if err != nil {
+			return fmt.Errorf("cannot unset cover: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE photo SET cover = TRUE WHERE id = $1
+		`, id)
+		if err != nil {
+			return fmt.Errorf("cannot set cover: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// CollectImageGarbage removes objects of the bucket that no photo or
+// thumbnail row names. Objects newer than uploadedBefore are kept: their
+// upload may not be committed yet.
+func (s *storageData) CollectImageGarbage(ctx context.Context, uploadedBefore time.Time) (int, error) {
+	objects, err := s.ost.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("cannot list objects: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name FROM photo
+		UNION ALL
+		SELECT name FROM photo_thumbnail
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get names: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return 0, fmt.Errorf("cannot scan: %w", err)
+		}
+		known[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("cannot read names: %w", err)
+	}
+
+	removed := 0
+	for _, object := range objects {
+		if known[object.Name] || !object.LastModified.Before(uploadedBefore) {
+			continue
+		}
+		if err := s.ost.Delete(ctx, object.Name); err != nil {
+			return removed, fmt.Errorf("cannot dell object: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// imageExists tells whether a photo or thumbnail row names the object,
+// other objects of the bucket are not served.
+func (s *storageData) imageExists(ctx context.Context, filename string) error {
+	var flag bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM photo WHERE name = $1
+			UNION ALL
+			SELECT 1 FROM photo_thumbnail WHERE name = $1
+		)
+	`, filename).Scan(&flag)
+	if err != nil {
+		return fmt.Errorf("cannot find image: %w", err)
+	}
+	if !flag {
+		return &NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+	}
+
+	return nil
+}
+
+// GetImage returns a presigned URL of the image that works for expires.
+// Blob stores without presigned URLs answer ostorage.ErrPresignNotSupported.
+func (s *storageData) GetImage(ctx context.Context, filename string, expires time.Duration) (string, error) {
+	if err := s.imageExists(ctx, filename); err != nil {
+		return "", err
+	}
+
+	url, err := s.ost.Presign(ctx, filename, expires)
+	if err != nil {
+		return "", fmt.Errorf("cannot presign image: %w", err)
+	}
+
+	return url, nil
+}
+
+// OpenImage returns the content of the image, it must be closed.
+func (s *storageData) OpenImage(ctx context.Context, filename string) (io.ReadSeekCloser, *ostorage.Object, error) {
+	if err := s.imageExists(ctx, filename); err != nil {
+		return nil, nil, err
+	}
+
+	content, object, err := s.ost.Get(ctx, filename)
+	if err != nil {
+		if errors.Is(err, ostorage.ErrNotExist) {
+			return nil, nil, &NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+		}
+		return nil, nil, fmt.Errorf("cannot open image: %w", err)
+	}
+
+	return content, object, nil
+}
@@ -13,6 +13,13 @@ import (
 	"github.com/pressly/goose"
 )
 
+// Backends of config.Storage.DatabaseBackend. Postgres is built here, the
+// in-memory one by package memory.
+const (
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory"
+)
+
 func newStorage(conf *config.Storage) (*storageData, error) {
 	db, err := Connection(conf.DatabaseDSN)
 	if err != nil {
@@ -25,7 +32,7 @@ func newStorage(conf *config.Storage) (*storageData, error) {
 		return nil, fmt.Errorf("cannot ping database: %w", err)
 	}
 
-	ost, err := ostorage.Connect(&conf.ObjectStorage)
+	ost, err := ostorage.Init(&conf.ObjectStorage)
 	if err != nil {
 		return nil, fmt.Errorf("cannot connection object storage: %w", err)
 	}
@@ -33,6 +40,15 @@ func newStorage(conf *config.Storage) (*storageData, error) {
 	return &storageData{db: db, ost: ost}, nil
 }
 
+// Close waits for running queries and closes the database connections.
+func (s *storageData) Close() error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("cannot close database: %w", err)
+	}
+
+	return nil
+}
+
 func Connection(databaseDSN string) (*sql.DB, error) {
 	db, err := sql.Open("pgx", databaseDSN)
 	if err != nil {
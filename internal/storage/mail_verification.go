@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (s *storageData) MailVerified(ctx context.Context, userID int) (bool, error) {
+	var verified bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT mail_verified
+		FROM users WHERE id = $1
+	`, userID).Scan(&verified)
+	if err != nil {
+		return false, fmt.Errorf("cannot scan: %w", err)
+	}
+
+	return verified, nil
+}
+
+// VerifyMail marks the address as verified only while the user still has
+// it, as the account email or as the address of the email channel. A link
+// sent to an old address does nothing.
+func (s *storageData) VerifyMail(ctx context.Context, userID int, mail string) error {
+	var verified int64
+	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, query := range []string{`
+			UPDATE users
+				SET mail_verified = TRUE
+				WHERE id = $1 AND mail = $2
+		`, `
+			UPDATE user_channel
+				SET verified = TRUE
+				WHERE user_id = $1 AND channel = 'email' AND address = $2
+		`} {
+			rows, err := tx.ExecContext(ctx, query, userID, mail)
+			if err != nil {
+				return fmt.Errorf("cannot verify mail: %w", err)
+			}
+
+			rowsAffected, err := rows.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("cannot get rows: %w", err)
+			}
+			verified += rowsAffected
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if verified == 0 {
+		return &NotFoundError{Err: errors.New("mail changed after the link was sent")}
+	}
+
+	return nil
+}
+
+func (s *storageData) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO password_reset (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`, tokenHash, userID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("cannot insert password reset: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword uses up the reset token and sets the new password. The
+// other tokens of the user stop working and every session is revoked. The
+// token came by mail, so the address counts as verified.
+func (s *storageData) ResetPassword(ctx context.Context, tokenHash, password string) error {
+	return s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var userID int
+		err := tx.QueryRowContext(ctx, `
+			UPDATE password_reset
+				SET used_at = now()
+				WHERE token_hash = $1
+				AND used_at IS NULL
+				AND expires_at > now()
+			RETURNING user_id
+		`, tokenHash).Scan(&userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: errors.New("reset token not valid or already used")}
+			}
+			return fmt.Errorf("cannot use reset token: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE password_reset
+				SET used_at = now()
+				WHERE user_id = $1 AND used_at IS NULL
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("cannot expire reset tokens: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE users
+				SET password = $2, mail_verified = TRUE
+				WHERE id = $1
+		`, userID, password)
+		if err != nil {
+			return fmt.Errorf("cannot update password: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE sessions
+				SET revoked = true
+				WHERE user_id = $1
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("cannot revoke sessions: %w", err)
+		}
+
+		return nil
+	})
+}
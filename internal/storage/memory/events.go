@@ -0,0 +1,469 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"sort"
+	"time"
+)
+
+// CreateEvent stores the event with its images. The objects are uploaded
+// first, the event is added only when all of them are in the blob store.
+func (s *Storage) CreateEvent(ctx context.Context, e *entity.Event) error {
+	s.mu.Lock()
+	_, err := s.user(e.UserID)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("cannot set event: %w", err)
+	}
+
+	if err := s.putImages(ctx, e.Images); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.ID = s.nextID("event")
+	s.events[e.ID] = &entity.Event{
+		ID:              e.ID,
+		UserID:          e.UserID,
+		Title:           e.Title,
+		Description:     e.Description,
+		Place:           e.Place,
+		Participants:    e.Participants,
+		MaxParticipants: e.MaxParticipants,
+		Date:            dbTime(e.Date),
+		Active:          e.Active,
+	}
+	s.addImages(e.ID, e.Images)
+
+	return nil
+}
+
+// event returns a copy of the event with its images. Occurrence falls back
+// to the date for events outside of a series.
+func (s *Storage) event(e *entity.Event) entity.Event {
+	event := *e
+	if event.Occurrence.IsZero() {
+		event.Occurrence = event.Date
+	}
+	event.Images = s.eventImages(e.ID)
+
+	return event
+}
+
+func (s *Storage) GetEvent(_ context.Context, eventID int) (*entity.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, eventNotExist(eventID)
+	}
+
+	event := s.event(e)
+	return &event, nil
+}
+
+// GetDateEvent returns in how many hours the event starts.
+func (s *Storage) GetDateEvent(_ context.Context, eventID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return 0, eventNotExist(eventID)
+	}
+
+	timeRemaining := time.Until(e.Date)
+	if timeRemaining < 0 {
+		return 0, &storage.ConflictError{Err: fmt.Errorf("event %d already started", eventID)}
+	}
+
+	return int(timeRemaining.Hours() + 0.5), nil
+}
+
+// ownEvent returns the event when it belongs to the user.
+func (s *Storage) ownEvent(userID, eventID int) (*entity.Event, error) {
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, eventNotExist(eventID)
+	}
+	if e.UserID != userID {
+		return nil, &storage.ForbiddenError{Err: fmt.Errorf("event %d belongs to another user", eventID)}
+	}
+
+	return e, nil
+}
+
+// DellEvent deletes the event with everything that refers to it, the
+// objects of its images after the rows.
+func (s *Storage) DellEvent(ctx context.Context, userID, eventID int) error {
+	s.mu.Lock()
+	if _, err := s.ownEvent(userID, eventID); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	images := s.eventImages(eventID)
+	s.dellEvent(eventID)
+	s.mu.Unlock()
+
+	s.removeObjects(ctx, objectNames(images))
+	return nil
+}
+
+// dellEvent deletes the event and the rows of the tables that reference it
+// with ON DELETE CASCADE.
+func (s *Storage) dellEvent(eventID int) {
+	delete(s.events, eventID)
+	delete(s.reminders, eventID)
+
+	for key := range s.records {
+		if key.eventID == eventID {
+			delete(s.records, key)
+		}
+	}
+	for key := range s.tickets {
+		if key.eventID == eventID {
+			delete(s.tickets, key)
+		}
+	}
+	for token, id := range s.revocations {
+		if id == eventID {
+			delete(s.revocations, token)
+		}
+	}
+	for key := range s.overrides {
+		if key.eventID == eventID {
+			delete(s.overrides, key)
+		}
+	}
+	for key := range s.remindersSent {
+		if key.eventID == eventID {
+			delete(s.remindersSent, key)
+		}
+	}
+	for id, p := range s.photos {
+		if p.eventID == eventID {
+			delete(s.photos, id)
+		}
+	}
+
+	s.waitlist = filter(s.waitlist, func(w waiting) bool { return w.eventID != eventID })
+	s.audits = filter(s.audits, func(a audit) bool { return a.eventID != eventID })
+	s.changes = filter(s.changes, func(c *changeNotice) bool { return c.eventID != eventID })
+	s.notices = filter(s.notices, func(n *registrationNotice) bool { return n.eventID != eventID })
+}
+
+// filter keeps the rows for which keep is true.
+func filter[T any](rows []T, keep func(T) bool) []T {
+	kept := rows[:0]
+	for _, row := range rows {
+		if keep(row) {
+			kept = append(kept, row)
+		}
+	}
+
+	var zero T
+	for i := len(kept); i < len(rows); i++ {
+		rows[i] = zero
+	}
+
+	return kept
+}
+
+func (s *Storage) CloseEvent(_ context.Context, userID, eventID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, err := s.ownEvent(userID, eventID)
+	if err != nil {
+		return err
+	}
+	e.Active = false
+
+	return nil
+}
+
+// CloseFinishedEvents closes the events that started before date.
+func (s *Storage) CloseFinishedEvents(_ context.Context, date time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.events {
+		if e.Active && e.Date.Before(date) {
+			e.Active = false
+		}
+	}
+
+	return nil
+}
+
+// GetUserEvents returns the events the user is registered to by date. Like
+// the Postgres query it leaves SeriesID and Occurrence unset.
+func (s *Storage) GetUserEvents(_ context.Context, userID int) ([]entity.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []entity.Event
+	for key := range s.records {
+		if key.userID != userID {
+			continue
+		}
+		e, ok := s.events[key.eventID]
+		if !ok {
+			continue
+		}
+
+		event := s.event(e)
+		event.SeriesID, event.Occurrence = 0, time.Time{}
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Date.Equal(events[j].Date) {
+			return events[i].Date.Before(events[j].Date)
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	return events, nil
+}
+
+// eventUsers returns the users registered to the event in the order they
+// registered.
+func (s *Storage) eventUsers(eventID int) []int {
+	type registered struct {
+		recordID int
+		userID   int
+	}
+
+	var records []registered
+	for key, id := range s.records {
+		if key.eventID == eventID {
+			records = append(records, registered{recordID: id, userID: key.userID})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].recordID < records[j].recordID })
+
+	users := make([]int, 0, len(records))
+	for _, record := range records {
+		users = append(users, record.userID)
+	}
+
+	return users
+}
+
+// reissue holds the new tokens of an event whose date moved.
+type reissue struct {
+	eventID int
+	date    time.Time
+	tokens  map[int]string
+}
+
+// reissueTickets signs new tickets of the event for date. Nothing is
+// changed until applyReissue, so a failed generator leaves the tickets as
+// they are.
+func (s *Storage) reissueTickets(eventID int, date time.Time, gen storage.TicketGenerator) (*reissue, error) {
+	r := &reissue{eventID: eventID, date: date, tokens: make(map[int]string)}
+	for key, t := range s.tickets {
+		if key.eventID != eventID {
+			continue
+		}
+
+		tick := entity.Ticket{EventID: eventID, UserID: key.userID, Token: t.token, Exp: int(time.Until(date).Hours() + 0.5)}
+		if err := gen.Generate(&tick); err != nil {
+			return nil, fmt.Errorf("cannot generate ticket: %w", err)
+		}
+		r.tokens[key.userID] = tick.Token
+	}
+
+	return r, nil
+}
+
+// applyReissue replaces the tickets and revokes the old tokens. Reminders
+// already sent are sent again for the new date.
+func (s *Storage) applyReissue(r *reissue) {
+	for userID, token := range r.tokens {
+		t := s.tickets[pair{eventID: r.eventID, userID: userID}]
+		if _, ok := s.revocations[t.token]; !ok {
+			s.revocations[t.token] = r.eventID
+		}
+		t.token = token
+		t.date = dbTime(r.date)
+	}
+
+	for key := range s.remindersSent {
+		if key.eventID == r.eventID {
+			delete(s.remindersSent, key)
+		}
+	}
+}
+
+// promoteTickets signs tickets for the first seats users of the waitlist of
+// e. Like reissueTickets nothing is changed, applyPromotions takes the seats.
+func (s *Storage) promoteTickets(e *entity.Event, seats int, date time.Time, gen storage.TicketGenerator) ([]*entity.Ticket, error) {
+	if !e.Active {
+		return nil, nil
+	}
+
+	var promoted []*entity.Ticket
+	for _, w := range s.waitlist {
+		if len(promoted) == seats {
+			break
+		}
+		if w.eventID != e.ID {
+			continue
+		}
+
+		tick := &entity.Ticket{UserID: w.userID, EventID: e.ID, Exp: int(time.Until(date).Hours() + 0.5)}
+		if err := gen.Generate(tick); err != nil {
+			return nil, fmt.Errorf("cannot generate ticket: %w", err)
+		}
+		if s.tokenTaken(tick.Token) {
+			return nil, errors.New("duplicate token")
+		}
+		promoted = append(promoted, tick)
+	}
+
+	return promoted, nil
+}
+
+// applyPromotions moves the promoted users from the waitlist to the event
+// and queues their promotion notices.
+func (s *Storage) applyPromotions(e *entity.Event, promoted []*entity.Ticket) error {
+	for _, tick := range promoted {
+		if err := s.addTicket(tick); err != nil {
+			return err
+		}
+		for i, w := range s.waitlist {
+			if w.pair == (pair{eventID: e.ID, userID: tick.UserID}) {
+				s.waitlist = append(s.waitlist[:i], s.waitlist[i+1:]...)
+				break
+			}
+		}
+		s.addRecord(e, tick.UserID)
+		s.addNotice(e.ID, tick.UserID, entity.NoticePromotion)
+	}
+
+	return nil
+}
+
+// addChanges writes the audit of the edit and queues an "event changed"
+// notice for every registered attendee.
+func (s *Storage) addChanges(eventID, userID int, changes []entity.EventChange, body []byte) {
+	at := now()
+	for _, change := range changes {
+		s.audits = append(s.audits, audit{eventID: eventID, userID: userID, change: change, at: at})
+	}
+
+	for _, attendee := range s.eventUsers(eventID) {
+		s.changes = append(s.changes, &changeNotice{
+			id:      s.nextID("event_change"),
+			pair:    pair{eventID: eventID, userID: attendee},
+			changes: body,
+		})
+	}
+}
+
+// UpdateEvent saves edited fields of e, writes the audit and queues an
+// "event changed" notice for every registered attendee. Shrinking
+// MaxParticipants below Participants is a ConflictError.
+func (s *Storage) UpdateEvent(_ context.Context, userID int, e *entity.Event, changes []entity.EventChange, gen storage.TicketGenerator) error {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("cannot json to byte: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.events[e.ID]
+	if !ok {
+		return eventNotExist(e.ID)
+	}
+
+	if e.MaxParticipants < current.Participants {
+		return &storage.ConflictError{Err: fmt.Errorf("max participants %d less than participants %d", e.MaxParticipants, current.Participants)}
+	}
+
+	date := dbTime(e.Date)
+	var r *reissue
+	if !current.Date.Equal(date) {
+		if r, err = s.reissueTickets(e.ID, date, gen); err != nil {
+			return fmt.Errorf("cannot update event: %w", err)
+		}
+	}
+	promoted, err := s.promoteTickets(current, e.MaxParticipants-current.Participants, date, gen)
+	if err != nil {
+		return fmt.Errorf("cannot update event: cannot promote waitlist: %w", err)
+	}
+
+	current.Title = e.Title
+	current.Description = e.Description
+	current.Place = e.Place
+	current.MaxParticipants = e.MaxParticipants
+	current.Date = date
+
+	if r != nil {
+		s.applyReissue(r)
+	}
+	s.addChanges(e.ID, userID, changes, body)
+	if err := s.applyPromotions(current, promoted); err != nil {
+		return fmt.Errorf("cannot update event: cannot promote waitlist: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetChangeNotices(_ context.Context, limit int) ([]entity.ChangeNotice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notices []entity.ChangeNotice
+	for _, c := range s.changes {
+		if len(notices) == limit {
+			break
+		}
+		u, ok := s.users[c.userID]
+		if c.send || !ok {
+			continue
+		}
+
+		notice := entity.ChangeNotice{
+			ID:      c.id,
+			EventID: c.eventID,
+			UserID:  c.userID,
+			Mail:    u.Mail,
+			Locale:  u.locale,
+		}
+		if err := json.Unmarshal(c.changes, &notice.Changes); err != nil {
+			return nil, fmt.Errorf("cannot parse changes: %w", err)
+		}
+		if t, ok := s.tickets[c.pair]; ok {
+			notice.Token = t.token
+		}
+		notices = append(notices, notice)
+	}
+
+	return notices, nil
+}
+
+func (s *Storage) ChangeNoticeSent(_ context.Context, noticeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.changes {
+		if c.id == noticeID {
+			c.send = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,355 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/logger"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"io"
+	"sort"
+	"time"
+)
+
+// putImages uploads the originals and thumbnails, on failure nothing of
+// this call is left in the blob store.
+func (s *Storage) putImages(ctx context.Context, images []entity.Image) error {
+	var put []string
+	set := func(name string, data []byte, mime string) error {
+		if err := s.ost.Put(ctx, name, data, mime); err != nil {
+			s.removeObjects(ctx, put)
+			return fmt.Errorf("cannot set photo ost: %w", err)
+		}
+		put = append(put, name)
+		return nil
+	}
+
+	for _, image := range images {
+		if err := set(image.Filename, image.Data, image.MIME); err != nil {
+			return err
+		}
+		for _, thumbnail := range image.Thumbnails {
+			if err := set(thumbnail.Filename, thumbnail.Data, thumbnail.MIME); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeObjects deletes objects whose rows are already gone, failures are
+// left to CollectImageGarbage.
+func (s *Storage) removeObjects(ctx context.Context, names []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, name := range names {
+		if err := s.ost.Delete(ctx, name); err != nil {
+			logger.Error("cannot dell object %s: %v", name, err)
+		}
+	}
+}
+
+// addImages appends images to the event, the first image of an event
+// without a cover becomes the cover. It sets ID and Cover of images.
+func (s *Storage) addImages(eventID int, images []entity.Image) {
+	position, cover := 0, false
+	for _, p := range s.photos {
+		if p.eventID != eventID {
+			continue
+		}
+		if p.position >= position {
+			position = p.position + 1
+		}
+		cover = cover || p.Cover
+	}
+
+	for index := range images {
+		image := &images[index]
+		image.ID = s.nextID("photo")
+		image.Cover = !cover
+		cover = true
+
+		stored := entity.Image{
+			ID:       image.ID,
+			Filename: image.Filename,
+			MIME:     image.MIME,
+			Width:    image.Width,
+			Height:   image.Height,
+			Cover:    image.Cover,
+		}
+		for _, thumbnail := range image.Thumbnails {
+			stored.Thumbnails = append(stored.Thumbnails, entity.Thumbnail{
+				Filename: thumbnail.Filename,
+				MIME:     thumbnail.MIME,
+				Width:    thumbnail.Width,
+				Height:   thumbnail.Height,
+			})
+		}
+		sort.SliceStable(stored.Thumbnails, func(i, j int) bool { return stored.Thumbnails[i].Width < stored.Thumbnails[j].Width })
+
+		s.photos[image.ID] = &photo{eventID: eventID, position: position, Image: stored}
+		position++
+	}
+}
+
+// eventPhotos returns the photos of the event in display order.
+func (s *Storage) eventPhotos(eventID int) []*photo {
+	var photos []*photo
+	for _, p := range s.photos {
+		if p.eventID == eventID {
+			photos = append(photos, p)
+		}
+	}
+
+	sort.Slice(photos, func(i, j int) bool {
+		if photos[i].position != photos[j].position {
+			return photos[i].position < photos[j].position
+		}
+		return photos[i].ID < photos[j].ID
+	})
+
+	return photos
+}
+
+// eventImages returns copies of the images of the event in display order
+// with their thumbnails, smallest first.
+func (s *Storage) eventImages(eventID int) []entity.Image {
+	var images []entity.Image
+	for _, p := range s.eventPhotos(eventID) {
+		image := p.Image
+		image.Thumbnails = append([]entity.Thumbnail(nil), p.Thumbnails...)
+		images = append(images, image)
+	}
+
+	return images
+}
+
+// objectNames lists the objects of images, originals and thumbnails.
+func objectNames(images []entity.Image) []string {
+	var names []string
+	for _, image := range images {
+		names = append(names, image.Filename)
+		for _, thumbnail := range image.Thumbnails {
+			names = append(names, thumbnail.Filename)
+		}
+	}
+
+	return names
+}
+
+func eventNotExist(eventID int) error {
+	return &storage.NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
+}
+
+// AddEventImages stores images of an existing event after the ones it has.
+// The objects are uploaded first, the rows are added only when all of them
+// are in the blob store.
+func (s *Storage) AddEventImages(ctx context.Context, eventID int, images []entity.Image) error {
+	s.mu.Lock()
+	_, ok := s.events[eventID]
+	s.mu.Unlock()
+	if !ok {
+		return eventNotExist(eventID)
+	}
+
+	if err := s.putImages(ctx, images); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the event may be deleted while the images were uploaded
+	if _, ok := s.events[eventID]; !ok {
+		s.removeObjects(ctx, objectNames(images))
+		return eventNotExist(eventID)
+	}
+
+	s.addImages(eventID, images)
+
+	return nil
+}
+
+// DellEventImage removes an image of the event with its thumbnails, the
+// objects after the rows. When the cover is removed the next image takes
+// over.
+func (s *Storage) DellEventImage(ctx context.Context, eventID int, filename string) error {
+	s.mu.Lock()
+
+	if _, ok := s.events[eventID]; !ok {
+		s.mu.Unlock()
+		return eventNotExist(eventID)
+	}
+
+	var removed *photo
+	for _, p := range s.eventPhotos(eventID) {
+		if p.Filename == filename {
+			removed = p
+			break
+		}
+	}
+	if removed == nil {
+		s.mu.Unlock()
+		return &storage.NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+	}
+
+	delete(s.photos, removed.ID)
+	if removed.Cover {
+		if photos := s.eventPhotos(eventID); len(photos) > 0 {
+			photos[0].Cover = true
+		}
+	}
+	s.mu.Unlock()
+
+	s.removeObjects(ctx, objectNames([]entity.Image{removed.Image}))
+	return nil
+}
+
+// ReorderEventImages puts the images of the event in the order of
+// filenames, which must name each of them exactly once.
+func (s *Storage) ReorderEventImages(_ context.Context, eventID int, filenames []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[eventID]; !ok {
+		return eventNotExist(eventID)
+	}
+
+	photos := s.eventPhotos(eventID)
+	if len(photos) != len(filenames) {
+		return &storage.ConflictError{Err: fmt.Errorf("event %d has %d images, the order lists %d", eventID, len(photos), len(filenames))}
+	}
+
+	byName := make(map[string][]*photo)
+	for _, p := range photos {
+		byName[p.Filename] = append(byName[p.Filename], p)
+	}
+	for _, filename := range filenames {
+		if len(byName[filename]) == 0 {
+			return &storage.NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+		}
+	}
+
+	for position, filename := range filenames {
+		for _, p := range byName[filename] {
+			p.position = position
+		}
+	}
+
+	return nil
+}
+
+// SetEventCover makes the image the cover of the event instead of the
+// current one.
+func (s *Storage) SetEventCover(_ context.Context, eventID int, filename string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[eventID]; !ok {
+		return eventNotExist(eventID)
+	}
+
+	var cover *photo
+	photos := s.eventPhotos(eventID)
+	for _, p := range photos {
+		if p.Filename == filename {
+			cover = p
+			break
+		}
+	}
+	if cover == nil {
+		return &storage.NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+	}
+
+	for _, p := range photos {
+		p.Cover = p == cover
+	}
+
+	return nil
+}
+
+// CollectImageGarbage removes objects of the blob store that no image
+// names. Objects newer than uploadedBefore are kept: their rows may not be
+// added yet.
+func (s *Storage) CollectImageGarbage(ctx context.Context, uploadedBefore time.Time) (int, error) {
+	objects, err := s.ost.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("cannot list objects: %w", err)
+	}
+
+	s.mu.Lock()
+	known := make(map[string]bool)
+	for _, p := range s.photos {
+		known[p.Filename] = true
+		for _, thumbnail := range p.Thumbnails {
+			known[thumbnail.Filename] = true
+		}
+	}
+	s.mu.Unlock()
+
+	removed := 0
+	for _, object := range objects {
+		if known[object.Name] || !object.LastModified.Before(uploadedBefore) {
+			continue
+		}
+		if err := s.ost.Delete(ctx, object.Name); err != nil {
+			return removed, fmt.Errorf("cannot dell object: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// imageExists tells whether an image or thumbnail has the name, other
+// objects of the blob store are not served.
+func (s *Storage) imageExists(filename string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.photos {
+		if p.Filename == filename {
+			return nil
+		}
+		for _, thumbnail := range p.Thumbnails {
+			if thumbnail.Filename == filename {
+				return nil
+			}
+		}
+	}
+
+	return &storage.NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+}
+
+// GetImage returns a presigned URL of the image that works for expires.
+func (s *Storage) GetImage(ctx context.Context, filename string, expires time.Duration) (string, error) {
+	if err := s.imageExists(filename); err != nil {
+		return "", err
+	}
+
+	url, err := s.ost.Presign(ctx, filename, expires)
+	if err != nil {
+		return "", fmt.Errorf("cannot presign image: %w", err)
+	}
+
+	return url, nil
+}
+
+// OpenImage returns the content of the image, it must be closed.
+func (s *Storage) OpenImage(ctx context.Context, filename string) (io.ReadSeekCloser, *ostorage.Object, error) {
+	if err := s.imageExists(filename); err != nil {
+		return nil, nil, err
+	}
+
+	content, object, err := s.ost.Get(ctx, filename)
+	if err != nil {
+		if errors.Is(err, ostorage.ErrNotExist) {
+			return nil, nil, &storage.NotFoundError{Err: fmt.Errorf("image %s not exist", filename)}
+		}
+		return nil, nil, fmt.Errorf("cannot open image: %w", err)
+	}
+
+	return content, object, nil
+}
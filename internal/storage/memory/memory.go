@@ -0,0 +1,172 @@
+// Package memory is a storage.Storage kept in process memory. It follows the
+// Postgres storage: the same unique keys, seat limits, cascade deletes and
+// error types, so it serves tests and a demo mode without a database. All
+// data is lost when the process stops.
+package memory
+
+import (
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"sync"
+	"time"
+)
+
+// pair keys rows of tables whose primary key is (event_id, user_id).
+type pair struct {
+	eventID int
+	userID  int
+}
+
+type user struct {
+	entity.User
+	locale       string
+	signupDigest bool
+	digestSentAt time.Time
+}
+
+type session struct {
+	id          int
+	userID      int
+	refreshHash string
+	expiresAt   time.Time
+	revoked     bool
+}
+
+type passwordReset struct {
+	userID    int
+	expiresAt time.Time
+	used      bool
+}
+
+type waiting struct {
+	id int
+	pair
+}
+
+type ticket struct {
+	id         int
+	token      string
+	date       time.Time
+	active     bool
+	redeemedAt time.Time
+	redeemedBy int
+}
+
+type audit struct {
+	eventID int
+	userID  int
+	change  entity.EventChange
+	at      time.Time
+}
+
+// changeNotice keeps the changes as JSON like the changes column, so they
+// read back the same.
+type changeNotice struct {
+	id int
+	pair
+	changes []byte
+	send    bool
+}
+
+type registrationNotice struct {
+	id int
+	pair
+	kind      string
+	createdAt time.Time
+	send      bool
+}
+
+type reminderKey struct {
+	pair
+	minutes int
+}
+
+type seriesException struct {
+	seriesID   int
+	occurrence time.Time
+}
+
+type photo struct {
+	eventID  int
+	position int
+	entity.Image
+}
+
+// Storage keeps every table behind one mutex, a method holds it for its
+// whole run like a transaction. Images go to the blob store outside of it.
+type Storage struct {
+	mu  sync.Mutex
+	ost ostorage.BlobStore
+	ids map[string]int
+
+	users          map[int]*user
+	sessions       map[int]*session
+	resets         map[string]*passwordReset
+	calendarTokens map[int]string
+	channels       map[int][]entity.UserChannel
+
+	events        map[int]*entity.Event
+	records       map[pair]int
+	waitlist      []waiting
+	tickets       map[pair]*ticket
+	revocations   map[string]int
+	audits        []audit
+	changes       []*changeNotice
+	notices       []*registrationNotice
+	reminders     map[int][]int
+	overrides     map[pair][]int
+	remindersSent map[reminderKey]bool
+	outbox        []*entity.OutboxMail
+	series        map[int]*entity.Series
+	exceptions    map[seriesException]bool
+	photos        map[int]*photo
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+// New returns an empty storage whose images are kept in ost.
+func New(ost ostorage.BlobStore) *Storage {
+	return &Storage{
+		ost: ost,
+		ids: make(map[string]int),
+
+		users:          make(map[int]*user),
+		sessions:       make(map[int]*session),
+		resets:         make(map[string]*passwordReset),
+		calendarTokens: make(map[int]string),
+		channels:       make(map[int][]entity.UserChannel),
+
+		events:        make(map[int]*entity.Event),
+		records:       make(map[pair]int),
+		tickets:       make(map[pair]*ticket),
+		revocations:   make(map[string]int),
+		reminders:     make(map[int][]int),
+		overrides:     make(map[pair][]int),
+		remindersSent: make(map[reminderKey]bool),
+		series:        make(map[int]*entity.Series),
+		exceptions:    make(map[seriesException]bool),
+		photos:        make(map[int]*photo),
+	}
+}
+
+// nextID works as the SERIAL column of table.
+func (s *Storage) nextID(table string) int {
+	s.ids[table]++
+	return s.ids[table]
+}
+
+// now and dbTime store times the way a timestamp column does: in UTC and
+// without the monotonic clock reading.
+func now() time.Time {
+	return time.Now().UTC().Round(0)
+}
+
+func dbTime(t time.Time) time.Time {
+	return t.UTC().Round(0)
+}
+
+// Close does nothing, there is no connection to close.
+func (s *Storage) Close() error {
+	return nil
+}
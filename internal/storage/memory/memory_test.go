@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"graduation/internal/storage/memory"
+	"graduation/internal/storage/storagetest"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, ost ostorage.BlobStore) storage.Storage {
+		return memory.New(ost)
+	})
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"sort"
+	"time"
+)
+
+// EnqueueMail adds notifications to the outbox. One whose key is already
+// queued for its channel is skipped.
+func (s *Storage) EnqueueMail(_ context.Context, mails []entity.OutboxMail) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type key struct {
+		key     string
+		channel string
+	}
+
+	queued := make(map[key]bool)
+	for _, mail := range s.outbox {
+		queued[key{key: mail.Key, channel: mail.Channel}] = true
+	}
+
+	at := now()
+	for _, mail := range mails {
+		if queued[key{key: mail.Key, channel: mail.Channel}] {
+			continue
+		}
+		queued[key{key: mail.Key, channel: mail.Channel}] = true
+
+		s.outbox = append(s.outbox, &entity.OutboxMail{
+			ID:            s.nextID("outbox"),
+			Key:           mail.Key,
+			UserID:        mail.UserID,
+			EventID:       mail.EventID,
+			Channel:       mail.Channel,
+			Address:       mail.Address,
+			Subject:       mail.Subject,
+			Body:          mail.Body,
+			Text:          mail.Text,
+			Token:         mail.Token,
+			Calendar:      mail.Calendar,
+			Status:        entity.OutboxPending,
+			NextAttemptAt: at,
+			CreatedAt:     at,
+		})
+	}
+
+	return nil
+}
+
+func (s *Storage) outboxMail(mailID int) *entity.OutboxMail {
+	for _, mail := range s.outbox {
+		if mail.ID == mailID {
+			return mail
+		}
+	}
+
+	return nil
+}
+
+// ClaimMail takes up to limit pending emails that are due and hides them
+// from other senders for lease. An email whose sender dies comes back after
+// the lease.
+func (s *Storage) ClaimMail(_ context.Context, limit int, lease time.Duration) ([]entity.OutboxMail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := now()
+
+	var due []*entity.OutboxMail
+	for _, mail := range s.outbox {
+		if mail.Status == entity.OutboxPending && !mail.NextAttemptAt.After(at) {
+			due = append(due, mail)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var mails []entity.OutboxMail
+	for _, mail := range due {
+		mail.NextAttemptAt = at.Add(lease)
+		mails = append(mails, *mail)
+	}
+
+	return mails, nil
+}
+
+func (s *Storage) MailSent(_ context.Context, mailID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mail := s.outboxMail(mailID); mail != nil {
+		mail.Status = entity.OutboxSent
+		mail.Attempts++
+		mail.LastError = ""
+	}
+
+	return nil
+}
+
+// MailRetry records a failed attempt and schedules the next one at next.
+func (s *Storage) MailRetry(_ context.Context, mailID int, reason string, next time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mail := s.outboxMail(mailID); mail != nil {
+		mail.Attempts++
+		mail.LastError = reason
+		mail.NextAttemptAt = dbTime(next)
+	}
+
+	return nil
+}
+
+// MailDead records the last failed attempt, the email is not sent again until
+// it is replayed.
+func (s *Storage) MailDead(_ context.Context, mailID int, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mail := s.outboxMail(mailID); mail != nil {
+		mail.Status = entity.OutboxDead
+		mail.Attempts++
+		mail.LastError = reason
+	}
+
+	return nil
+}
+
+// OutboxMails returns a page of emails in status, newest first, and how many
+// emails are in status.
+func (s *Storage) OutboxMails(_ context.Context, status string, limit, offset int) ([]entity.OutboxMail, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []entity.OutboxMail
+	for i := len(s.outbox) - 1; i >= 0; i-- {
+		if s.outbox[i].Status == status {
+			matched = append(matched, *s.outbox[i])
+		}
+	}
+
+	total := len(matched)
+	if offset >= total {
+		return nil, total, nil
+	}
+	matched = matched[offset:]
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	return matched, total, nil
+}
+
+// ReplayMail returns a dead email to the queue with a fresh attempt count.
+func (s *Storage) ReplayMail(_ context.Context, mailID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mail := s.outboxMail(mailID)
+	if mail == nil {
+		return &storage.NotFoundError{Err: errors.New("mail not exist")}
+	}
+	if mail.Status != entity.OutboxDead {
+		return &storage.ConflictError{Err: fmt.Errorf("mail %d is %s", mailID, mail.Status)}
+	}
+
+	mail.Status = entity.OutboxPending
+	mail.Attempts = 0
+	mail.NextAttemptAt = now()
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"sort"
+	"time"
+)
+
+// addTicket issues the ticket of a registration, it expires tick.Exp hours
+// from now.
+func (s *Storage) addTicket(tick *entity.Ticket) error {
+	if s.tokenTaken(tick.Token) {
+		return errors.New("cannot INSERT ticket: duplicate token")
+	}
+
+	s.tickets[pair{eventID: tick.EventID, userID: tick.UserID}] = &ticket{
+		id:     s.nextID("ticket"),
+		token:  tick.Token,
+		date:   now().Add(time.Hour * time.Duration(tick.Exp)),
+		active: true,
+	}
+
+	return nil
+}
+
+func (s *Storage) tokenTaken(token string) bool {
+	for _, t := range s.tickets {
+		if t.token == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addRecord registers the user to the event and takes a seat.
+func (s *Storage) addRecord(e *entity.Event, userID int) {
+	s.records[pair{eventID: e.ID, userID: userID}] = s.nextID("record")
+	e.Participants++
+}
+
+// dellRecord cancels the registration with the reminders that reference
+// it.
+func (s *Storage) dellRecord(key pair) {
+	delete(s.records, key)
+	delete(s.overrides, key)
+	for sent := range s.remindersSent {
+		if sent.pair == key {
+			delete(s.remindersSent, sent)
+		}
+	}
+}
+
+// addNotice queues a registration email.
+func (s *Storage) addNotice(eventID, userID int, kind string) {
+	s.notices = append(s.notices, &registrationNotice{
+		id:        s.nextID("registration_notice"),
+		pair:      pair{eventID: eventID, userID: userID},
+		kind:      kind,
+		createdAt: now(),
+	})
+}
+
+// AddEventUser registers the user to the event and issues the ticket. When
+// the event is full the user joins the waitlist instead, the position on it
+// is returned.
+func (s *Storage) AddEventUser(_ context.Context, tick *entity.Ticket) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[tick.EventID]
+	if !ok {
+		return 0, eventNotExist(tick.EventID)
+	}
+	if !e.Active {
+		return 0, &storage.ConflictError{Err: fmt.Errorf("event %d is closed", tick.EventID)}
+	}
+
+	key := pair{eventID: tick.EventID, userID: tick.UserID}
+	if _, ok := s.records[key]; ok {
+		return 0, &storage.ConflictError{Err: fmt.Errorf("user %d already registered for event %d", tick.UserID, tick.EventID)}
+	}
+	if _, err := s.user(tick.UserID); err != nil {
+		return 0, fmt.Errorf("cannot add evnt user: %w", err)
+	}
+
+	if e.Participants >= e.MaxParticipants {
+		position := 1
+		for _, w := range s.waitlist {
+			if w.eventID != tick.EventID {
+				continue
+			}
+			if w.userID == tick.UserID {
+				return 0, &storage.ConflictError{Err: fmt.Errorf("user %d already on the waitlist of event %d", tick.UserID, tick.EventID)}
+			}
+			position++
+		}
+
+		s.waitlist = append(s.waitlist, waiting{id: s.nextID("waitlist"), pair: key})
+		return position, nil
+	}
+
+	if err := s.addTicket(tick); err != nil {
+		return 0, fmt.Errorf("cannot add evnt user: %w", err)
+	}
+	s.addRecord(e, tick.UserID)
+	s.addNotice(tick.EventID, tick.UserID, entity.NoticeRegistration)
+
+	return 0, nil
+}
+
+// DellEventUser takes the user off the waitlist or cancels the
+// registration. A freed seat goes to the first user of the waitlist, whose
+// ticket is returned.
+func (s *Storage) DellEventUser(_ context.Context, eventID, userID int, gen storage.TicketGenerator) (*entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pair{eventID: eventID, userID: userID}
+	for i, w := range s.waitlist {
+		if w.pair == key {
+			s.waitlist = append(s.waitlist[:i], s.waitlist[i+1:]...)
+			return nil, nil
+		}
+	}
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, eventNotExist(eventID)
+	}
+	if _, ok := s.records[key]; !ok {
+		return nil, &storage.ConflictError{Err: fmt.Errorf("user %d not registered for event %d", userID, eventID)}
+	}
+
+	// the promoted ticket is signed first, a failed generator changes nothing
+	next := -1
+	var promoted *entity.Ticket
+	if e.Active && e.Participants-1 < e.MaxParticipants {
+		for i, w := range s.waitlist {
+			if w.eventID == eventID {
+				next = i
+				break
+			}
+		}
+	}
+	if next >= 0 {
+		promoted = &entity.Ticket{
+			UserID:  s.waitlist[next].userID,
+			EventID: eventID,
+			Exp:     int(time.Until(e.Date).Hours() + 0.5),
+		}
+		if err := gen.Generate(promoted); err != nil {
+			return nil, fmt.Errorf("cannot promote waitlist: cannot generate ticket: %w", err)
+		}
+		if s.tokenTaken(promoted.Token) {
+			return nil, errors.New("cannot promote waitlist: duplicate token")
+		}
+	}
+
+	s.dellRecord(key)
+	e.Participants--
+	if t, ok := s.tickets[key]; ok {
+		if _, ok := s.revocations[t.token]; !ok {
+			s.revocations[t.token] = eventID
+		}
+		delete(s.tickets, key)
+	}
+	s.addNotice(eventID, userID, entity.NoticeCancellation)
+
+	if promoted != nil {
+		if err := s.addTicket(promoted); err != nil {
+			return nil, fmt.Errorf("cannot promote waitlist: %w", err)
+		}
+		s.waitlist = append(s.waitlist[:next], s.waitlist[next+1:]...)
+		s.addRecord(e, promoted.UserID)
+		s.addNotice(eventID, promoted.UserID, entity.NoticePromotion)
+	}
+
+	return promoted, nil
+}
+
+func (s *Storage) GetRegistrationNotices(_ context.Context, limit int) ([]entity.RegistrationNotice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notices []entity.RegistrationNotice
+	for _, n := range s.notices {
+		if len(notices) == limit {
+			break
+		}
+		u, ok := s.users[n.userID]
+		if n.send || !ok {
+			continue
+		}
+
+		notice := entity.RegistrationNotice{
+			ID:      n.id,
+			EventID: n.eventID,
+			UserID:  n.userID,
+			Kind:    n.kind,
+			Mail:    u.Mail,
+			Locale:  u.locale,
+		}
+		if t, ok := s.tickets[n.pair]; ok && n.kind != entity.NoticeCancellation {
+			notice.Token = t.token
+		}
+		notices = append(notices, notice)
+	}
+
+	return notices, nil
+}
+
+func (s *Storage) RegistrationNoticeSent(_ context.Context, noticeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, n := range s.notices {
+		if n.id == noticeID {
+			n.send = true
+		}
+	}
+
+	return nil
+}
+
+// DueDigests returns the digests of organizers whose last digest is at least
+// every old and who have new signups since.
+func (s *Storage) DueDigests(_ context.Context, now time.Time, every time.Duration) ([]entity.Digest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := dbTime(now).Add(-every)
+
+	var userIDs []int
+	for id, u := range s.users {
+		if u.signupDigest && !u.digestSentAt.After(due) {
+			userIDs = append(userIDs, id)
+		}
+	}
+	sort.Ints(userIDs)
+
+	var digests []entity.Digest
+	for _, userID := range userIDs {
+		u := s.users[userID]
+
+		signups := make(map[int]int)
+		for _, n := range s.notices {
+			if n.kind != entity.NoticeRegistration && n.kind != entity.NoticePromotion {
+				continue
+			}
+			if e, ok := s.events[n.eventID]; ok && e.UserID == userID && n.createdAt.After(u.digestSentAt) {
+				signups[n.eventID]++
+			}
+		}
+		if len(signups) == 0 {
+			continue
+		}
+
+		digest := entity.Digest{UserID: userID, Mail: u.Mail, Locale: u.locale, Since: u.digestSentAt}
+		for eventID, count := range signups {
+			e := s.events[eventID]
+			digest.Events = append(digest.Events, entity.DigestEvent{
+				EventID:         eventID,
+				Title:           e.Title,
+				Date:            e.Date,
+				Signups:         count,
+				Participants:    e.Participants,
+				MaxParticipants: e.MaxParticipants,
+			})
+		}
+		sort.Slice(digest.Events, func(i, j int) bool {
+			if !digest.Events[i].Date.Equal(digest.Events[j].Date) {
+				return digest.Events[i].Date.Before(digest.Events[j].Date)
+			}
+			return digest.Events[i].EventID < digest.Events[j].EventID
+		})
+		digests = append(digests, digest)
+	}
+
+	return digests, nil
+}
+
+// DigestSent moves the start of the next digest of the organizer to at.
+func (s *Storage) DigestSent(_ context.Context, userID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.digestSentAt = dbTime(at)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"sort"
+	"time"
+)
+
+// schedule returns the reminders of a registration: the attendee's own
+// schedule, else the event's, else entity.DefaultReminders.
+func (s *Storage) schedule(key pair) []int {
+	if minutes, ok := s.overrides[key]; ok {
+		return minutes
+	}
+	if minutes, ok := s.reminders[key.eventID]; ok {
+		return minutes
+	}
+
+	return entity.DefaultReminders
+}
+
+// pendingReminders returns the unsent reminders of upcoming active events
+// ordered by event, attendee and minutes.
+func (s *Storage) pendingReminders(now time.Time) []reminderKey {
+	var pending []reminderKey
+	for key := range s.records {
+		e, ok := s.events[key.eventID]
+		if !ok || !e.Active || !e.Date.After(now) {
+			continue
+		}
+
+		for _, minutes := range s.schedule(key) {
+			reminder := reminderKey{pair: key, minutes: minutes}
+			if !s.remindersSent[reminder] {
+				pending = append(pending, reminder)
+			}
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if a.eventID != b.eventID {
+			return a.eventID < b.eventID
+		}
+		if a.userID != b.userID {
+			return a.userID < b.userID
+		}
+		return a.minutes < b.minutes
+	})
+
+	return pending
+}
+
+// DueReminders returns up to limit unsent reminders whose time has come,
+// ordered by event, attendee and minutes.
+func (s *Storage) DueReminders(_ context.Context, now time.Time, limit int) ([]entity.Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now = dbTime(now)
+
+	var reminders []entity.Reminder
+	for _, key := range s.pendingReminders(now) {
+		if len(reminders) == limit {
+			break
+		}
+
+		e := s.events[key.eventID]
+		u, ok := s.users[key.userID]
+		if !ok || e.Date.Add(-time.Duration(key.minutes)*time.Minute).After(now) {
+			continue
+		}
+
+		reminder := entity.Reminder{
+			EventID:  key.eventID,
+			UserID:   key.userID,
+			RecordID: s.records[key.pair],
+			Minutes:  key.minutes,
+			Date:     e.Date,
+			Mail:     u.Mail,
+			Locale:   u.locale,
+		}
+		if t, ok := s.tickets[key.pair]; ok {
+			reminder.Token = t.token
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+// NextReminder returns when the earliest unsent reminder is due, zero time
+// when there is none.
+func (s *Storage) NextReminder(_ context.Context, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	for _, key := range s.pendingReminders(dbTime(now)) {
+		at := s.events[key.eventID].Date.Add(-time.Duration(key.minutes) * time.Minute)
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+
+	return next, nil
+}
+
+// ReminderSent marks reminders of the attendee as sent.
+func (s *Storage) ReminderSent(_ context.Context, eventID, userID int, minutes []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pair{eventID: eventID, userID: userID}
+	if _, ok := s.records[key]; !ok && len(minutes) > 0 {
+		return fmt.Errorf("cannot INSERT reminder sent: user %d not registered for event %d", userID, eventID)
+	}
+
+	for _, m := range minutes {
+		s.remindersSent[reminderKey{pair: key, minutes: m}] = true
+	}
+
+	return nil
+}
+
+// EventReminders returns the schedule of the event, entity.DefaultReminders
+// when the organizer did not set one.
+func (s *Storage) EventReminders(_ context.Context, eventID int) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	minutes, ok := s.reminders[eventID]
+	if !ok {
+		return entity.DefaultReminders, nil
+	}
+
+	return append([]int{}, minutes...), nil
+}
+
+// SetEventReminders replaces the schedule of the event, an empty one turns
+// reminders off.
+func (s *Storage) SetEventReminders(_ context.Context, eventID int, minutes []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[eventID]; !ok {
+		return eventNotExist(eventID)
+	}
+	s.reminders[eventID] = append([]int{}, minutes...)
+
+	return nil
+}
+
+// UserReminders returns the attendee's own schedule for the event, nil when
+// the attendee follows the event's one.
+func (s *Storage) UserReminders(_ context.Context, eventID, userID int) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	minutes, ok := s.overrides[pair{eventID: eventID, userID: userID}]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]int{}, minutes...), nil
+}
+
+// SetUserReminders sets the attendee's own schedule, an empty one opts out.
+func (s *Storage) SetUserReminders(_ context.Context, eventID, userID int, minutes []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pair{eventID: eventID, userID: userID}
+	if _, ok := s.records[key]; !ok {
+		return &storage.NotFoundError{Err: fmt.Errorf("user %d not registered for event %d", userID, eventID)}
+	}
+	s.overrides[key] = append([]int{}, minutes...)
+
+	return nil
+}
+
+func (s *Storage) DellUserReminders(_ context.Context, eventID, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.overrides, pair{eventID: eventID, userID: userID})
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"graduation/internal/entity"
+	"sort"
+	"strings"
+	"time"
+)
+
+// searchTerms splits a query like websearch_to_tsquery: words joined by "or"
+// form a group of which an event must contain one, an event must match every
+// group and contain none of the words prefixed with "-".
+func searchTerms(query string) (include [][]string, exclude []string) {
+	or := false
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		word = strings.Trim(word, `"`)
+		switch {
+		case word == "":
+		case word == "or":
+			// "or" without a word before it is ignored
+			or = len(include) > 0
+		case strings.HasPrefix(word, "-"):
+			if word = strings.TrimPrefix(word, "-"); word != "" {
+				exclude = append(exclude, word)
+			}
+			or = false
+		case or:
+			include[len(include)-1] = append(include[len(include)-1], word)
+			or = false
+		default:
+			include = append(include, []string{word})
+		}
+	}
+
+	return include, exclude
+}
+
+// searchRank approximates the full-text search of Postgres: a word of every
+// included group must be found in the title, the place or the description,
+// which are weighted like the search column. Zero means the event does not
+// match.
+func searchRank(e *entity.Event, include [][]string, exclude []string) float64 {
+	title := strings.ToLower(e.Title)
+	place := strings.ToLower(e.Place)
+	description := strings.ToLower(e.Description)
+
+	for _, word := range exclude {
+		if strings.Contains(title, word) || strings.Contains(place, word) || strings.Contains(description, word) {
+			return 0
+		}
+	}
+
+	rank := 0.0
+	for _, group := range include {
+		best := 0.0
+		for _, word := range group {
+			found := 0.0
+			if strings.Contains(title, word) {
+				found += 1
+			}
+			if strings.Contains(place, word) {
+				found += 0.4
+			}
+			if strings.Contains(description, word) {
+				found += 0.2
+			}
+			best = max(best, found)
+		}
+		if best == 0 {
+			return 0
+		}
+		rank += best
+	}
+
+	return rank
+}
+
+// virtualEvents returns the occurrences of active series in the listing
+// period up to f.SeriesUntil that have no event yet, with a zero id. They are
+// never stored.
+func (s *Storage) virtualEvents(f *entity.EventFilter) ([]*entity.Event, error) {
+	if f.SeriesUntil.IsZero() {
+		return nil, nil
+	}
+
+	until := dbTime(f.To)
+	if seriesUntil := dbTime(f.SeriesUntil); seriesUntil.Before(until) {
+		until = seriesUntil
+	}
+
+	var events []*entity.Event
+	for _, series := range s.series {
+		if !series.Active || !series.MaterializedUntil.Before(until) {
+			continue
+		}
+		if f.OrganizerID != 0 && series.UserID != f.OrganizerID {
+			continue
+		}
+
+		from := dbTime(f.From)
+		if after := series.MaterializedUntil.Add(time.Second); after.After(from) {
+			from = after
+		}
+
+		occurrences, err := s.occurrences(series, from, until)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list series %d: %w", series.ID, err)
+		}
+		for _, occurrence := range occurrences {
+			events = append(events, &entity.Event{
+				UserID:          series.UserID,
+				Title:           series.Title,
+				Description:     series.Description,
+				Place:           series.Place,
+				MaxParticipants: series.MaxParticipants,
+				Date:            occurrence,
+				Active:          true,
+				SeriesID:        series.ID,
+				Occurrence:      occurrence,
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// GetEvents returns a page of the public listing and how many events match
+// the filter.
+func (s *Storage) GetEvents(_ context.Context, f *entity.EventFilter) ([]entity.Event, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	include, exclude := searchTerms(f.Query)
+	from, to := dbTime(f.From), dbTime(f.To)
+	place := strings.ToLower(f.Place)
+
+	candidates, err := s.virtualEvents(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, e := range s.events {
+		candidates = append(candidates, e)
+	}
+
+	ranks := make(map[*entity.Event]float64)
+	var matched []*entity.Event
+	for _, e := range candidates {
+		if !e.Active || e.Date.Before(from) || e.Date.After(to) {
+			continue
+		}
+		if f.OrganizerID != 0 && e.UserID != f.OrganizerID {
+			continue
+		}
+		if place != "" && !strings.Contains(strings.ToLower(e.Place), place) {
+			continue
+		}
+		if f.MinSeats > 0 && e.MaxParticipants-e.Participants < f.MinSeats {
+			continue
+		}
+		if f.Query != "" {
+			rank := searchRank(e, include, exclude)
+			if rank == 0 {
+				continue
+			}
+			ranks[e] = rank
+		}
+		matched = append(matched, e)
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return eventLess(f, ranks, matched[i], matched[j]) })
+
+	total := len(matched)
+	offset := (f.Page - 1) * f.Limit
+	if offset < 0 || offset >= total {
+		return nil, total, nil
+	}
+	matched = matched[offset:]
+	if len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+
+	events := make([]entity.Event, 0, len(matched))
+	for _, e := range matched {
+		events = append(events, s.event(e))
+	}
+
+	return events, total, nil
+}
+
+// eventLess orders the listing like the ORDER BY of Postgres.
+func eventLess(f *entity.EventFilter, ranks map[*entity.Event]float64, a, b *entity.Event) bool {
+	// occurrences without an event share the zero id
+	byID := func() bool {
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.SeriesID < b.SeriesID
+	}
+	byDate := func() bool {
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return byID()
+	}
+
+	switch f.Sort {
+	case entity.SortDateDesc:
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		return byID()
+	case entity.SortSeats:
+		if seatsA, seatsB := a.MaxParticipants-a.Participants, b.MaxParticipants-b.Participants; seatsA != seatsB {
+			return seatsA > seatsB
+		}
+	case entity.SortPopularity:
+		if a.Participants != b.Participants {
+			return a.Participants > b.Participants
+		}
+	case entity.SortRelevance:
+		if f.Query != "" && ranks[a] != ranks[b] {
+			return ranks[a] > ranks[b]
+		}
+	}
+
+	return byDate()
+}
@@ -0,0 +1,329 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/rrule"
+	"graduation/internal/storage"
+	"sort"
+	"time"
+)
+
+// maxOccurrences bounds how many events one series creates in a single run.
+const maxOccurrences = 500
+
+// occurrences returns the occurrences of series in [from, until] that have
+// no event yet and are not cancelled.
+func (s *Storage) occurrences(series *entity.Series, from, until time.Time) ([]time.Time, error) {
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rule: %w", err)
+	}
+
+	exist := make(map[time.Time]bool)
+	for _, e := range s.events {
+		if e.SeriesID == series.ID {
+			exist[e.Occurrence] = true
+		}
+	}
+
+	var occurrences []time.Time
+	for _, occurrence := range rule.Between(series.Start, from, until, maxOccurrences) {
+		occurrence = dbTime(occurrence)
+		if exist[occurrence] || s.exceptions[seriesException{seriesID: series.ID, occurrence: occurrence}] {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences, nil
+}
+
+// insertOccurrences creates events for the occurrences and returns their
+// ids.
+func (s *Storage) insertOccurrences(series *entity.Series, occurrences []time.Time) []int {
+	var ids []int
+	for _, occurrence := range occurrences {
+		id := s.nextID("event")
+		s.events[id] = &entity.Event{
+			ID:              id,
+			UserID:          series.UserID,
+			Title:           series.Title,
+			Description:     series.Description,
+			Place:           series.Place,
+			MaxParticipants: series.MaxParticipants,
+			Date:            occurrence,
+			Active:          true,
+			SeriesID:        series.ID,
+			Occurrence:      occurrence,
+		}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// addSeries stores a copy of series under a new id.
+func (s *Storage) addSeries(series *entity.Series) {
+	series.ID = s.nextID("series")
+
+	stored := *series
+	stored.Start = dbTime(series.Start)
+	stored.MaterializedUntil = dbTime(series.MaterializedUntil)
+	s.series[series.ID] = &stored
+}
+
+// CreateSeries saves the series and creates its occurrences up to until as
+// events. It returns the ids of these events.
+func (s *Storage) CreateSeries(_ context.Context, series *entity.Series, until time.Time) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.user(series.UserID); err != nil {
+		return nil, fmt.Errorf("cannot create series: %w", err)
+	}
+
+	// a new series has no events and no exceptions yet
+	occurrences, err := s.occurrences(&entity.Series{ID: -1, Start: series.Start, RRule: series.RRule}, series.Start, until)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create series: %w", err)
+	}
+
+	series.MaterializedUntil = until
+	series.Active = true
+	s.addSeries(series)
+
+	return s.insertOccurrences(s.series[series.ID], occurrences), nil
+}
+
+func (s *Storage) GetSeries(_ context.Context, seriesID int) (*entity.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[seriesID]
+	if !ok {
+		return nil, &storage.NotFoundError{Err: errors.New("series not exist")}
+	}
+
+	found := *series
+	return &found, nil
+}
+
+// MaterializeSeries creates the events of every active series up to until.
+func (s *Storage) MaterializeSeries(_ context.Context, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until = dbTime(until)
+
+	var ids []int
+	for id, series := range s.series {
+		if series.Active && series.MaterializedUntil.Before(until) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		series := s.series[id]
+
+		occurrences, err := s.occurrences(series, series.MaterializedUntil.Add(time.Second), until)
+		if err != nil {
+			return fmt.Errorf("cannot materialize series %d: %w", id, err)
+		}
+
+		s.insertOccurrences(series, occurrences)
+		series.MaterializedUntil = until
+	}
+
+	return nil
+}
+
+// errAttendees refuses to delete occurrences that somebody registered for or
+// waits for, nobody would tell them the event is gone.
+var errAttendees = errors.New("occurrence has attendees or a waitlist")
+
+// hasAttendees reports whether one of the events has participants or a
+// waitlist.
+func (s *Storage) hasAttendees(ids ...int) bool {
+	for _, id := range ids {
+		if s.events[id].Participants > 0 {
+			return true
+		}
+		for _, w := range s.waitlist {
+			if w.eventID == id {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CancelOccurrence deletes one event of the series and remembers its
+// occurrence, so the series does not create it again. An occurrence with
+// attendees or a waitlist is a ConflictError.
+func (s *Storage) CancelOccurrence(ctx context.Context, seriesID, eventID int) error {
+	s.mu.Lock()
+
+	e, ok := s.events[eventID]
+	if !ok || e.SeriesID != seriesID || seriesID == 0 {
+		s.mu.Unlock()
+		return &storage.NotFoundError{Err: errors.New("occurrence not exist")}
+	}
+	if s.hasAttendees(eventID) {
+		s.mu.Unlock()
+		return &storage.ConflictError{Err: errAttendees}
+	}
+
+	images := s.eventImages(eventID)
+	s.exceptions[seriesException{seriesID: seriesID, occurrence: e.Occurrence}] = true
+	s.dellEvent(eventID)
+	s.mu.Unlock()
+
+	s.removeObjects(ctx, objectNames(images))
+	return nil
+}
+
+// seriesEvents returns the ids of the events of the series from from on.
+func (s *Storage) seriesEvents(seriesID int, from time.Time) []int {
+	var ids []int
+	for id, e := range s.events {
+		if e.SeriesID == seriesID && !e.Occurrence.Before(from) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// TruncateSeries ends the series before from: the rule is replaced by rule
+// and the events from from on are deleted. A series truncated at its start
+// is deactivated. When one of these events has attendees or a waitlist
+// nothing changes and a ConflictError is returned.
+func (s *Storage) TruncateSeries(ctx context.Context, seriesID int, from time.Time, rule string) error {
+	s.mu.Lock()
+
+	series, ok := s.series[seriesID]
+	if !ok {
+		s.mu.Unlock()
+		return &storage.NotFoundError{Err: errors.New("series not exist")}
+	}
+
+	from = dbTime(from)
+	ids := s.seriesEvents(seriesID, from)
+	if s.hasAttendees(ids...) {
+		s.mu.Unlock()
+		return &storage.ConflictError{Err: errAttendees}
+	}
+
+	series.RRule = rule
+	series.Active = series.Active && from.After(series.Start)
+
+	var images []entity.Image
+	for _, id := range ids {
+		images = append(images, s.eventImages(id)...)
+		s.dellEvent(id)
+	}
+	s.mu.Unlock()
+
+	s.removeObjects(ctx, objectNames(images))
+	return nil
+}
+
+// SplitSeries applies an edit to the occurrences from from on. The old series
+// ends with rule, next becomes a new series that takes over the events of
+// these occurrences; changes are applied to every event, dates move by shift.
+// It returns the ids of the moved events.
+func (s *Storage) SplitSeries(_ context.Context, userID, seriesID int, from time.Time, rule string, next *entity.Series, changes []entity.EventChange, shift time.Duration, gen storage.TicketGenerator) ([]int, error) {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot json to byte: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[seriesID]
+	if !ok {
+		return nil, &storage.NotFoundError{Err: errors.New("series not exist")}
+	}
+
+	from = dbTime(from)
+	ids := s.seriesEvents(seriesID, from)
+	for _, id := range ids {
+		if s.events[id].Participants > next.MaxParticipants {
+			return nil, &storage.ConflictError{Err: fmt.Errorf("max participants %d less than participants", next.MaxParticipants)}
+		}
+	}
+
+	// tickets are signed before anything changes, a failed generator leaves
+	// the series as it is
+	var reissues []*reissue
+	if shift != 0 {
+		for _, id := range ids {
+			r, err := s.reissueTickets(id, s.events[id].Date.Add(shift), gen)
+			if err != nil {
+				return nil, fmt.Errorf("cannot split series: cannot reissue tickets: %w", err)
+			}
+			reissues = append(reissues, r)
+		}
+	}
+
+	next.MaterializedUntil = series.MaterializedUntil.Add(shift)
+	next.Active = true
+	s.addSeries(next)
+
+	series.RRule = rule
+	series.Active = series.Active && from.After(series.Start)
+	if from.Before(series.MaterializedUntil) {
+		series.MaterializedUntil = from
+	}
+
+	for exception := range s.exceptions {
+		if exception.seriesID == seriesID && !exception.occurrence.Before(from) {
+			delete(s.exceptions, exception)
+			s.exceptions[seriesException{seriesID: next.ID, occurrence: exception.occurrence.Add(shift)}] = true
+		}
+	}
+
+	for _, id := range ids {
+		e := s.events[id]
+		e.SeriesID = next.ID
+		e.Occurrence = e.Occurrence.Add(shift)
+		e.Date = e.Date.Add(shift)
+
+		applySeriesChanges(e, next, changes)
+	}
+
+	for _, r := range reissues {
+		s.applyReissue(r)
+	}
+
+	for _, id := range ids {
+		s.addChanges(id, userID, changes, body)
+	}
+
+	return ids, nil
+}
+
+// applySeriesChanges copies the changed fields of series to the event, so
+// occurrences edited one by one keep their other fields.
+func applySeriesChanges(e *entity.Event, series *entity.Series, changes []entity.EventChange) {
+	for _, change := range changes {
+		switch change.Field {
+		case "title":
+			e.Title = series.Title
+		case "description":
+			e.Description = series.Description
+		case "place":
+			e.Place = series.Place
+		case "participants":
+			e.MaxParticipants = series.MaxParticipants
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"time"
+)
+
+func (s *Storage) CreateSession(_ context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.user(userID); err != nil {
+		return 0, fmt.Errorf("cannot insert session: %w", err)
+	}
+	for _, other := range s.sessions {
+		if other.refreshHash == refreshHash {
+			return 0, errors.New("cannot insert session: duplicate refresh token")
+		}
+	}
+
+	id := s.nextID("sessions")
+	s.sessions[id] = &session{id: id, userID: userID, refreshHash: refreshHash, expiresAt: dbTime(expiresAt)}
+
+	return id, nil
+}
+
+// RefreshSession rotates the refresh token of an active session, so every
+// refresh token can be used only once.
+func (s *Storage) RefreshSession(_ context.Context, refreshHash, newHash string, expiresAt time.Time) (*entity.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, current := range s.sessions {
+		if current.refreshHash != refreshHash || current.revoked || !current.expiresAt.After(now()) {
+			continue
+		}
+
+		u, ok := s.users[current.userID]
+		if !ok {
+			break
+		}
+
+		current.refreshHash = newHash
+		current.expiresAt = dbTime(expiresAt)
+
+		return &entity.Session{ID: current.id, UserID: current.userID, Role: u.Role, ExpiresAt: expiresAt}, nil
+	}
+
+	return nil, &storage.NotFoundError{Err: errors.New("session not found")}
+}
+
+func (s *Storage) RevokeSession(_ context.Context, sessionID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.sessions[sessionID]; ok {
+		current.revoked = true
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeUserSessions(_ context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revokeUserSessions(userID)
+
+	return nil
+}
+
+func (s *Storage) revokeUserSessions(userID int) {
+	for _, current := range s.sessions {
+		if current.userID == userID {
+			current.revoked = true
+		}
+	}
+}
+
+func (s *Storage) SessionActive(_ context.Context, sessionID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.sessions[sessionID]
+	if !ok {
+		return false, nil
+	}
+
+	return !current.revoked && current.expiresAt.After(now()), nil
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"sort"
+)
+
+// ticketsWhere returns the tickets whose key matches in the order they were
+// issued.
+func (s *Storage) ticketsWhere(match func(pair) bool) []pair {
+	var keys []pair
+	for key := range s.tickets {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return s.tickets[keys[i]].id < s.tickets[keys[j]].id })
+
+	return keys
+}
+
+func (s *Storage) UserTickets(_ context.Context, userID int) ([]entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tickets []entity.Ticket
+	for _, key := range s.ticketsWhere(func(key pair) bool { return key.userID == userID }) {
+		t := s.tickets[key]
+		tickets = append(tickets, entity.Ticket{Token: t.token, EventID: key.eventID, Status: t.active})
+	}
+
+	return tickets, nil
+}
+
+func (s *Storage) GetTicket(_ context.Context, userID, eventID int) (*entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[pair{eventID: eventID, userID: userID}]
+	if !ok {
+		return nil, &storage.NotFoundError{Err: errors.New("ticket not exist")}
+	}
+
+	return &entity.Ticket{UserID: userID, EventID: eventID, Token: t.token, Status: t.active}, nil
+}
+
+// RedeemTicket marks an active ticket as used, only the first scan of a
+// ticket succeeds.
+func (s *Storage) RedeemTicket(_ context.Context, token string, scannerID int) (*entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, t := range s.tickets {
+		if t.token != token {
+			continue
+		}
+		if !t.active {
+			break
+		}
+		if !t.redeemedAt.IsZero() {
+			return nil, &storage.ConflictError{Err: fmt.Errorf("ticket already redeemed at %v", t.redeemedAt)}
+		}
+
+		t.redeemedAt = now()
+		t.redeemedBy = scannerID
+
+		return &entity.Ticket{
+			Token:      token,
+			UserID:     key.userID,
+			EventID:    key.eventID,
+			Status:     t.active,
+			RedeemedAt: t.redeemedAt,
+			RedeemedBy: t.redeemedBy,
+		}, nil
+	}
+
+	return nil, &storage.NotFoundError{Err: errors.New("ticket not exist")}
+}
+
+func (s *Storage) CheckinCount(_ context.Context, eventID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for key, t := range s.tickets {
+		if key.eventID == eventID && !t.redeemedAt.IsZero() {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *Storage) EventTickets(_ context.Context, eventID int) ([]entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tickets []entity.Ticket
+	for _, key := range s.ticketsWhere(func(key pair) bool { return key.eventID == eventID }) {
+		t := s.tickets[key]
+		tickets = append(tickets, entity.Ticket{
+			Token:      t.token,
+			UserID:     key.userID,
+			EventID:    eventID,
+			Status:     t.active,
+			RedeemedAt: t.redeemedAt,
+		})
+	}
+
+	return tickets, nil
+}
+
+func (s *Storage) RevokedTickets(_ context.Context, eventID int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tokens []string
+	for token, id := range s.revocations {
+		if id == eventID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Strings(tokens)
+
+	return tokens, nil
+}
+
+// MergeCheckins applies a scanner log, the earliest scan of a ticket wins.
+// Returns the number of updated tickets.
+func (s *Storage) MergeCheckins(_ context.Context, eventID, scannerID int, checkins []entity.Checkin) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := 0
+	for _, checkin := range checkins {
+		scannedAt := dbTime(checkin.ScannedAt)
+		for key, t := range s.tickets {
+			if t.token != checkin.Token || key.eventID != eventID || !t.active {
+				continue
+			}
+			if t.redeemedAt.IsZero() || t.redeemedAt.After(scannedAt) {
+				t.redeemedAt = scannedAt
+				t.redeemedBy = scannerID
+				merged++
+			}
+		}
+	}
+
+	return merged, nil
+}
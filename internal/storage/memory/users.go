@@ -0,0 +1,296 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/storage"
+	"sort"
+	"time"
+)
+
+func (s *Storage) SetUser(_ context.Context, login, password, mail, role string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Login == login {
+			return 0, &storage.ConflictError{Err: fmt.Errorf("user %s already exists", login)}
+		}
+	}
+
+	id := s.nextID("users")
+	s.users[id] = &user{
+		User:         entity.User{ID: id, Login: login, Password: password, Mail: mail, Role: role},
+		locale:       entity.DefaultLocale,
+		digestSentAt: now(),
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetUser(_ context.Context, login string) (*entity.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Login == login {
+			found := u.User
+			return &found, nil
+		}
+	}
+
+	return nil, &storage.NotFoundError{Err: fmt.Errorf("user %s not exist", login)}
+}
+
+// user returns the row of the user, the error is the one of a query that
+// found no row.
+func (s *Storage) user(userID int) (*user, error) {
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("user %d not exist", userID)
+	}
+
+	return u, nil
+}
+
+func (s *Storage) UpdatePassword(_ context.Context, userID int, password string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.Password = password
+	}
+
+	return nil
+}
+
+func (s *Storage) SetRole(_ context.Context, login, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Login == login {
+			u.Role = role
+			s.revokeUserSessions(u.ID)
+			return nil
+		}
+	}
+
+	return &storage.NotFoundError{Err: fmt.Errorf("user %s not exist", login)}
+}
+
+func (s *Storage) GetMail(_ context.Context, userID int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, err := s.user(userID)
+	if err != nil {
+		return "", fmt.Errorf("cannot scan: %w", err)
+	}
+
+	return u.Mail, nil
+}
+
+func (s *Storage) GetLocale(_ context.Context, userID int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, err := s.user(userID)
+	if err != nil {
+		return "", fmt.Errorf("cannot scan: %w", err)
+	}
+
+	return u.locale, nil
+}
+
+func (s *Storage) SetLocale(_ context.Context, userID int, locale string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		u.locale = locale
+	}
+
+	return nil
+}
+
+// SetSignupDigest turns the digest on or off, a digest turned on covers
+// signups from now on.
+func (s *Storage) SetSignupDigest(_ context.Context, userID int, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[userID]; ok {
+		if !u.signupDigest {
+			u.digestSentAt = now()
+		}
+		u.signupDigest = enabled
+	}
+
+	return nil
+}
+
+func (s *Storage) MailVerified(_ context.Context, userID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, err := s.user(userID)
+	if err != nil {
+		return false, fmt.Errorf("cannot scan: %w", err)
+	}
+
+	return u.MailVerified, nil
+}
+
+// VerifyMail marks the address as verified only while the user still has
+// it, as the account email or as the address of the email channel.
+func (s *Storage) VerifyMail(_ context.Context, userID int, mail string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return &storage.NotFoundError{Err: errors.New("mail changed after the link was sent")}
+	}
+
+	verified := false
+	if u.Mail == mail {
+		u.MailVerified = true
+		verified = true
+	}
+	for i, channel := range s.channels[userID] {
+		if channel.Channel == entity.ChannelEmail && channel.Address == mail {
+			s.channels[userID][i].Verified = true
+			verified = true
+		}
+	}
+	if !verified {
+		return &storage.NotFoundError{Err: errors.New("mail changed after the link was sent")}
+	}
+
+	return nil
+}
+
+func (s *Storage) CreatePasswordReset(_ context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.user(userID); err != nil {
+		return fmt.Errorf("cannot insert password reset: %w", err)
+	}
+	if _, ok := s.resets[tokenHash]; ok {
+		return errors.New("cannot insert password reset: duplicate token")
+	}
+
+	s.resets[tokenHash] = &passwordReset{userID: userID, expiresAt: dbTime(expiresAt)}
+
+	return nil
+}
+
+// ResetPassword uses up the token and every other token of the user, sets
+// the password, verifies the mail and revokes the sessions of the user.
+func (s *Storage) ResetPassword(_ context.Context, tokenHash, password string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reset, ok := s.resets[tokenHash]
+	if !ok || reset.used || !reset.expiresAt.After(now()) {
+		return &storage.NotFoundError{Err: errors.New("reset token not valid or already used")}
+	}
+
+	for _, other := range s.resets {
+		if other.userID == reset.userID {
+			other.used = true
+		}
+	}
+
+	if u, ok := s.users[reset.userID]; ok {
+		u.Password = password
+		u.MailVerified = true
+	}
+
+	s.revokeUserSessions(reset.userID)
+
+	return nil
+}
+
+// SetCalendarToken replaces the calendar feed token of the user.
+func (s *Storage) SetCalendarToken(_ context.Context, userID int, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.user(userID); err != nil {
+		return fmt.Errorf("cannot set calendar token: %w", err)
+	}
+	for id, hash := range s.calendarTokens {
+		if hash == tokenHash && id != userID {
+			return errors.New("cannot set calendar token: duplicate token")
+		}
+	}
+
+	s.calendarTokens[userID] = tokenHash
+
+	return nil
+}
+
+func (s *Storage) CalendarUser(_ context.Context, tokenHash string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID, hash := range s.calendarTokens {
+		if hash == tokenHash {
+			return userID, nil
+		}
+	}
+
+	return 0, &storage.NotFoundError{Err: errors.New("calendar token not exist")}
+}
+
+// UserChannels returns the channels the user picked ordered by channel.
+func (s *Storage) UserChannels(_ context.Context, userID int) ([]entity.UserChannel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels := s.channels[userID]
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	return append([]entity.UserChannel(nil), channels...), nil
+}
+
+// SetUserChannels replaces the channels of the user. A channel that keeps
+// its address stays verified, a new address has to be verified again.
+func (s *Storage) SetUserChannels(_ context.Context, userID int, channels []entity.UserChannel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.user(userID); err != nil {
+		return fmt.Errorf("cannot set user channels: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, channel := range channels {
+		if seen[channel.Channel] {
+			return fmt.Errorf("cannot set user channels: channel %s twice", channel.Channel)
+		}
+		seen[channel.Channel] = true
+	}
+
+	sorted := make([]entity.UserChannel, 0, len(channels))
+	for _, channel := range channels {
+		verified := false
+		for _, old := range s.channels[userID] {
+			if old.Channel == channel.Channel && old.Address == channel.Address {
+				verified = old.Verified
+			}
+		}
+		sorted = append(sorted, entity.UserChannel{Channel: channel.Channel, Address: channel.Address, Verified: verified})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Channel < sorted[j].Channel })
+	s.channels[userID] = sorted
+
+	return nil
+}
@@ -7,12 +7,52 @@ package mock
 import (
 	context "context"
 	entity "graduation/internal/entity"
+	ostorage "graduation/internal/ostorage"
+	storage "graduation/internal/storage"
+	io "io"
 	reflect "reflect"
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockTicketGenerator is a mock of TicketGenerator interface.
+type MockTicketGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockTicketGeneratorMockRecorder
+}
+
+// MockTicketGeneratorMockRecorder is the mock recorder for MockTicketGenerator.
+type MockTicketGeneratorMockRecorder struct {
+	mock *MockTicketGenerator
+}
+
+// NewMockTicketGenerator creates a new mock instance.
+func NewMockTicketGenerator(ctrl *gomock.Controller) *MockTicketGenerator {
+	mock := &MockTicketGenerator{ctrl: ctrl}
+	mock.recorder = &MockTicketGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTicketGenerator) EXPECT() *MockTicketGeneratorMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockTicketGenerator) Generate(tick *entity.Ticket) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", tick)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockTicketGeneratorMockRecorder) Generate(tick interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockTicketGenerator)(nil).Generate), tick)
+}
+
 // MockUserStorage is a mock of UserStorage interface.
 type MockUserStorage struct {
 	ctrl     *gomock.Controller
@@ -37,11 +77,12 @@ func (m *MockUserStorage) EXPECT() *MockUserStorageMockRecorder {
 }
 
 // AddEventUser mocks base method.
-func (m *MockUserStorage) AddEventUser(ctx context.Context, tick *entity.Ticket) error {
+func (m *MockUserStorage) AddEventUser(ctx context.Context, tick *entity.Ticket) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddEventUser", ctx, tick)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddEventUser indicates an expected call of AddEventUser.
@@ -50,33 +91,122 @@ func (mr *MockUserStorageMockRecorder) AddEventUser(ctx, tick interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventUser", reflect.TypeOf((*MockUserStorage)(nil).AddEventUser), ctx, tick)
 }
 
-// DellEventUser mocks base method.
-func (m *MockUserStorage) DellEventUser(ctx context.Context, eventID, userID int) error {
+// CalendarUser mocks base method.
+func (m *MockUserStorage) CalendarUser(ctx context.Context, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarUser", ctx, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarUser indicates an expected call of CalendarUser.
+func (mr *MockUserStorageMockRecorder) CalendarUser(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarUser", reflect.TypeOf((*MockUserStorage)(nil).CalendarUser), ctx, tokenHash)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockUserStorage) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DellEventUser", ctx, eventID, userID)
+	ret := m.ctrl.Call(m, "CreatePasswordReset", ctx, userID, tokenHash, expiresAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockUserStorageMockRecorder) CreatePasswordReset(ctx, userID, tokenHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockUserStorage)(nil).CreatePasswordReset), ctx, userID, tokenHash, expiresAt)
+}
+
+// DellEventUser mocks base method.
+func (m *MockUserStorage) DellEventUser(ctx context.Context, eventID, userID int, gen storage.TicketGenerator) (*entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellEventUser", ctx, eventID, userID, gen)
+	ret0, _ := ret[0].(*entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DellEventUser indicates an expected call of DellEventUser.
-func (mr *MockUserStorageMockRecorder) DellEventUser(ctx, eventID, userID interface{}) *gomock.Call {
+func (mr *MockUserStorageMockRecorder) DellEventUser(ctx, eventID, userID, gen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEventUser", reflect.TypeOf((*MockUserStorage)(nil).DellEventUser), ctx, eventID, userID, gen)
+}
+
+// DellUserReminders mocks base method.
+func (m *MockUserStorage) DellUserReminders(ctx context.Context, eventID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellUserReminders", ctx, eventID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DellUserReminders indicates an expected call of DellUserReminders.
+func (mr *MockUserStorageMockRecorder) DellUserReminders(ctx, eventID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellUserReminders", reflect.TypeOf((*MockUserStorage)(nil).DellUserReminders), ctx, eventID, userID)
+}
+
+// GetLocale mocks base method.
+func (m *MockUserStorage) GetLocale(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocale", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocale indicates an expected call of GetLocale.
+func (mr *MockUserStorageMockRecorder) GetLocale(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocale", reflect.TypeOf((*MockUserStorage)(nil).GetLocale), ctx, userID)
+}
+
+// GetMail mocks base method.
+func (m *MockUserStorage) GetMail(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMail", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMail indicates an expected call of GetMail.
+func (mr *MockUserStorageMockRecorder) GetMail(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMail", reflect.TypeOf((*MockUserStorage)(nil).GetMail), ctx, userID)
+}
+
+// GetTicket mocks base method.
+func (m *MockUserStorage) GetTicket(ctx context.Context, userID, eventID int) (*entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicket", ctx, userID, eventID)
+	ret0, _ := ret[0].(*entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicket indicates an expected call of GetTicket.
+func (mr *MockUserStorageMockRecorder) GetTicket(ctx, userID, eventID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEventUser", reflect.TypeOf((*MockUserStorage)(nil).DellEventUser), ctx, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicket", reflect.TypeOf((*MockUserStorage)(nil).GetTicket), ctx, userID, eventID)
 }
 
 // GetUser mocks base method.
-func (m *MockUserStorage) GetUser(ctx context.Context, login, password string) (int, error) {
+func (m *MockUserStorage) GetUser(ctx context.Context, login string) (*entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, login, password)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "GetUser", ctx, login)
+	ret0, _ := ret[0].(*entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUser indicates an expected call of GetUser.
-func (mr *MockUserStorageMockRecorder) GetUser(ctx, login, password interface{}) *gomock.Call {
+func (mr *MockUserStorageMockRecorder) GetUser(ctx, login interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserStorage)(nil).GetUser), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserStorage)(nil).GetUser), ctx, login)
 }
 
 // GetUserEvents mocks base method.
@@ -91,471 +221,2062 @@ func (m *MockUserStorage) GetUserEvents(ctx context.Context, userID int) ([]enti
 // GetUserEvents indicates an expected call of GetUserEvents.
 func (mr *MockUserStorageMockRecorder) GetUserEvents(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEvents", reflect.TypeOf((*MockUserStorage)(nil).GetUserEvents), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEvents", reflect.TypeOf((*MockUserStorage)(nil).GetUserEvents), ctx, userID)
+}
+
+// MailVerified mocks base method.
+func (m *MockUserStorage) MailVerified(ctx context.Context, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailVerified", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MailVerified indicates an expected call of MailVerified.
+func (mr *MockUserStorageMockRecorder) MailVerified(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailVerified", reflect.TypeOf((*MockUserStorage)(nil).MailVerified), ctx, userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserStorage) ResetPassword(ctx context.Context, tokenHash, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserStorageMockRecorder) ResetPassword(ctx, tokenHash, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserStorage)(nil).ResetPassword), ctx, tokenHash, password)
+}
+
+// SetCalendarToken mocks base method.
+func (m *MockUserStorage) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCalendarToken", ctx, userID, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCalendarToken indicates an expected call of SetCalendarToken.
+func (mr *MockUserStorageMockRecorder) SetCalendarToken(ctx, userID, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCalendarToken", reflect.TypeOf((*MockUserStorage)(nil).SetCalendarToken), ctx, userID, tokenHash)
+}
+
+// SetLocale mocks base method.
+func (m *MockUserStorage) SetLocale(ctx context.Context, userID int, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocale", ctx, userID, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocale indicates an expected call of SetLocale.
+func (mr *MockUserStorageMockRecorder) SetLocale(ctx, userID, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocale", reflect.TypeOf((*MockUserStorage)(nil).SetLocale), ctx, userID, locale)
+}
+
+// SetRole mocks base method.
+func (m *MockUserStorage) SetRole(ctx context.Context, login, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", ctx, login, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUserStorageMockRecorder) SetRole(ctx, login, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUserStorage)(nil).SetRole), ctx, login, role)
+}
+
+// SetSignupDigest mocks base method.
+func (m *MockUserStorage) SetSignupDigest(ctx context.Context, userID int, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSignupDigest", ctx, userID, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSignupDigest indicates an expected call of SetSignupDigest.
+func (mr *MockUserStorageMockRecorder) SetSignupDigest(ctx, userID, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSignupDigest", reflect.TypeOf((*MockUserStorage)(nil).SetSignupDigest), ctx, userID, enabled)
+}
+
+// SetUser mocks base method.
+func (m *MockUserStorage) SetUser(ctx context.Context, login, password, mail, role string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUser", ctx, login, password, mail, role)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUser indicates an expected call of SetUser.
+func (mr *MockUserStorageMockRecorder) SetUser(ctx, login, password, mail, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUser", reflect.TypeOf((*MockUserStorage)(nil).SetUser), ctx, login, password, mail, role)
+}
+
+// SetUserChannels mocks base method.
+func (m *MockUserStorage) SetUserChannels(ctx context.Context, userID int, channels []entity.UserChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserChannels", ctx, userID, channels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserChannels indicates an expected call of SetUserChannels.
+func (mr *MockUserStorageMockRecorder) SetUserChannels(ctx, userID, channels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserChannels", reflect.TypeOf((*MockUserStorage)(nil).SetUserChannels), ctx, userID, channels)
+}
+
+// SetUserReminders mocks base method.
+func (m *MockUserStorage) SetUserReminders(ctx context.Context, eventID, userID int, minutes []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserReminders", ctx, eventID, userID, minutes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserReminders indicates an expected call of SetUserReminders.
+func (mr *MockUserStorageMockRecorder) SetUserReminders(ctx, eventID, userID, minutes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserReminders", reflect.TypeOf((*MockUserStorage)(nil).SetUserReminders), ctx, eventID, userID, minutes)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserStorage) UpdatePassword(ctx context.Context, userID int, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserStorageMockRecorder) UpdatePassword(ctx, userID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserStorage)(nil).UpdatePassword), ctx, userID, password)
+}
+
+// UserChannels mocks base method.
+func (m *MockUserStorage) UserChannels(ctx context.Context, userID int) ([]entity.UserChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserChannels", ctx, userID)
+	ret0, _ := ret[0].([]entity.UserChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserChannels indicates an expected call of UserChannels.
+func (mr *MockUserStorageMockRecorder) UserChannels(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserChannels", reflect.TypeOf((*MockUserStorage)(nil).UserChannels), ctx, userID)
+}
+
+// UserReminders mocks base method.
+func (m *MockUserStorage) UserReminders(ctx context.Context, eventID, userID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserReminders", ctx, eventID, userID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserReminders indicates an expected call of UserReminders.
+func (mr *MockUserStorageMockRecorder) UserReminders(ctx, eventID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserReminders", reflect.TypeOf((*MockUserStorage)(nil).UserReminders), ctx, eventID, userID)
+}
+
+// UserTickets mocks base method.
+func (m *MockUserStorage) UserTickets(ctx context.Context, userID int) ([]entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserTickets", ctx, userID)
+	ret0, _ := ret[0].([]entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserTickets indicates an expected call of UserTickets.
+func (mr *MockUserStorageMockRecorder) UserTickets(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserTickets", reflect.TypeOf((*MockUserStorage)(nil).UserTickets), ctx, userID)
+}
+
+// VerifyMail mocks base method.
+func (m *MockUserStorage) VerifyMail(ctx context.Context, userID int, mail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMail", ctx, userID, mail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyMail indicates an expected call of VerifyMail.
+func (mr *MockUserStorageMockRecorder) VerifyMail(ctx, userID, mail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMail", reflect.TypeOf((*MockUserStorage)(nil).VerifyMail), ctx, userID, mail)
+}
+
+// MockEventStorage is a mock of EventStorage interface.
+type MockEventStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStorageMockRecorder
+}
+
+// MockEventStorageMockRecorder is the mock recorder for MockEventStorage.
+type MockEventStorageMockRecorder struct {
+	mock *MockEventStorage
+}
+
+// NewMockEventStorage creates a new mock instance.
+func NewMockEventStorage(ctrl *gomock.Controller) *MockEventStorage {
+	mock := &MockEventStorage{ctrl: ctrl}
+	mock.recorder = &MockEventStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStorage) EXPECT() *MockEventStorageMockRecorder {
+	return m.recorder
+}
+
+// AddEventImages mocks base method.
+func (m *MockEventStorage) AddEventImages(ctx context.Context, eventID int, images []entity.Image) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEventImages", ctx, eventID, images)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEventImages indicates an expected call of AddEventImages.
+func (mr *MockEventStorageMockRecorder) AddEventImages(ctx, eventID, images interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventImages", reflect.TypeOf((*MockEventStorage)(nil).AddEventImages), ctx, eventID, images)
+}
+
+// CancelOccurrence mocks base method.
+func (m *MockEventStorage) CancelOccurrence(ctx context.Context, seriesID, eventID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOccurrence", ctx, seriesID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOccurrence indicates an expected call of CancelOccurrence.
+func (mr *MockEventStorageMockRecorder) CancelOccurrence(ctx, seriesID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOccurrence", reflect.TypeOf((*MockEventStorage)(nil).CancelOccurrence), ctx, seriesID, eventID)
+}
+
+// CheckinCount mocks base method.
+func (m *MockEventStorage) CheckinCount(ctx context.Context, eventID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckinCount", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckinCount indicates an expected call of CheckinCount.
+func (mr *MockEventStorageMockRecorder) CheckinCount(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckinCount", reflect.TypeOf((*MockEventStorage)(nil).CheckinCount), ctx, eventID)
+}
+
+// CloseEvent mocks base method.
+func (m *MockEventStorage) CloseEvent(ctx context.Context, userID, eventID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseEvent", ctx, userID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseEvent indicates an expected call of CloseEvent.
+func (mr *MockEventStorageMockRecorder) CloseEvent(ctx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseEvent", reflect.TypeOf((*MockEventStorage)(nil).CloseEvent), ctx, userID, eventID)
+}
+
+// CollectImageGarbage mocks base method.
+func (m *MockEventStorage) CollectImageGarbage(ctx context.Context, uploadedBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectImageGarbage", ctx, uploadedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectImageGarbage indicates an expected call of CollectImageGarbage.
+func (mr *MockEventStorageMockRecorder) CollectImageGarbage(ctx, uploadedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectImageGarbage", reflect.TypeOf((*MockEventStorage)(nil).CollectImageGarbage), ctx, uploadedBefore)
+}
+
+// CreateEvent mocks base method.
+func (m *MockEventStorage) CreateEvent(ctx context.Context, e *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockEventStorageMockRecorder) CreateEvent(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockEventStorage)(nil).CreateEvent), ctx, e)
+}
+
+// CreateSeries mocks base method.
+func (m *MockEventStorage) CreateSeries(ctx context.Context, series *entity.Series, until time.Time) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", ctx, series, until)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockEventStorageMockRecorder) CreateSeries(ctx, series, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockEventStorage)(nil).CreateSeries), ctx, series, until)
+}
+
+// DellEvent mocks base method.
+func (m *MockEventStorage) DellEvent(ctx context.Context, userID, eventID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellEvent", ctx, userID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DellEvent indicates an expected call of DellEvent.
+func (mr *MockEventStorageMockRecorder) DellEvent(ctx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEvent", reflect.TypeOf((*MockEventStorage)(nil).DellEvent), ctx, userID, eventID)
+}
+
+// DellEventImage mocks base method.
+func (m *MockEventStorage) DellEventImage(ctx context.Context, eventID int, filename string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellEventImage", ctx, eventID, filename)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DellEventImage indicates an expected call of DellEventImage.
+func (mr *MockEventStorageMockRecorder) DellEventImage(ctx, eventID, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEventImage", reflect.TypeOf((*MockEventStorage)(nil).DellEventImage), ctx, eventID, filename)
+}
+
+// EventReminders mocks base method.
+func (m *MockEventStorage) EventReminders(ctx context.Context, eventID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventReminders", ctx, eventID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventReminders indicates an expected call of EventReminders.
+func (mr *MockEventStorageMockRecorder) EventReminders(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventReminders", reflect.TypeOf((*MockEventStorage)(nil).EventReminders), ctx, eventID)
+}
+
+// EventTickets mocks base method.
+func (m *MockEventStorage) EventTickets(ctx context.Context, eventID int) ([]entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventTickets", ctx, eventID)
+	ret0, _ := ret[0].([]entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventTickets indicates an expected call of EventTickets.
+func (mr *MockEventStorageMockRecorder) EventTickets(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventTickets", reflect.TypeOf((*MockEventStorage)(nil).EventTickets), ctx, eventID)
+}
+
+// GetDateEvent mocks base method.
+func (m *MockEventStorage) GetDateEvent(ctx context.Context, eventID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDateEvent", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDateEvent indicates an expected call of GetDateEvent.
+func (mr *MockEventStorageMockRecorder) GetDateEvent(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDateEvent", reflect.TypeOf((*MockEventStorage)(nil).GetDateEvent), ctx, eventID)
+}
+
+// GetEvent mocks base method.
+func (m *MockEventStorage) GetEvent(ctx context.Context, eventID int) (*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
+	ret0, _ := ret[0].(*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockEventStorageMockRecorder) GetEvent(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockEventStorage)(nil).GetEvent), ctx, eventID)
+}
+
+// GetEvents mocks base method.
+func (m *MockEventStorage) GetEvents(ctx context.Context, filter *entity.EventFilter) ([]entity.Event, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx, filter)
+	ret0, _ := ret[0].([]entity.Event)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockEventStorageMockRecorder) GetEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockEventStorage)(nil).GetEvents), ctx, filter)
+}
+
+// GetImage mocks base method.
+func (m *MockEventStorage) GetImage(ctx context.Context, filename string, expires time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImage", ctx, filename, expires)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImage indicates an expected call of GetImage.
+func (mr *MockEventStorageMockRecorder) GetImage(ctx, filename, expires interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockEventStorage)(nil).GetImage), ctx, filename, expires)
+}
+
+// GetSeries mocks base method.
+func (m *MockEventStorage) GetSeries(ctx context.Context, seriesID int) (*entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, seriesID)
+	ret0, _ := ret[0].(*entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockEventStorageMockRecorder) GetSeries(ctx, seriesID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockEventStorage)(nil).GetSeries), ctx, seriesID)
+}
+
+// MergeCheckins mocks base method.
+func (m *MockEventStorage) MergeCheckins(ctx context.Context, eventID, scannerID int, checkins []entity.Checkin) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCheckins", ctx, eventID, scannerID, checkins)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeCheckins indicates an expected call of MergeCheckins.
+func (mr *MockEventStorageMockRecorder) MergeCheckins(ctx, eventID, scannerID, checkins interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCheckins", reflect.TypeOf((*MockEventStorage)(nil).MergeCheckins), ctx, eventID, scannerID, checkins)
+}
+
+// OpenImage mocks base method.
+func (m *MockEventStorage) OpenImage(ctx context.Context, filename string) (io.ReadSeekCloser, *ostorage.Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenImage", ctx, filename)
+	ret0, _ := ret[0].(io.ReadSeekCloser)
+	ret1, _ := ret[1].(*ostorage.Object)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenImage indicates an expected call of OpenImage.
+func (mr *MockEventStorageMockRecorder) OpenImage(ctx, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenImage", reflect.TypeOf((*MockEventStorage)(nil).OpenImage), ctx, filename)
+}
+
+// RedeemTicket mocks base method.
+func (m *MockEventStorage) RedeemTicket(ctx context.Context, token string, scannerID int) (*entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemTicket", ctx, token, scannerID)
+	ret0, _ := ret[0].(*entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemTicket indicates an expected call of RedeemTicket.
+func (mr *MockEventStorageMockRecorder) RedeemTicket(ctx, token, scannerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemTicket", reflect.TypeOf((*MockEventStorage)(nil).RedeemTicket), ctx, token, scannerID)
+}
+
+// ReorderEventImages mocks base method.
+func (m *MockEventStorage) ReorderEventImages(ctx context.Context, eventID int, filenames []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderEventImages", ctx, eventID, filenames)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderEventImages indicates an expected call of ReorderEventImages.
+func (mr *MockEventStorageMockRecorder) ReorderEventImages(ctx, eventID, filenames interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderEventImages", reflect.TypeOf((*MockEventStorage)(nil).ReorderEventImages), ctx, eventID, filenames)
+}
+
+// RevokedTickets mocks base method.
+func (m *MockEventStorage) RevokedTickets(ctx context.Context, eventID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokedTickets", ctx, eventID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokedTickets indicates an expected call of RevokedTickets.
+func (mr *MockEventStorageMockRecorder) RevokedTickets(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokedTickets", reflect.TypeOf((*MockEventStorage)(nil).RevokedTickets), ctx, eventID)
+}
+
+// SetEventCover mocks base method.
+func (m *MockEventStorage) SetEventCover(ctx context.Context, eventID int, filename string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEventCover", ctx, eventID, filename)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEventCover indicates an expected call of SetEventCover.
+func (mr *MockEventStorageMockRecorder) SetEventCover(ctx, eventID, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventCover", reflect.TypeOf((*MockEventStorage)(nil).SetEventCover), ctx, eventID, filename)
+}
+
+// SetEventReminders mocks base method.
+func (m *MockEventStorage) SetEventReminders(ctx context.Context, eventID int, minutes []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEventReminders", ctx, eventID, minutes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEventReminders indicates an expected call of SetEventReminders.
+func (mr *MockEventStorageMockRecorder) SetEventReminders(ctx, eventID, minutes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventReminders", reflect.TypeOf((*MockEventStorage)(nil).SetEventReminders), ctx, eventID, minutes)
+}
+
+// SplitSeries mocks base method.
+func (m *MockEventStorage) SplitSeries(ctx context.Context, userID, seriesID int, from time.Time, rule string, next *entity.Series, changes []entity.EventChange, shift time.Duration, gen storage.TicketGenerator) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitSeries", ctx, userID, seriesID, from, rule, next, changes, shift, gen)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitSeries indicates an expected call of SplitSeries.
+func (mr *MockEventStorageMockRecorder) SplitSeries(ctx, userID, seriesID, from, rule, next, changes, shift, gen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeries", reflect.TypeOf((*MockEventStorage)(nil).SplitSeries), ctx, userID, seriesID, from, rule, next, changes, shift, gen)
+}
+
+// TruncateSeries mocks base method.
+func (m *MockEventStorage) TruncateSeries(ctx context.Context, seriesID int, from time.Time, rule string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TruncateSeries", ctx, seriesID, from, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TruncateSeries indicates an expected call of TruncateSeries.
+func (mr *MockEventStorageMockRecorder) TruncateSeries(ctx, seriesID, from, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TruncateSeries", reflect.TypeOf((*MockEventStorage)(nil).TruncateSeries), ctx, seriesID, from, rule)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockEventStorage) UpdateEvent(ctx context.Context, userID int, e *entity.Event, changes []entity.EventChange, gen storage.TicketGenerator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, userID, e, changes, gen)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockEventStorageMockRecorder) UpdateEvent(ctx, userID, e, changes, gen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockEventStorage)(nil).UpdateEvent), ctx, userID, e, changes, gen)
+}
+
+// MockNotificationStorage is a mock of NotificationStorage interface.
+type MockNotificationStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationStorageMockRecorder
+}
+
+// MockNotificationStorageMockRecorder is the mock recorder for MockNotificationStorage.
+type MockNotificationStorageMockRecorder struct {
+	mock *MockNotificationStorage
+}
+
+// NewMockNotificationStorage creates a new mock instance.
+func NewMockNotificationStorage(ctrl *gomock.Controller) *MockNotificationStorage {
+	mock := &MockNotificationStorage{ctrl: ctrl}
+	mock.recorder = &MockNotificationStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationStorage) EXPECT() *MockNotificationStorageMockRecorder {
+	return m.recorder
+}
+
+// ChangeNoticeSent mocks base method.
+func (m *MockNotificationStorage) ChangeNoticeSent(ctx context.Context, noticeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeNoticeSent", ctx, noticeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeNoticeSent indicates an expected call of ChangeNoticeSent.
+func (mr *MockNotificationStorageMockRecorder) ChangeNoticeSent(ctx, noticeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeNoticeSent", reflect.TypeOf((*MockNotificationStorage)(nil).ChangeNoticeSent), ctx, noticeID)
+}
+
+// ClaimMail mocks base method.
+func (m *MockNotificationStorage) ClaimMail(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMail", ctx, limit, lease)
+	ret0, _ := ret[0].([]entity.OutboxMail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMail indicates an expected call of ClaimMail.
+func (mr *MockNotificationStorageMockRecorder) ClaimMail(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMail", reflect.TypeOf((*MockNotificationStorage)(nil).ClaimMail), ctx, limit, lease)
+}
+
+// CloseFinishedEvents mocks base method.
+func (m *MockNotificationStorage) CloseFinishedEvents(ctx context.Context, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseFinishedEvents", ctx, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseFinishedEvents indicates an expected call of CloseFinishedEvents.
+func (mr *MockNotificationStorageMockRecorder) CloseFinishedEvents(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseFinishedEvents", reflect.TypeOf((*MockNotificationStorage)(nil).CloseFinishedEvents), ctx, date)
+}
+
+// DigestSent mocks base method.
+func (m *MockNotificationStorage) DigestSent(ctx context.Context, userID int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DigestSent", ctx, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DigestSent indicates an expected call of DigestSent.
+func (mr *MockNotificationStorageMockRecorder) DigestSent(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DigestSent", reflect.TypeOf((*MockNotificationStorage)(nil).DigestSent), ctx, userID, at)
+}
+
+// DueDigests mocks base method.
+func (m *MockNotificationStorage) DueDigests(ctx context.Context, now time.Time, every time.Duration) ([]entity.Digest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueDigests", ctx, now, every)
+	ret0, _ := ret[0].([]entity.Digest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueDigests indicates an expected call of DueDigests.
+func (mr *MockNotificationStorageMockRecorder) DueDigests(ctx, now, every interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueDigests", reflect.TypeOf((*MockNotificationStorage)(nil).DueDigests), ctx, now, every)
+}
+
+// DueReminders mocks base method.
+func (m *MockNotificationStorage) DueReminders(ctx context.Context, now time.Time, limit int) ([]entity.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueReminders", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueReminders indicates an expected call of DueReminders.
+func (mr *MockNotificationStorageMockRecorder) DueReminders(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueReminders", reflect.TypeOf((*MockNotificationStorage)(nil).DueReminders), ctx, now, limit)
+}
+
+// EnqueueMail mocks base method.
+func (m *MockNotificationStorage) EnqueueMail(ctx context.Context, mails []entity.OutboxMail) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueMail", ctx, mails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueMail indicates an expected call of EnqueueMail.
+func (mr *MockNotificationStorageMockRecorder) EnqueueMail(ctx, mails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueMail", reflect.TypeOf((*MockNotificationStorage)(nil).EnqueueMail), ctx, mails)
+}
+
+// GetChangeNotices mocks base method.
+func (m *MockNotificationStorage) GetChangeNotices(ctx context.Context, limit int) ([]entity.ChangeNotice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeNotices", ctx, limit)
+	ret0, _ := ret[0].([]entity.ChangeNotice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeNotices indicates an expected call of GetChangeNotices.
+func (mr *MockNotificationStorageMockRecorder) GetChangeNotices(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeNotices", reflect.TypeOf((*MockNotificationStorage)(nil).GetChangeNotices), ctx, limit)
+}
+
+// GetRegistrationNotices mocks base method.
+func (m *MockNotificationStorage) GetRegistrationNotices(ctx context.Context, limit int) ([]entity.RegistrationNotice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistrationNotices", ctx, limit)
+	ret0, _ := ret[0].([]entity.RegistrationNotice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistrationNotices indicates an expected call of GetRegistrationNotices.
+func (mr *MockNotificationStorageMockRecorder) GetRegistrationNotices(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistrationNotices", reflect.TypeOf((*MockNotificationStorage)(nil).GetRegistrationNotices), ctx, limit)
+}
+
+// MailDead mocks base method.
+func (m *MockNotificationStorage) MailDead(ctx context.Context, mailID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailDead", ctx, mailID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MailDead indicates an expected call of MailDead.
+func (mr *MockNotificationStorageMockRecorder) MailDead(ctx, mailID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailDead", reflect.TypeOf((*MockNotificationStorage)(nil).MailDead), ctx, mailID, reason)
+}
+
+// MailRetry mocks base method.
+func (m *MockNotificationStorage) MailRetry(ctx context.Context, mailID int, reason string, next time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailRetry", ctx, mailID, reason, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MailRetry indicates an expected call of MailRetry.
+func (mr *MockNotificationStorageMockRecorder) MailRetry(ctx, mailID, reason, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailRetry", reflect.TypeOf((*MockNotificationStorage)(nil).MailRetry), ctx, mailID, reason, next)
+}
+
+// MailSent mocks base method.
+func (m *MockNotificationStorage) MailSent(ctx context.Context, mailID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailSent", ctx, mailID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MailSent indicates an expected call of MailSent.
+func (mr *MockNotificationStorageMockRecorder) MailSent(ctx, mailID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailSent", reflect.TypeOf((*MockNotificationStorage)(nil).MailSent), ctx, mailID)
+}
+
+// MaterializeSeries mocks base method.
+func (m *MockNotificationStorage) MaterializeSeries(ctx context.Context, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaterializeSeries", ctx, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MaterializeSeries indicates an expected call of MaterializeSeries.
+func (mr *MockNotificationStorageMockRecorder) MaterializeSeries(ctx, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeSeries", reflect.TypeOf((*MockNotificationStorage)(nil).MaterializeSeries), ctx, until)
+}
+
+// NextReminder mocks base method.
+func (m *MockNotificationStorage) NextReminder(ctx context.Context, now time.Time) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextReminder", ctx, now)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextReminder indicates an expected call of NextReminder.
+func (mr *MockNotificationStorageMockRecorder) NextReminder(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextReminder", reflect.TypeOf((*MockNotificationStorage)(nil).NextReminder), ctx, now)
+}
+
+// OutboxMails mocks base method.
+func (m *MockNotificationStorage) OutboxMails(ctx context.Context, status string, limit, offset int) ([]entity.OutboxMail, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutboxMails", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]entity.OutboxMail)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OutboxMails indicates an expected call of OutboxMails.
+func (mr *MockNotificationStorageMockRecorder) OutboxMails(ctx, status, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboxMails", reflect.TypeOf((*MockNotificationStorage)(nil).OutboxMails), ctx, status, limit, offset)
+}
+
+// RegistrationNoticeSent mocks base method.
+func (m *MockNotificationStorage) RegistrationNoticeSent(ctx context.Context, noticeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistrationNoticeSent", ctx, noticeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegistrationNoticeSent indicates an expected call of RegistrationNoticeSent.
+func (mr *MockNotificationStorageMockRecorder) RegistrationNoticeSent(ctx, noticeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistrationNoticeSent", reflect.TypeOf((*MockNotificationStorage)(nil).RegistrationNoticeSent), ctx, noticeID)
+}
+
+// ReminderSent mocks base method.
+func (m *MockNotificationStorage) ReminderSent(ctx context.Context, eventID, userID int, minutes []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReminderSent", ctx, eventID, userID, minutes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReminderSent indicates an expected call of ReminderSent.
+func (mr *MockNotificationStorageMockRecorder) ReminderSent(ctx, eventID, userID, minutes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReminderSent", reflect.TypeOf((*MockNotificationStorage)(nil).ReminderSent), ctx, eventID, userID, minutes)
+}
+
+// ReplayMail mocks base method.
+func (m *MockNotificationStorage) ReplayMail(ctx context.Context, mailID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayMail", ctx, mailID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayMail indicates an expected call of ReplayMail.
+func (mr *MockNotificationStorageMockRecorder) ReplayMail(ctx, mailID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayMail", reflect.TypeOf((*MockNotificationStorage)(nil).ReplayMail), ctx, mailID)
+}
+
+// MockSessionStorage is a mock of SessionStorage interface.
+type MockSessionStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionStorageMockRecorder
+}
+
+// MockSessionStorageMockRecorder is the mock recorder for MockSessionStorage.
+type MockSessionStorageMockRecorder struct {
+	mock *MockSessionStorage
+}
+
+// NewMockSessionStorage creates a new mock instance.
+func NewMockSessionStorage(ctrl *gomock.Controller) *MockSessionStorage {
+	mock := &MockSessionStorage{ctrl: ctrl}
+	mock.recorder = &MockSessionStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionStorage) EXPECT() *MockSessionStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *MockSessionStorage) CreateSession(ctx context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, userID, refreshHash, expiresAt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionStorageMockRecorder) CreateSession(ctx, userID, refreshHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionStorage)(nil).CreateSession), ctx, userID, refreshHash, expiresAt)
+}
+
+// RefreshSession mocks base method.
+func (m *MockSessionStorage) RefreshSession(ctx context.Context, refreshHash, newHash string, expiresAt time.Time) (*entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", ctx, refreshHash, newHash, expiresAt)
+	ret0, _ := ret[0].(*entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MockSessionStorageMockRecorder) RefreshSession(ctx, refreshHash, newHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MockSessionStorage)(nil).RefreshSession), ctx, refreshHash, newHash, expiresAt)
+}
+
+// RevokeSession mocks base method.
+func (m *MockSessionStorage) RevokeSession(ctx context.Context, sessionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockSessionStorageMockRecorder) RevokeSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockSessionStorage)(nil).RevokeSession), ctx, sessionID)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockSessionStorage) RevokeUserSessions(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockSessionStorageMockRecorder) RevokeUserSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockSessionStorage)(nil).RevokeUserSessions), ctx, userID)
+}
+
+// SessionActive mocks base method.
+func (m *MockSessionStorage) SessionActive(ctx context.Context, sessionID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionActive", ctx, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionActive indicates an expected call of SessionActive.
+func (mr *MockSessionStorageMockRecorder) SessionActive(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionActive", reflect.TypeOf((*MockSessionStorage)(nil).SessionActive), ctx, sessionID)
+}
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// AddEventImages mocks base method.
+func (m *MockStorage) AddEventImages(ctx context.Context, eventID int, images []entity.Image) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEventImages", ctx, eventID, images)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEventImages indicates an expected call of AddEventImages.
+func (mr *MockStorageMockRecorder) AddEventImages(ctx, eventID, images interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventImages", reflect.TypeOf((*MockStorage)(nil).AddEventImages), ctx, eventID, images)
+}
+
+// AddEventUser mocks base method.
+func (m *MockStorage) AddEventUser(ctx context.Context, tick *entity.Ticket) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEventUser", ctx, tick)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEventUser indicates an expected call of AddEventUser.
+func (mr *MockStorageMockRecorder) AddEventUser(ctx, tick interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventUser", reflect.TypeOf((*MockStorage)(nil).AddEventUser), ctx, tick)
+}
+
+// CalendarUser mocks base method.
+func (m *MockStorage) CalendarUser(ctx context.Context, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarUser", ctx, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarUser indicates an expected call of CalendarUser.
+func (mr *MockStorageMockRecorder) CalendarUser(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarUser", reflect.TypeOf((*MockStorage)(nil).CalendarUser), ctx, tokenHash)
+}
+
+// CancelOccurrence mocks base method.
+func (m *MockStorage) CancelOccurrence(ctx context.Context, seriesID, eventID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOccurrence", ctx, seriesID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOccurrence indicates an expected call of CancelOccurrence.
+func (mr *MockStorageMockRecorder) CancelOccurrence(ctx, seriesID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOccurrence", reflect.TypeOf((*MockStorage)(nil).CancelOccurrence), ctx, seriesID, eventID)
+}
+
+// ChangeNoticeSent mocks base method.
+func (m *MockStorage) ChangeNoticeSent(ctx context.Context, noticeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeNoticeSent", ctx, noticeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeNoticeSent indicates an expected call of ChangeNoticeSent.
+func (mr *MockStorageMockRecorder) ChangeNoticeSent(ctx, noticeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeNoticeSent", reflect.TypeOf((*MockStorage)(nil).ChangeNoticeSent), ctx, noticeID)
+}
+
+// CheckinCount mocks base method.
+func (m *MockStorage) CheckinCount(ctx context.Context, eventID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckinCount", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckinCount indicates an expected call of CheckinCount.
+func (mr *MockStorageMockRecorder) CheckinCount(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckinCount", reflect.TypeOf((*MockStorage)(nil).CheckinCount), ctx, eventID)
+}
+
+// ClaimMail mocks base method.
+func (m *MockStorage) ClaimMail(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMail", ctx, limit, lease)
+	ret0, _ := ret[0].([]entity.OutboxMail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMail indicates an expected call of ClaimMail.
+func (mr *MockStorageMockRecorder) ClaimMail(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMail", reflect.TypeOf((*MockStorage)(nil).ClaimMail), ctx, limit, lease)
+}
+
+// Close mocks base method.
+func (m *MockStorage) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockStorageMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStorage)(nil).Close))
+}
+
+// CloseEvent mocks base method.
+func (m *MockStorage) CloseEvent(ctx context.Context, userID, eventID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseEvent", ctx, userID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseEvent indicates an expected call of CloseEvent.
+func (mr *MockStorageMockRecorder) CloseEvent(ctx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseEvent", reflect.TypeOf((*MockStorage)(nil).CloseEvent), ctx, userID, eventID)
+}
+
+// CloseFinishedEvents mocks base method.
+func (m *MockStorage) CloseFinishedEvents(ctx context.Context, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseFinishedEvents", ctx, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseFinishedEvents indicates an expected call of CloseFinishedEvents.
+func (mr *MockStorageMockRecorder) CloseFinishedEvents(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseFinishedEvents", reflect.TypeOf((*MockStorage)(nil).CloseFinishedEvents), ctx, date)
+}
+
+// CollectImageGarbage mocks base method.
+func (m *MockStorage) CollectImageGarbage(ctx context.Context, uploadedBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectImageGarbage", ctx, uploadedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectImageGarbage indicates an expected call of CollectImageGarbage.
+func (mr *MockStorageMockRecorder) CollectImageGarbage(ctx, uploadedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectImageGarbage", reflect.TypeOf((*MockStorage)(nil).CollectImageGarbage), ctx, uploadedBefore)
+}
+
+// CreateEvent mocks base method.
+func (m *MockStorage) CreateEvent(ctx context.Context, e *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockStorageMockRecorder) CreateEvent(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockStorage)(nil).CreateEvent), ctx, e)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockStorage) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", ctx, userID, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockStorageMockRecorder) CreatePasswordReset(ctx, userID, tokenHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockStorage)(nil).CreatePasswordReset), ctx, userID, tokenHash, expiresAt)
+}
+
+// CreateSeries mocks base method.
+func (m *MockStorage) CreateSeries(ctx context.Context, series *entity.Series, until time.Time) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", ctx, series, until)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockStorageMockRecorder) CreateSeries(ctx, series, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockStorage)(nil).CreateSeries), ctx, series, until)
+}
+
+// CreateSession mocks base method.
+func (m *MockStorage) CreateSession(ctx context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, userID, refreshHash, expiresAt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStorageMockRecorder) CreateSession(ctx, userID, refreshHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStorage)(nil).CreateSession), ctx, userID, refreshHash, expiresAt)
+}
+
+// DellEvent mocks base method.
+func (m *MockStorage) DellEvent(ctx context.Context, userID, eventID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellEvent", ctx, userID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DellEvent indicates an expected call of DellEvent.
+func (mr *MockStorageMockRecorder) DellEvent(ctx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEvent", reflect.TypeOf((*MockStorage)(nil).DellEvent), ctx, userID, eventID)
+}
+
+// DellEventImage mocks base method.
+func (m *MockStorage) DellEventImage(ctx context.Context, eventID int, filename string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellEventImage", ctx, eventID, filename)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DellEventImage indicates an expected call of DellEventImage.
+func (mr *MockStorageMockRecorder) DellEventImage(ctx, eventID, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEventImage", reflect.TypeOf((*MockStorage)(nil).DellEventImage), ctx, eventID, filename)
+}
+
+// DellEventUser mocks base method.
+func (m *MockStorage) DellEventUser(ctx context.Context, eventID, userID int, gen storage.TicketGenerator) (*entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellEventUser", ctx, eventID, userID, gen)
+	ret0, _ := ret[0].(*entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DellEventUser indicates an expected call of DellEventUser.
+func (mr *MockStorageMockRecorder) DellEventUser(ctx, eventID, userID, gen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEventUser", reflect.TypeOf((*MockStorage)(nil).DellEventUser), ctx, eventID, userID, gen)
+}
+
+// DellUserReminders mocks base method.
+func (m *MockStorage) DellUserReminders(ctx context.Context, eventID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DellUserReminders", ctx, eventID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DellUserReminders indicates an expected call of DellUserReminders.
+func (mr *MockStorageMockRecorder) DellUserReminders(ctx, eventID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellUserReminders", reflect.TypeOf((*MockStorage)(nil).DellUserReminders), ctx, eventID, userID)
+}
+
+// DigestSent mocks base method.
+func (m *MockStorage) DigestSent(ctx context.Context, userID int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DigestSent", ctx, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DigestSent indicates an expected call of DigestSent.
+func (mr *MockStorageMockRecorder) DigestSent(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DigestSent", reflect.TypeOf((*MockStorage)(nil).DigestSent), ctx, userID, at)
+}
+
+// DueDigests mocks base method.
+func (m *MockStorage) DueDigests(ctx context.Context, now time.Time, every time.Duration) ([]entity.Digest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueDigests", ctx, now, every)
+	ret0, _ := ret[0].([]entity.Digest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueDigests indicates an expected call of DueDigests.
+func (mr *MockStorageMockRecorder) DueDigests(ctx, now, every interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueDigests", reflect.TypeOf((*MockStorage)(nil).DueDigests), ctx, now, every)
+}
+
+// DueReminders mocks base method.
+func (m *MockStorage) DueReminders(ctx context.Context, now time.Time, limit int) ([]entity.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueReminders", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueReminders indicates an expected call of DueReminders.
+func (mr *MockStorageMockRecorder) DueReminders(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueReminders", reflect.TypeOf((*MockStorage)(nil).DueReminders), ctx, now, limit)
+}
+
+// EnqueueMail mocks base method.
+func (m *MockStorage) EnqueueMail(ctx context.Context, mails []entity.OutboxMail) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueMail", ctx, mails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueMail indicates an expected call of EnqueueMail.
+func (mr *MockStorageMockRecorder) EnqueueMail(ctx, mails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueMail", reflect.TypeOf((*MockStorage)(nil).EnqueueMail), ctx, mails)
+}
+
+// EventReminders mocks base method.
+func (m *MockStorage) EventReminders(ctx context.Context, eventID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventReminders", ctx, eventID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventReminders indicates an expected call of EventReminders.
+func (mr *MockStorageMockRecorder) EventReminders(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventReminders", reflect.TypeOf((*MockStorage)(nil).EventReminders), ctx, eventID)
+}
+
+// EventTickets mocks base method.
+func (m *MockStorage) EventTickets(ctx context.Context, eventID int) ([]entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventTickets", ctx, eventID)
+	ret0, _ := ret[0].([]entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventTickets indicates an expected call of EventTickets.
+func (mr *MockStorageMockRecorder) EventTickets(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventTickets", reflect.TypeOf((*MockStorage)(nil).EventTickets), ctx, eventID)
+}
+
+// GetChangeNotices mocks base method.
+func (m *MockStorage) GetChangeNotices(ctx context.Context, limit int) ([]entity.ChangeNotice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeNotices", ctx, limit)
+	ret0, _ := ret[0].([]entity.ChangeNotice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeNotices indicates an expected call of GetChangeNotices.
+func (mr *MockStorageMockRecorder) GetChangeNotices(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeNotices", reflect.TypeOf((*MockStorage)(nil).GetChangeNotices), ctx, limit)
+}
+
+// GetDateEvent mocks base method.
+func (m *MockStorage) GetDateEvent(ctx context.Context, eventID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDateEvent", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDateEvent indicates an expected call of GetDateEvent.
+func (mr *MockStorageMockRecorder) GetDateEvent(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDateEvent", reflect.TypeOf((*MockStorage)(nil).GetDateEvent), ctx, eventID)
+}
+
+// GetEvent mocks base method.
+func (m *MockStorage) GetEvent(ctx context.Context, eventID int) (*entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
+	ret0, _ := ret[0].(*entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockStorageMockRecorder) GetEvent(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockStorage)(nil).GetEvent), ctx, eventID)
+}
+
+// GetEvents mocks base method.
+func (m *MockStorage) GetEvents(ctx context.Context, filter *entity.EventFilter) ([]entity.Event, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx, filter)
+	ret0, _ := ret[0].([]entity.Event)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockStorageMockRecorder) GetEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockStorage)(nil).GetEvents), ctx, filter)
+}
+
+// GetImage mocks base method.
+func (m *MockStorage) GetImage(ctx context.Context, filename string, expires time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImage", ctx, filename, expires)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImage indicates an expected call of GetImage.
+func (mr *MockStorageMockRecorder) GetImage(ctx, filename, expires interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockStorage)(nil).GetImage), ctx, filename, expires)
+}
+
+// GetLocale mocks base method.
+func (m *MockStorage) GetLocale(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocale", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocale indicates an expected call of GetLocale.
+func (mr *MockStorageMockRecorder) GetLocale(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocale", reflect.TypeOf((*MockStorage)(nil).GetLocale), ctx, userID)
+}
+
+// GetMail mocks base method.
+func (m *MockStorage) GetMail(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMail", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMail indicates an expected call of GetMail.
+func (mr *MockStorageMockRecorder) GetMail(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMail", reflect.TypeOf((*MockStorage)(nil).GetMail), ctx, userID)
+}
+
+// GetRegistrationNotices mocks base method.
+func (m *MockStorage) GetRegistrationNotices(ctx context.Context, limit int) ([]entity.RegistrationNotice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistrationNotices", ctx, limit)
+	ret0, _ := ret[0].([]entity.RegistrationNotice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistrationNotices indicates an expected call of GetRegistrationNotices.
+func (mr *MockStorageMockRecorder) GetRegistrationNotices(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistrationNotices", reflect.TypeOf((*MockStorage)(nil).GetRegistrationNotices), ctx, limit)
+}
+
+// GetSeries mocks base method.
+func (m *MockStorage) GetSeries(ctx context.Context, seriesID int) (*entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, seriesID)
+	ret0, _ := ret[0].(*entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockStorageMockRecorder) GetSeries(ctx, seriesID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockStorage)(nil).GetSeries), ctx, seriesID)
+}
+
+// GetTicket mocks base method.
+func (m *MockStorage) GetTicket(ctx context.Context, userID, eventID int) (*entity.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicket", ctx, userID, eventID)
+	ret0, _ := ret[0].(*entity.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicket indicates an expected call of GetTicket.
+func (mr *MockStorageMockRecorder) GetTicket(ctx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicket", reflect.TypeOf((*MockStorage)(nil).GetTicket), ctx, userID, eventID)
 }
 
-// SetUser mocks base method.
-func (m *MockUserStorage) SetUser(ctx context.Context, login, password, mail string) (int, error) {
+// GetUser mocks base method.
+func (m *MockStorage) GetUser(ctx context.Context, login string) (*entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetUser", ctx, login, password, mail)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "GetUser", ctx, login)
+	ret0, _ := ret[0].(*entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SetUser indicates an expected call of SetUser.
-func (mr *MockUserStorageMockRecorder) SetUser(ctx, login, password, mail interface{}) *gomock.Call {
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStorageMockRecorder) GetUser(ctx, login interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUser", reflect.TypeOf((*MockUserStorage)(nil).SetUser), ctx, login, password, mail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStorage)(nil).GetUser), ctx, login)
 }
 
-// UserTickets mocks base method.
-func (m *MockUserStorage) UserTickets(ctx context.Context, userID int) ([]entity.Ticket, error) {
+// GetUserEvents mocks base method.
+func (m *MockStorage) GetUserEvents(ctx context.Context, userID int) ([]entity.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UserTickets", ctx, userID)
-	ret0, _ := ret[0].([]entity.Ticket)
+	ret := m.ctrl.Call(m, "GetUserEvents", ctx, userID)
+	ret0, _ := ret[0].([]entity.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UserTickets indicates an expected call of UserTickets.
-func (mr *MockUserStorageMockRecorder) UserTickets(ctx, userID interface{}) *gomock.Call {
+// GetUserEvents indicates an expected call of GetUserEvents.
+func (mr *MockStorageMockRecorder) GetUserEvents(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserTickets", reflect.TypeOf((*MockUserStorage)(nil).UserTickets), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEvents", reflect.TypeOf((*MockStorage)(nil).GetUserEvents), ctx, userID)
 }
 
-// MockEventStorage is a mock of EventStorage interface.
-type MockEventStorage struct {
-	ctrl     *gomock.Controller
-	recorder *MockEventStorageMockRecorder
+// MailDead mocks base method.
+func (m *MockStorage) MailDead(ctx context.Context, mailID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailDead", ctx, mailID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockEventStorageMockRecorder is the mock recorder for MockEventStorage.
-type MockEventStorageMockRecorder struct {
-	mock *MockEventStorage
+// MailDead indicates an expected call of MailDead.
+func (mr *MockStorageMockRecorder) MailDead(ctx, mailID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailDead", reflect.TypeOf((*MockStorage)(nil).MailDead), ctx, mailID, reason)
 }
 
-// NewMockEventStorage creates a new mock instance.
-func NewMockEventStorage(ctrl *gomock.Controller) *MockEventStorage {
-	mock := &MockEventStorage{ctrl: ctrl}
-	mock.recorder = &MockEventStorageMockRecorder{mock}
-	return mock
+// MailRetry mocks base method.
+func (m *MockStorage) MailRetry(ctx context.Context, mailID int, reason string, next time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailRetry", ctx, mailID, reason, next)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockEventStorage) EXPECT() *MockEventStorageMockRecorder {
-	return m.recorder
+// MailRetry indicates an expected call of MailRetry.
+func (mr *MockStorageMockRecorder) MailRetry(ctx, mailID, reason, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailRetry", reflect.TypeOf((*MockStorage)(nil).MailRetry), ctx, mailID, reason, next)
 }
 
-// CloseEvent mocks base method.
-func (m *MockEventStorage) CloseEvent(ctx context.Context, userID, eventID int) error {
+// MailSent mocks base method.
+func (m *MockStorage) MailSent(ctx context.Context, mailID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseEvent", ctx, userID, eventID)
+	ret := m.ctrl.Call(m, "MailSent", ctx, mailID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CloseEvent indicates an expected call of CloseEvent.
-func (mr *MockEventStorageMockRecorder) CloseEvent(ctx, userID, eventID interface{}) *gomock.Call {
+// MailSent indicates an expected call of MailSent.
+func (mr *MockStorageMockRecorder) MailSent(ctx, mailID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseEvent", reflect.TypeOf((*MockEventStorage)(nil).CloseEvent), ctx, userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailSent", reflect.TypeOf((*MockStorage)(nil).MailSent), ctx, mailID)
 }
 
-// CreateEvent mocks base method.
-func (m *MockEventStorage) CreateEvent(ctx context.Context, e *entity.Event) error {
+// MailVerified mocks base method.
+func (m *MockStorage) MailVerified(ctx context.Context, userID int) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEvent", ctx, e)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MailVerified", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateEvent indicates an expected call of CreateEvent.
-func (mr *MockEventStorageMockRecorder) CreateEvent(ctx, e interface{}) *gomock.Call {
+// MailVerified indicates an expected call of MailVerified.
+func (mr *MockStorageMockRecorder) MailVerified(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockEventStorage)(nil).CreateEvent), ctx, e)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailVerified", reflect.TypeOf((*MockStorage)(nil).MailVerified), ctx, userID)
 }
 
-// DellEvent mocks base method.
-func (m *MockEventStorage) DellEvent(ctx context.Context, userID, eventID int) error {
+// MaterializeSeries mocks base method.
+func (m *MockStorage) MaterializeSeries(ctx context.Context, until time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DellEvent", ctx, userID, eventID)
+	ret := m.ctrl.Call(m, "MaterializeSeries", ctx, until)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DellEvent indicates an expected call of DellEvent.
-func (mr *MockEventStorageMockRecorder) DellEvent(ctx, userID, eventID interface{}) *gomock.Call {
+// MaterializeSeries indicates an expected call of MaterializeSeries.
+func (mr *MockStorageMockRecorder) MaterializeSeries(ctx, until interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEvent", reflect.TypeOf((*MockEventStorage)(nil).DellEvent), ctx, userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeSeries", reflect.TypeOf((*MockStorage)(nil).MaterializeSeries), ctx, until)
 }
 
-// GetDateEvent mocks base method.
-func (m *MockEventStorage) GetDateEvent(ctx context.Context, eventID int) (int, error) {
+// MergeCheckins mocks base method.
+func (m *MockStorage) MergeCheckins(ctx context.Context, eventID, scannerID int, checkins []entity.Checkin) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDateEvent", ctx, eventID)
+	ret := m.ctrl.Call(m, "MergeCheckins", ctx, eventID, scannerID, checkins)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetDateEvent indicates an expected call of GetDateEvent.
-func (mr *MockEventStorageMockRecorder) GetDateEvent(ctx, eventID interface{}) *gomock.Call {
+// MergeCheckins indicates an expected call of MergeCheckins.
+func (mr *MockStorageMockRecorder) MergeCheckins(ctx, eventID, scannerID, checkins interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDateEvent", reflect.TypeOf((*MockEventStorage)(nil).GetDateEvent), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCheckins", reflect.TypeOf((*MockStorage)(nil).MergeCheckins), ctx, eventID, scannerID, checkins)
 }
 
-// GetEvent mocks base method.
-func (m *MockEventStorage) GetEvent(ctx context.Context, eventID int) (*entity.Event, error) {
+// NextReminder mocks base method.
+func (m *MockStorage) NextReminder(ctx context.Context, now time.Time) (time.Time, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
-	ret0, _ := ret[0].(*entity.Event)
+	ret := m.ctrl.Call(m, "NextReminder", ctx, now)
+	ret0, _ := ret[0].(time.Time)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEvent indicates an expected call of GetEvent.
-func (mr *MockEventStorageMockRecorder) GetEvent(ctx, eventID interface{}) *gomock.Call {
+// NextReminder indicates an expected call of NextReminder.
+func (mr *MockStorageMockRecorder) NextReminder(ctx, now interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockEventStorage)(nil).GetEvent), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextReminder", reflect.TypeOf((*MockStorage)(nil).NextReminder), ctx, now)
 }
 
-// GetEvents mocks base method.
-func (m *MockEventStorage) GetEvents(ctx context.Context, from, to time.Time, limit, page int) ([]entity.Event, int, error) {
+// OpenImage mocks base method.
+func (m *MockStorage) OpenImage(ctx context.Context, filename string) (io.ReadSeekCloser, *ostorage.Object, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvents", ctx, from, to, limit, page)
-	ret0, _ := ret[0].([]entity.Event)
+	ret := m.ctrl.Call(m, "OpenImage", ctx, filename)
+	ret0, _ := ret[0].(io.ReadSeekCloser)
+	ret1, _ := ret[1].(*ostorage.Object)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenImage indicates an expected call of OpenImage.
+func (mr *MockStorageMockRecorder) OpenImage(ctx, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenImage", reflect.TypeOf((*MockStorage)(nil).OpenImage), ctx, filename)
+}
+
+// OutboxMails mocks base method.
+func (m *MockStorage) OutboxMails(ctx context.Context, status string, limit, offset int) ([]entity.OutboxMail, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutboxMails", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]entity.OutboxMail)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetEvents indicates an expected call of GetEvents.
-func (mr *MockEventStorageMockRecorder) GetEvents(ctx, from, to, limit, page interface{}) *gomock.Call {
+// OutboxMails indicates an expected call of OutboxMails.
+func (mr *MockStorageMockRecorder) OutboxMails(ctx, status, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockEventStorage)(nil).GetEvents), ctx, from, to, limit, page)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboxMails", reflect.TypeOf((*MockStorage)(nil).OutboxMails), ctx, status, limit, offset)
 }
 
-// GetImage mocks base method.
-func (m *MockEventStorage) GetImage(ctx context.Context, filename string) (string, error) {
+// RedeemTicket mocks base method.
+func (m *MockStorage) RedeemTicket(ctx context.Context, token string, scannerID int) (*entity.Ticket, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetImage", ctx, filename)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "RedeemTicket", ctx, token, scannerID)
+	ret0, _ := ret[0].(*entity.Ticket)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetImage indicates an expected call of GetImage.
-func (mr *MockEventStorageMockRecorder) GetImage(ctx, filename interface{}) *gomock.Call {
+// RedeemTicket indicates an expected call of RedeemTicket.
+func (mr *MockStorageMockRecorder) RedeemTicket(ctx, token, scannerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockEventStorage)(nil).GetImage), ctx, filename)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemTicket", reflect.TypeOf((*MockStorage)(nil).RedeemTicket), ctx, token, scannerID)
 }
 
-// MockNotificationStorage is a mock of NotificationStorage interface.
-type MockNotificationStorage struct {
-	ctrl     *gomock.Controller
-	recorder *MockNotificationStorageMockRecorder
+// RefreshSession mocks base method.
+func (m *MockStorage) RefreshSession(ctx context.Context, refreshHash, newHash string, expiresAt time.Time) (*entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", ctx, refreshHash, newHash, expiresAt)
+	ret0, _ := ret[0].(*entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockNotificationStorageMockRecorder is the mock recorder for MockNotificationStorage.
-type MockNotificationStorageMockRecorder struct {
-	mock *MockNotificationStorage
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MockStorageMockRecorder) RefreshSession(ctx, refreshHash, newHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MockStorage)(nil).RefreshSession), ctx, refreshHash, newHash, expiresAt)
 }
 
-// NewMockNotificationStorage creates a new mock instance.
-func NewMockNotificationStorage(ctrl *gomock.Controller) *MockNotificationStorage {
-	mock := &MockNotificationStorage{ctrl: ctrl}
-	mock.recorder = &MockNotificationStorageMockRecorder{mock}
-	return mock
+// RegistrationNoticeSent mocks base method.
+func (m *MockStorage) RegistrationNoticeSent(ctx context.Context, noticeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistrationNoticeSent", ctx, noticeID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockNotificationStorage) EXPECT() *MockNotificationStorageMockRecorder {
-	return m.recorder
+// RegistrationNoticeSent indicates an expected call of RegistrationNoticeSent.
+func (mr *MockStorageMockRecorder) RegistrationNoticeSent(ctx, noticeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistrationNoticeSent", reflect.TypeOf((*MockStorage)(nil).RegistrationNoticeSent), ctx, noticeID)
 }
 
-// EventsToday mocks base method.
-func (m *MockNotificationStorage) EventsToday(ctx context.Context, date time.Time) error {
+// ReminderSent mocks base method.
+func (m *MockStorage) ReminderSent(ctx context.Context, eventID, userID int, minutes []int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EventsToday", ctx, date)
+	ret := m.ctrl.Call(m, "ReminderSent", ctx, eventID, userID, minutes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// EventsToday indicates an expected call of EventsToday.
-func (mr *MockNotificationStorageMockRecorder) EventsToday(ctx, date interface{}) *gomock.Call {
+// ReminderSent indicates an expected call of ReminderSent.
+func (mr *MockStorageMockRecorder) ReminderSent(ctx, eventID, userID, minutes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsToday", reflect.TypeOf((*MockNotificationStorage)(nil).EventsToday), ctx, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReminderSent", reflect.TypeOf((*MockStorage)(nil).ReminderSent), ctx, eventID, userID, minutes)
 }
 
-// GetMessages mocks base method.
-func (m *MockNotificationStorage) GetMessages(ctx context.Context, date time.Time) ([]entity.Message, error) {
+// ReorderEventImages mocks base method.
+func (m *MockStorage) ReorderEventImages(ctx context.Context, eventID int, filenames []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", ctx, date)
-	ret0, _ := ret[0].([]entity.Message)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ReorderEventImages", ctx, eventID, filenames)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetMessages indicates an expected call of GetMessages.
-func (mr *MockNotificationStorageMockRecorder) GetMessages(ctx, date interface{}) *gomock.Call {
+// ReorderEventImages indicates an expected call of ReorderEventImages.
+func (mr *MockStorageMockRecorder) ReorderEventImages(ctx, eventID, filenames interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockNotificationStorage)(nil).GetMessages), ctx, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderEventImages", reflect.TypeOf((*MockStorage)(nil).ReorderEventImages), ctx, eventID, filenames)
 }
 
-// MessageUpdate mocks base method.
-func (m *MockNotificationStorage) MessageUpdate(ctx context.Context, eventID, userID int) error {
+// ReplayMail mocks base method.
+func (m *MockStorage) ReplayMail(ctx context.Context, mailID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MessageUpdate", ctx, eventID, userID)
+	ret := m.ctrl.Call(m, "ReplayMail", ctx, mailID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MessageUpdate indicates an expected call of MessageUpdate.
-func (mr *MockNotificationStorageMockRecorder) MessageUpdate(ctx, eventID, userID interface{}) *gomock.Call {
+// ReplayMail indicates an expected call of ReplayMail.
+func (mr *MockStorageMockRecorder) ReplayMail(ctx, mailID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageUpdate", reflect.TypeOf((*MockNotificationStorage)(nil).MessageUpdate), ctx, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayMail", reflect.TypeOf((*MockStorage)(nil).ReplayMail), ctx, mailID)
 }
 
-// MockStorage is a mock of Storage interface.
-type MockStorage struct {
-	ctrl     *gomock.Controller
-	recorder *MockStorageMockRecorder
+// ResetPassword mocks base method.
+func (m *MockStorage) ResetPassword(ctx context.Context, tokenHash, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, password)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockStorageMockRecorder is the mock recorder for MockStorage.
-type MockStorageMockRecorder struct {
-	mock *MockStorage
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockStorageMockRecorder) ResetPassword(ctx, tokenHash, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockStorage)(nil).ResetPassword), ctx, tokenHash, password)
 }
 
-// NewMockStorage creates a new mock instance.
-func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
-	mock := &MockStorage{ctrl: ctrl}
-	mock.recorder = &MockStorageMockRecorder{mock}
-	return mock
+// RevokeSession mocks base method.
+func (m *MockStorage) RevokeSession(ctx context.Context, sessionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
-	return m.recorder
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockStorageMockRecorder) RevokeSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockStorage)(nil).RevokeSession), ctx, sessionID)
 }
 
-// AddEventUser mocks base method.
-func (m *MockStorage) AddEventUser(ctx context.Context, tick *entity.Ticket) error {
+// RevokeUserSessions mocks base method.
+func (m *MockStorage) RevokeUserSessions(ctx context.Context, userID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddEventUser", ctx, tick)
+	ret := m.ctrl.Call(m, "RevokeUserSessions", ctx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddEventUser indicates an expected call of AddEventUser.
-func (mr *MockStorageMockRecorder) AddEventUser(ctx, tick interface{}) *gomock.Call {
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockStorageMockRecorder) RevokeUserSessions(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventUser", reflect.TypeOf((*MockStorage)(nil).AddEventUser), ctx, tick)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockStorage)(nil).RevokeUserSessions), ctx, userID)
 }
 
-// CloseEvent mocks base method.
-func (m *MockStorage) CloseEvent(ctx context.Context, userID, eventID int) error {
+// RevokedTickets mocks base method.
+func (m *MockStorage) RevokedTickets(ctx context.Context, eventID int) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseEvent", ctx, userID, eventID)
+	ret := m.ctrl.Call(m, "RevokedTickets", ctx, eventID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokedTickets indicates an expected call of RevokedTickets.
+func (mr *MockStorageMockRecorder) RevokedTickets(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokedTickets", reflect.TypeOf((*MockStorage)(nil).RevokedTickets), ctx, eventID)
+}
+
+// SessionActive mocks base method.
+func (m *MockStorage) SessionActive(ctx context.Context, sessionID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionActive", ctx, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionActive indicates an expected call of SessionActive.
+func (mr *MockStorageMockRecorder) SessionActive(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionActive", reflect.TypeOf((*MockStorage)(nil).SessionActive), ctx, sessionID)
+}
+
+// SetCalendarToken mocks base method.
+func (m *MockStorage) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCalendarToken", ctx, userID, tokenHash)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CloseEvent indicates an expected call of CloseEvent.
-func (mr *MockStorageMockRecorder) CloseEvent(ctx, userID, eventID interface{}) *gomock.Call {
+// SetCalendarToken indicates an expected call of SetCalendarToken.
+func (mr *MockStorageMockRecorder) SetCalendarToken(ctx, userID, tokenHash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseEvent", reflect.TypeOf((*MockStorage)(nil).CloseEvent), ctx, userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCalendarToken", reflect.TypeOf((*MockStorage)(nil).SetCalendarToken), ctx, userID, tokenHash)
 }
 
-// CreateEvent mocks base method.
-func (m *MockStorage) CreateEvent(ctx context.Context, e *entity.Event) error {
+// SetEventCover mocks base method.
+func (m *MockStorage) SetEventCover(ctx context.Context, eventID int, filename string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEvent", ctx, e)
+	ret := m.ctrl.Call(m, "SetEventCover", ctx, eventID, filename)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateEvent indicates an expected call of CreateEvent.
-func (mr *MockStorageMockRecorder) CreateEvent(ctx, e interface{}) *gomock.Call {
+// SetEventCover indicates an expected call of SetEventCover.
+func (mr *MockStorageMockRecorder) SetEventCover(ctx, eventID, filename interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockStorage)(nil).CreateEvent), ctx, e)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventCover", reflect.TypeOf((*MockStorage)(nil).SetEventCover), ctx, eventID, filename)
 }
 
-// DellEvent mocks base method.
-func (m *MockStorage) DellEvent(ctx context.Context, userID, eventID int) error {
+// SetEventReminders mocks base method.
+func (m *MockStorage) SetEventReminders(ctx context.Context, eventID int, minutes []int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DellEvent", ctx, userID, eventID)
+	ret := m.ctrl.Call(m, "SetEventReminders", ctx, eventID, minutes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DellEvent indicates an expected call of DellEvent.
-func (mr *MockStorageMockRecorder) DellEvent(ctx, userID, eventID interface{}) *gomock.Call {
+// SetEventReminders indicates an expected call of SetEventReminders.
+func (mr *MockStorageMockRecorder) SetEventReminders(ctx, eventID, minutes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEvent", reflect.TypeOf((*MockStorage)(nil).DellEvent), ctx, userID, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventReminders", reflect.TypeOf((*MockStorage)(nil).SetEventReminders), ctx, eventID, minutes)
 }
 
-// DellEventUser mocks base method.
-func (m *MockStorage) DellEventUser(ctx context.Context, eventID, userID int) error {
+// SetLocale mocks base method.
+func (m *MockStorage) SetLocale(ctx context.Context, userID int, locale string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DellEventUser", ctx, eventID, userID)
+	ret := m.ctrl.Call(m, "SetLocale", ctx, userID, locale)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DellEventUser indicates an expected call of DellEventUser.
-func (mr *MockStorageMockRecorder) DellEventUser(ctx, eventID, userID interface{}) *gomock.Call {
+// SetLocale indicates an expected call of SetLocale.
+func (mr *MockStorageMockRecorder) SetLocale(ctx, userID, locale interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DellEventUser", reflect.TypeOf((*MockStorage)(nil).DellEventUser), ctx, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocale", reflect.TypeOf((*MockStorage)(nil).SetLocale), ctx, userID, locale)
 }
 
-// EventsToday mocks base method.
-func (m *MockStorage) EventsToday(ctx context.Context, date time.Time) error {
+// SetRole mocks base method.
+func (m *MockStorage) SetRole(ctx context.Context, login, role string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EventsToday", ctx, date)
+	ret := m.ctrl.Call(m, "SetRole", ctx, login, role)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// EventsToday indicates an expected call of EventsToday.
-func (mr *MockStorageMockRecorder) EventsToday(ctx, date interface{}) *gomock.Call {
+// SetRole indicates an expected call of SetRole.
+func (mr *MockStorageMockRecorder) SetRole(ctx, login, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsToday", reflect.TypeOf((*MockStorage)(nil).EventsToday), ctx, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockStorage)(nil).SetRole), ctx, login, role)
 }
 
-// GetDateEvent mocks base method.
-func (m *MockStorage) GetDateEvent(ctx context.Context, eventID int) (int, error) {
+// SetSignupDigest mocks base method.
+func (m *MockStorage) SetSignupDigest(ctx context.Context, userID int, enabled bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDateEvent", ctx, eventID)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetSignupDigest", ctx, userID, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetDateEvent indicates an expected call of GetDateEvent.
-func (mr *MockStorageMockRecorder) GetDateEvent(ctx, eventID interface{}) *gomock.Call {
+// SetSignupDigest indicates an expected call of SetSignupDigest.
+func (mr *MockStorageMockRecorder) SetSignupDigest(ctx, userID, enabled interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDateEvent", reflect.TypeOf((*MockStorage)(nil).GetDateEvent), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSignupDigest", reflect.TypeOf((*MockStorage)(nil).SetSignupDigest), ctx, userID, enabled)
 }
 
-// GetEvent mocks base method.
-func (m *MockStorage) GetEvent(ctx context.Context, eventID int) (*entity.Event, error) {
+// SetUser mocks base method.
+func (m *MockStorage) SetUser(ctx context.Context, login, password, mail, role string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
-	ret0, _ := ret[0].(*entity.Event)
+	ret := m.ctrl.Call(m, "SetUser", ctx, login, password, mail, role)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEvent indicates an expected call of GetEvent.
-func (mr *MockStorageMockRecorder) GetEvent(ctx, eventID interface{}) *gomock.Call {
+// SetUser indicates an expected call of SetUser.
+func (mr *MockStorageMockRecorder) SetUser(ctx, login, password, mail, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockStorage)(nil).GetEvent), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUser", reflect.TypeOf((*MockStorage)(nil).SetUser), ctx, login, password, mail, role)
 }
 
-// GetEvents mocks base method.
-func (m *MockStorage) GetEvents(ctx context.Context, from, to time.Time, limit, page int) ([]entity.Event, int, error) {
+// SetUserChannels mocks base method.
+func (m *MockStorage) SetUserChannels(ctx context.Context, userID int, channels []entity.UserChannel) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvents", ctx, from, to, limit, page)
-	ret0, _ := ret[0].([]entity.Event)
-	ret1, _ := ret[1].(int)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "SetUserChannels", ctx, userID, channels)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetEvents indicates an expected call of GetEvents.
-func (mr *MockStorageMockRecorder) GetEvents(ctx, from, to, limit, page interface{}) *gomock.Call {
+// SetUserChannels indicates an expected call of SetUserChannels.
+func (mr *MockStorageMockRecorder) SetUserChannels(ctx, userID, channels interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockStorage)(nil).GetEvents), ctx, from, to, limit, page)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserChannels", reflect.TypeOf((*MockStorage)(nil).SetUserChannels), ctx, userID, channels)
 }
 
-// GetImage mocks base method.
-func (m *MockStorage) GetImage(ctx context.Context, filename string) (string, error) {
+// SetUserReminders mocks base method.
+func (m *MockStorage) SetUserReminders(ctx context.Context, eventID, userID int, minutes []int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetImage", ctx, filename)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetUserReminders", ctx, eventID, userID, minutes)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetImage indicates an expected call of GetImage.
-func (mr *MockStorageMockRecorder) GetImage(ctx, filename interface{}) *gomock.Call {
+// SetUserReminders indicates an expected call of SetUserReminders.
+func (mr *MockStorageMockRecorder) SetUserReminders(ctx, eventID, userID, minutes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockStorage)(nil).GetImage), ctx, filename)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserReminders", reflect.TypeOf((*MockStorage)(nil).SetUserReminders), ctx, eventID, userID, minutes)
 }
 
-// GetMessages mocks base method.
-func (m *MockStorage) GetMessages(ctx context.Context, date time.Time) ([]entity.Message, error) {
+// SplitSeries mocks base method.
+func (m *MockStorage) SplitSeries(ctx context.Context, userID, seriesID int, from time.Time, rule string, next *entity.Series, changes []entity.EventChange, shift time.Duration, gen storage.TicketGenerator) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", ctx, date)
-	ret0, _ := ret[0].([]entity.Message)
+	ret := m.ctrl.Call(m, "SplitSeries", ctx, userID, seriesID, from, rule, next, changes, shift, gen)
+	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMessages indicates an expected call of GetMessages.
-func (mr *MockStorageMockRecorder) GetMessages(ctx, date interface{}) *gomock.Call {
+// SplitSeries indicates an expected call of SplitSeries.
+func (mr *MockStorageMockRecorder) SplitSeries(ctx, userID, seriesID, from, rule, next, changes, shift, gen interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockStorage)(nil).GetMessages), ctx, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeries", reflect.TypeOf((*MockStorage)(nil).SplitSeries), ctx, userID, seriesID, from, rule, next, changes, shift, gen)
 }
 
-// GetUser mocks base method.
-func (m *MockStorage) GetUser(ctx context.Context, login, password string) (int, error) {
+// TruncateSeries mocks base method.
+func (m *MockStorage) TruncateSeries(ctx context.Context, seriesID int, from time.Time, rule string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, login, password)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "TruncateSeries", ctx, seriesID, from, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStorageMockRecorder) GetUser(ctx, login, password interface{}) *gomock.Call {
+// TruncateSeries indicates an expected call of TruncateSeries.
+func (mr *MockStorageMockRecorder) TruncateSeries(ctx, seriesID, from, rule interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStorage)(nil).GetUser), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TruncateSeries", reflect.TypeOf((*MockStorage)(nil).TruncateSeries), ctx, seriesID, from, rule)
 }
 
-// GetUserEvents mocks base method.
-func (m *MockStorage) GetUserEvents(ctx context.Context, userID int) ([]entity.Event, error) {
+// UpdateEvent mocks base method.
+func (m *MockStorage) UpdateEvent(ctx context.Context, userID int, e *entity.Event, changes []entity.EventChange, gen storage.TicketGenerator) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserEvents", ctx, userID)
-	ret0, _ := ret[0].([]entity.Event)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, userID, e, changes, gen)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetUserEvents indicates an expected call of GetUserEvents.
-func (mr *MockStorageMockRecorder) GetUserEvents(ctx, userID interface{}) *gomock.Call {
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockStorageMockRecorder) UpdateEvent(ctx, userID, e, changes, gen interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEvents", reflect.TypeOf((*MockStorage)(nil).GetUserEvents), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockStorage)(nil).UpdateEvent), ctx, userID, e, changes, gen)
 }
 
-// MessageUpdate mocks base method.
-func (m *MockStorage) MessageUpdate(ctx context.Context, eventID, userID int) error {
+// UpdatePassword mocks base method.
+func (m *MockStorage) UpdatePassword(ctx context.Context, userID int, password string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MessageUpdate", ctx, eventID, userID)
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, password)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MessageUpdate indicates an expected call of MessageUpdate.
-func (mr *MockStorageMockRecorder) MessageUpdate(ctx, eventID, userID interface{}) *gomock.Call {
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockStorageMockRecorder) UpdatePassword(ctx, userID, password interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageUpdate", reflect.TypeOf((*MockStorage)(nil).MessageUpdate), ctx, eventID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockStorage)(nil).UpdatePassword), ctx, userID, password)
 }
 
-// SetUser mocks base method.
-func (m *MockStorage) SetUser(ctx context.Context, login, password, mail string) (int, error) {
+// UserChannels mocks base method.
+func (m *MockStorage) UserChannels(ctx context.Context, userID int) ([]entity.UserChannel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetUser", ctx, login, password, mail)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "UserChannels", ctx, userID)
+	ret0, _ := ret[0].([]entity.UserChannel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SetUser indicates an expected call of SetUser.
-func (mr *MockStorageMockRecorder) SetUser(ctx, login, password, mail interface{}) *gomock.Call {
+// UserChannels indicates an expected call of UserChannels.
+func (mr *MockStorageMockRecorder) UserChannels(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUser", reflect.TypeOf((*MockStorage)(nil).SetUser), ctx, login, password, mail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserChannels", reflect.TypeOf((*MockStorage)(nil).UserChannels), ctx, userID)
+}
+
+// UserReminders mocks base method.
+func (m *MockStorage) UserReminders(ctx context.Context, eventID, userID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserReminders", ctx, eventID, userID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserReminders indicates an expected call of UserReminders.
+func (mr *MockStorageMockRecorder) UserReminders(ctx, eventID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserReminders", reflect.TypeOf((*MockStorage)(nil).UserReminders), ctx, eventID, userID)
 }
 
 // UserTickets mocks base method.
@@ -572,3 +2293,17 @@ func (mr *MockStorageMockRecorder) UserTickets(ctx, userID interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserTickets", reflect.TypeOf((*MockStorage)(nil).UserTickets), ctx, userID)
 }
+
+// VerifyMail mocks base method.
+func (m *MockStorage) VerifyMail(ctx context.Context, userID int, mail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMail", ctx, userID, mail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyMail indicates an expected call of VerifyMail.
+func (mr *MockStorageMockRecorder) VerifyMail(ctx, userID, mail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMail", reflect.TypeOf((*MockStorage)(nil).VerifyMail), ctx, userID, mail)
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"time"
+)
+
+// EnqueueMail adds notifications to the outbox. One whose key is already
+// queued for its channel is skipped.
+func (s *storageData) EnqueueMail(ctx context.Context, mails []entity.OutboxMail) error {
+	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, mail := range mails {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO outbox (key, user_id, event_id, channel, address, subject, body, text, token, calendar)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				ON CONFLICT (key, channel) DO NOTHING
+			`, mail.Key, mail.UserID, mail.EventID, mail.Channel, mail.Address, mail.Subject, mail.Body, mail.Text, mail.Token, mail.Calendar)
+			if err != nil {
+				return fmt.Errorf("cannot INSERT outbox: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("cannot enqueue mail: %w", err)
+	}
+
+	return nil
+}
+
+func scanOutbox(rows *sql.Rows) ([]entity.OutboxMail, error) {
+	var mails []entity.OutboxMail
+	for rows.Next() {
+		var mail entity.OutboxMail
+		err := rows.Scan(
+			&mail.ID,
+			&mail.Key,
+			&mail.UserID,
+			&mail.EventID,
+			&mail.Channel,
+			&mail.Address,
+			&mail.Subject,
+			&mail.Body,
+			&mail.Text,
+			&mail.Token,
+			&mail.Calendar,
+			&mail.Status,
+			&mail.Attempts,
+			&mail.NextAttemptAt,
+			&mail.LastError,
+			&mail.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		mails = append(mails, mail)
+	}
+
+	return mails, nil
+}
+
+// ClaimMail takes up to limit pending emails that are due and hides them
+// from other senders for lease. An email whose sender dies comes back after
+// the lease.
+func (s *storageData) ClaimMail(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMail, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE outbox
+			SET next_attempt_at = now() + $2 * interval '1 second'
+			WHERE id IN (
+				SELECT id FROM outbox
+				WHERE status = 'pending' AND next_attempt_at <= now()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, key, user_id, event_id, channel, address, subject, body, text, token, calendar, status, attempts, next_attempt_at, last_error, created_at
+	`, limit, lease.Seconds())
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot claim mail: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutbox(rows)
+}
+
+func (s *storageData) MailSent(ctx context.Context, mailID int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox
+			SET status = 'sent', attempts = attempts + 1, last_error = ''
+			WHERE id = $1
+	`, mailID)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE outbox: %w", err)
+	}
+
+	return nil
+}
+
+// MailRetry records a failed attempt and schedules the next one at next.
+func (s *storageData) MailRetry(ctx context.Context, mailID int, reason string, next time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox
+			SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+			WHERE id = $1
+	`, mailID, reason, next)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE outbox: %w", err)
+	}
+
+	return nil
+}
+
+// MailDead records the last failed attempt, the email is not sent again until
+// it is replayed.
+func (s *storageData) MailDead(ctx context.Context, mailID int, reason string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox
+			SET status = 'dead', attempts = attempts + 1, last_error = $2
+			WHERE id = $1
+	`, mailID, reason)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE outbox: %w", err)
+	}
+
+	return nil
+}
+
+// OutboxMails returns a page of emails in status, newest first, and how many
+// emails are in status.
+func (s *storageData) OutboxMails(ctx context.Context, status string, limit, offset int) ([]entity.OutboxMail, int, error) {
+	var total int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM outbox WHERE status = $1
+	`, status).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot count outbox: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, key, user_id, event_id, channel, address, subject, body, text, token, calendar, status, attempts, next_attempt_at, last_error, created_at
+		FROM outbox
+		WHERE status = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+	if err != nil || rows.Err() != nil {
+		return nil, 0, fmt.Errorf("cannot get outbox: %w", err)
+	}
+	defer rows.Close()
+
+	mails, err := scanOutbox(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return mails, total, nil
+}
+
+// ReplayMail returns a dead email to the queue with a fresh attempt count.
+// A missing email is a NotFoundError, one that is not dead a ConflictError.
+func (s *storageData) ReplayMail(ctx context.Context, mailID int) error {
+	return s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var status string
+		err := tx.QueryRowContext(ctx, `
+			SELECT status FROM outbox WHERE id = $1 FOR UPDATE
+		`, mailID).Scan(&status)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: errors.New("mail not exist")}
+			}
+			return fmt.Errorf("cannot SELECT outbox: %w", err)
+		}
+
+		if status != entity.OutboxDead {
+			return &ConflictError{Err: fmt.Errorf("mail %d is %s", mailID, status)}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE outbox
+				SET status = 'pending', attempts = 0, next_attempt_at = now()
+				WHERE id = $1
+		`, mailID)
+		if err != nil {
+			return fmt.Errorf("cannot UPDATE outbox: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	"context"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"graduation/internal/storage/storagetest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pressly/goose"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgres runs the conformance suite against the database of
+// TEST_DATABASE_DSN. Every table of the database is emptied before each
+// test, so it refuses a database whose name does not end in _test.
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := storage.Connection(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	var name string
+	require.NoError(t, db.QueryRow(`SELECT current_database()`).Scan(&name))
+	if !strings.HasSuffix(name, "_test") {
+		t.Fatalf("database %q would be emptied, its name must end in _test", name)
+	}
+
+	err = goose.Up(db, "../migration")
+	if err != goose.ErrNoNextVersion {
+		require.NoError(t, err)
+	}
+
+	storagetest.Run(t, func(t *testing.T, ost ostorage.BlobStore) storage.Storage {
+		ctx := context.Background()
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT tablename FROM pg_tables
+			WHERE schemaname = current_schema() AND tablename <> 'goose_db_version'
+		`)
+		require.NoError(t, err)
+		var tables []string
+		for rows.Next() {
+			var table string
+			require.NoError(t, rows.Scan(&table))
+			tables = append(tables, table)
+		}
+		require.NoError(t, rows.Err())
+		rows.Close()
+
+		_, err = db.ExecContext(ctx, `TRUNCATE `+strings.Join(tables, ", ")+` RESTART IDENTITY CASCADE`)
+		require.NoError(t, err)
+
+		// the suite closes every storage, the connections are shared
+		other, err := storage.Connection(dsn)
+		require.NoError(t, err)
+
+		return storage.NewTestStorage(other, ost)
+	})
+}
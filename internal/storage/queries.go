@@ -3,17 +3,11 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"graduation/internal/entity"
-	"time"
 )
 
-type EventUsers struct {
-	ID     int
-	Date   time.Time
-	UserID []int
-}
-
 func (s *storageData) inTransaction(ctx context.Context, f func(ctx context.Context, tx *sql.Tx) error) error {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -21,6 +15,9 @@ func (s *storageData) inTransaction(ctx context.Context, f func(ctx context.Cont
 	}
 
 	if err := f(ctx, tx); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return fmt.Errorf("transaction Rollback failed: %w", err)
+		}
 		return fmt.Errorf("transaction: %w", err)
 	}
 
@@ -58,23 +55,19 @@ func (s *storageData) UserTickets(ctx context.Context, userID int) ([]entity.Tic
 	return tickets, nil
 }
 
-func (s *storageData) GetImage(ctx context.Context, filename string) (string, error) {
-	url, err := s.ost.Get(filename)
-	if err != nil {
-		return "", fmt.Errorf("photo not found: %w", err)
-	}
-	return url, nil
-}
-
-func (s *storageData) MessageUpdate(ctx context.Context, eventID, userID int) error {
-	_, err := s.db.ExecContext(ctx, `
-			UPDATE today
-			SET send = true
-			WHERE event_id = $1 AND user_id = $2
-		`, eventID, userID)
+func (s *storageData) GetTicket(ctx context.Context, userID, eventID int) (*entity.Ticket, error) {
+	ticket := &entity.Ticket{UserID: userID, EventID: eventID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT token, active
+		FROM ticket
+		WHERE user_id = $1 AND event_id = $2
+	`, userID, eventID).Scan(&ticket.Token, &ticket.Status)
 	if err != nil {
-		return fmt.Errorf("cannot update today: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Err: errors.New("ticket not exist")}
+		}
+		return nil, fmt.Errorf("cannot get ticket: %w", err)
 	}
 
-	return nil
+	return ticket, nil
 }
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graduation/internal/entity"
+	"time"
+)
+
+// addNotice queues a registration email in the transaction that changed the
+// registration.
+func addNotice(ctx context.Context, tx *sql.Tx, eventID, userID int, kind string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO registration_notice (event_id, user_id, kind)
+		VALUES ($1, $2, $3)
+	`, eventID, userID, kind)
+	if err != nil {
+		return fmt.Errorf("cannot INSERT registration notice: %w", err)
+	}
+
+	return nil
+}
+
+func (s *storageData) GetRegistrationNotices(ctx context.Context, limit int) ([]entity.RegistrationNotice, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT registration_notice.id, registration_notice.event_id, registration_notice.user_id, registration_notice.kind,
+			users.mail, users.locale, COALESCE(ticket.token, '')
+		FROM registration_notice
+		JOIN users ON users.id = registration_notice.user_id
+		LEFT JOIN ticket ON ticket.event_id = registration_notice.event_id AND ticket.user_id = registration_notice.user_id
+			AND registration_notice.kind <> 'cancellation'
+		WHERE registration_notice.send = FALSE
+		ORDER BY registration_notice.id
+		LIMIT $1
+	`, limit)
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get registration notices: %w", err)
+	}
+	defer rows.Close()
+
+	var notices []entity.RegistrationNotice
+	for rows.Next() {
+		var notice entity.RegistrationNotice
+		err := rows.Scan(&notice.ID, &notice.EventID, &notice.UserID, &notice.Kind, &notice.Mail, &notice.Locale, &notice.Token)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		notices = append(notices, notice)
+	}
+
+	return notices, nil
+}
+
+func (s *storageData) RegistrationNoticeSent(ctx context.Context, noticeID int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE registration_notice SET send = TRUE WHERE id = $1
+	`, noticeID)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE registration notice: %w", err)
+	}
+
+	return nil
+}
+
+// SetSignupDigest turns the daily digest of new signups on or off for an
+// organizer. A digest turned on covers signups from now on.
+func (s *storageData) SetSignupDigest(ctx context.Context, userID int, enabled bool) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE users
+			SET digest_sent_at = CASE WHEN signup_digest THEN digest_sent_at ELSE now() END,
+				signup_digest = $2
+			WHERE id = $1
+	`, userID, enabled)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE signup digest: %w", err)
+	}
+
+	return nil
+}
+
+// DueDigests returns the digests of organizers whose last digest is at least
+// every old and who have new signups since.
+func (s *storageData) DueDigests(ctx context.Context, now time.Time, every time.Duration) ([]entity.Digest, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT users.id, users.mail, users.locale, users.digest_sent_at,
+			event.id, event.title, event.date, COUNT(*), event.participants, event.max_participants
+		FROM users
+		JOIN event ON event.user_id = users.id
+		JOIN registration_notice ON registration_notice.event_id = event.id
+		WHERE users.signup_digest = TRUE
+		AND users.digest_sent_at <= $1::timestamp - $2 * interval '1 second'
+		AND registration_notice.kind IN ('registration', 'promotion')
+		AND registration_notice.created_at > users.digest_sent_at
+		GROUP BY users.id, event.id
+		ORDER BY users.id, event.date
+	`, now, every.Seconds())
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get digests: %w", err)
+	}
+	defer rows.Close()
+
+	var digests []entity.Digest
+	for rows.Next() {
+		var digest entity.Digest
+		var event entity.DigestEvent
+		err := rows.Scan(&digest.UserID, &digest.Mail, &digest.Locale, &digest.Since,
+			&event.EventID, &event.Title, &event.Date, &event.Signups, &event.Participants, &event.MaxParticipants)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+
+		if len(digests) == 0 || digests[len(digests)-1].UserID != digest.UserID {
+			digests = append(digests, digest)
+		}
+		last := &digests[len(digests)-1]
+		last.Events = append(last.Events, event)
+	}
+
+	return digests, nil
+}
+
+// DigestSent moves the start of the next digest of the organizer to at.
+func (s *storageData) DigestSent(ctx context.Context, userID int, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE users SET digest_sent_at = $2 WHERE id = $1
+	`, userID, at)
+	if err != nil {
+		return fmt.Errorf("cannot UPDATE digest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// reminderFrom expands every registration into its reminders: the
+// attendee's own schedule, else the event's, else entity.DefaultReminders
+// ($2). reminderWhere keeps unsent reminders of upcoming active events ($1 is
+// now).
+const (
+	reminderFrom = `
+	FROM record
+	JOIN event ON event.id = record.event_id
+	LEFT JOIN reminder_override ON reminder_override.event_id = record.event_id AND reminder_override.user_id = record.user_id
+	LEFT JOIN event_reminder ON event_reminder.event_id = record.event_id
+	CROSS JOIN LATERAL unnest(COALESCE(reminder_override.minutes, event_reminder.minutes, string_to_array($2, ',')::int[])) AS schedule(minutes)
+`
+	reminderWhere = `
+	WHERE event.active = true AND event.date > $1
+	AND NOT EXISTS (
+		SELECT 1 FROM reminder_sent
+		WHERE reminder_sent.event_id = record.event_id
+		AND reminder_sent.user_id = record.user_id
+		AND reminder_sent.minutes = schedule.minutes
+	)
+`
+)
+
+// formatMinutes and parseMinutes carry INT[] columns as text, "" is an empty
+// schedule.
+func formatMinutes(minutes []int) string {
+	values := make([]string, 0, len(minutes))
+	for _, m := range minutes {
+		values = append(values, strconv.Itoa(m))
+	}
+	return strings.Join(values, ",")
+}
+
+func parseMinutes(s string) ([]int, error) {
+	minutes := []int{}
+	if s == "" {
+		return minutes, nil
+	}
+
+	for _, value := range strings.Split(s, ",") {
+		m, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse minutes: %w", err)
+		}
+		minutes = append(minutes, m)
+	}
+
+	return minutes, nil
+}
+
+// DueReminders returns up to limit unsent reminders whose time has come,
+// ordered by event, attendee and minutes.
+func (s *storageData) DueReminders(ctx context.Context, now time.Time, limit int) ([]entity.Reminder, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT record.event_id, record.user_id, record.id, schedule.minutes, event.date, users.mail, users.locale, COALESCE(ticket.token, '')
+		`+reminderFrom+`
+		JOIN users ON users.id = record.user_id
+		LEFT JOIN ticket ON ticket.event_id = record.event_id AND ticket.user_id = record.user_id
+		`+reminderWhere+`
+		AND event.date - schedule.minutes * interval '1 minute' <= $1
+		ORDER BY record.event_id, record.user_id, schedule.minutes
+		LIMIT $3
+	`, now, formatMinutes(entity.DefaultReminders), limit)
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []entity.Reminder
+	for rows.Next() {
+		var reminder entity.Reminder
+		err := rows.Scan(&reminder.EventID, &reminder.UserID, &reminder.RecordID, &reminder.Minutes, &reminder.Date, &reminder.Mail, &reminder.Locale, &reminder.Token)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+// NextReminder returns when the earliest unsent reminder is due, zero time
+// when there is none.
+func (s *storageData) NextReminder(ctx context.Context, now time.Time) (time.Time, error) {
+	var next sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT MIN(event.date - schedule.minutes * interval '1 minute')
+		`+reminderFrom+reminderWhere, now, formatMinutes(entity.DefaultReminders)).Scan(&next)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot get next reminder: %w", err)
+	}
+
+	return next.Time, nil
+}
+
+// ReminderSent marks reminders of the attendee as sent.
+func (s *storageData) ReminderSent(ctx context.Context, eventID, userID int, minutes []int) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO reminder_sent (event_id, user_id, minutes)
+			SELECT $1, $2, unnest(string_to_array($3, ',')::int[])
+		ON CONFLICT DO NOTHING
+	`, eventID, userID, formatMinutes(minutes))
+	if err != nil {
+		return fmt.Errorf("cannot INSERT reminder sent: %w", err)
+	}
+
+	return nil
+}
+
+// EventReminders returns the schedule of the event, entity.DefaultReminders
+// when the organizer did not set one.
+func (s *storageData) EventReminders(ctx context.Context, eventID int) ([]int, error) {
+	var minutes string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT array_to_string(minutes, ',')
+		FROM event_reminder
+		WHERE event_id = $1
+	`, eventID).Scan(&minutes)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.DefaultReminders, nil
+		}
+		return nil, fmt.Errorf("cannot SELECT event reminder: %w", err)
+	}
+
+	return parseMinutes(minutes)
+}
+
+// SetEventReminders replaces the schedule of the event, an empty one turns
+// reminders off.
+func (s *storageData) SetEventReminders(ctx context.Context, eventID int, minutes []int) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO event_reminder (event_id, minutes)
+		VALUES ($1, string_to_array($2, ',')::int[])
+		ON CONFLICT (event_id) DO UPDATE SET minutes = EXCLUDED.minutes
+	`, eventID, formatMinutes(minutes))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return &NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
+		}
+		return fmt.Errorf("cannot set event reminders: %w", err)
+	}
+
+	return nil
+}
+
+// UserReminders returns the attendee's own schedule for the event, nil when
+// the attendee follows the event's one.
+func (s *storageData) UserReminders(ctx context.Context, eventID, userID int) ([]int, error) {
+	var minutes string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT array_to_string(minutes, ',')
+		FROM reminder_override
+		WHERE event_id = $1 AND user_id = $2
+	`, eventID, userID).Scan(&minutes)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot SELECT reminder override: %w", err)
+	}
+
+	return parseMinutes(minutes)
+}
+
+// SetUserReminders sets the attendee's own schedule, an empty one opts out.
+// A user not registered to the event is a NotFoundError.
+func (s *storageData) SetUserReminders(ctx context.Context, eventID, userID int, minutes []int) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO reminder_override (event_id, user_id, minutes)
+		VALUES ($1, $2, string_to_array($3, ',')::int[])
+		ON CONFLICT (event_id, user_id) DO UPDATE SET minutes = EXCLUDED.minutes
+	`, eventID, userID, formatMinutes(minutes))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return &NotFoundError{Err: fmt.Errorf("user %d not registered for event %d", userID, eventID)}
+		}
+		return fmt.Errorf("cannot set reminder override: %w", err)
+	}
+
+	return nil
+}
+
+func (s *storageData) DellUserReminders(ctx context.Context, eventID, userID int) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM reminder_override
+		WHERE event_id = $1 AND user_id = $2
+	`, eventID, userID)
+	if err != nil {
+		return fmt.Errorf("cannot dell reminder override: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,538 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/rrule"
+	"time"
+)
+
+// maxOccurrences bounds how many events one series creates in a single run.
+const maxOccurrences = 500
+
+// insertOccurrences creates events for the occurrences of series in
+// [from, until] and returns their ids. Cancelled occurrences and the ones that
+// already exist are skipped.
+func insertOccurrences(ctx context.Context, tx *sql.Tx, series *entity.Series, from, until time.Time) ([]int, error) {
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rule: %w", err)
+	}
+
+	var ids []int
+	for _, occurrence := range rule.Between(series.Start, from, until, maxOccurrences) {
+		var id int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO event (user_id, title, description, place, participants, max_participants, date, active, series_id, occurrence)
+				SELECT $1, $2, $3, $4, 0, $5, $6, true, $7, $6
+				WHERE NOT EXISTS (
+					SELECT 1 FROM series_exception
+					WHERE series_id = $7 AND occurrence = $6
+				)
+			ON CONFLICT (series_id, occurrence) DO NOTHING
+			RETURNING id
+		`, series.UserID, series.Title, series.Description, series.Place, series.MaxParticipants, occurrence, series.ID).Scan(&id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("cannot INSERT event: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// CreateSeries saves the series and creates its occurrences up to until as
+// events. It returns the ids of these events.
+func (s *storageData) CreateSeries(ctx context.Context, series *entity.Series, until time.Time) ([]int, error) {
+	var ids []int
+
+	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO series (user_id, title, description, place, max_participants, start, rrule, materialized_until)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, series.UserID, series.Title, series.Description, series.Place, series.MaxParticipants, series.Start, series.RRule, until).Scan(&series.ID)
+		if err != nil {
+			return fmt.Errorf("cannot INSERT series: %w", err)
+		}
+		series.MaterializedUntil = until
+		series.Active = true
+
+		ids, err = insertOccurrences(ctx, tx, series, series.Start, until)
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot create series: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (s *storageData) GetSeries(ctx context.Context, seriesID int) (*entity.Series, error) {
+	var series entity.Series
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, title, description, place, max_participants, start, rrule, materialized_until, active
+		FROM series
+		WHERE id = $1
+	`, seriesID).Scan(
+		&series.ID,
+		&series.UserID,
+		&series.Title,
+		&series.Description,
+		&series.Place,
+		&series.MaxParticipants,
+		&series.Start,
+		&series.RRule,
+		&series.MaterializedUntil,
+		&series.Active)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Err: errors.New("series not exist")}
+		}
+		return nil, fmt.Errorf("cannot SELECT series: %w", err)
+	}
+
+	return &series, nil
+}
+
+// MaterializeSeries creates the events of every active series up to until.
+func (s *storageData) MaterializeSeries(ctx context.Context, until time.Time) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id
+		FROM series
+		WHERE active = true AND materialized_until < $1
+	`, until)
+	if err != nil || rows.Err() != nil {
+		return fmt.Errorf("cannot get series: %w", err)
+	}
+
+	var seriesIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("cannot scan: %w", err)
+		}
+		seriesIDs = append(seriesIDs, id)
+	}
+	rows.Close()
+
+	for _, id := range seriesIDs {
+		err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			var series entity.Series
+			err := tx.QueryRowContext(ctx, `
+				SELECT id, user_id, title, description, place, max_participants, start, rrule, materialized_until
+				FROM series
+				WHERE id = $1 AND active = true
+				FOR UPDATE
+			`, id).Scan(
+				&series.ID,
+				&series.UserID,
+				&series.Title,
+				&series.Description,
+				&series.Place,
+				&series.MaxParticipants,
+				&series.Start,
+				&series.RRule,
+				&series.MaterializedUntil)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil
+				}
+				return fmt.Errorf("cannot lock series: %w", err)
+			}
+
+			if !series.MaterializedUntil.Before(until) {
+				return nil
+			}
+
+			_, err = insertOccurrences(ctx, tx, &series, series.MaterializedUntil.Add(time.Second), until)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				UPDATE series SET materialized_until = $2 WHERE id = $1
+			`, id, until)
+			if err != nil {
+				return fmt.Errorf("cannot UPDATE series: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("cannot materialize series %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// errAttendees refuses to delete occurrences that somebody registered for or
+// waits for, nobody would tell them the event is gone.
+var errAttendees = errors.New("occurrence has attendees or a waitlist")
+
+// hasAttendees reports whether an event of the series from from on, or the
+// event eventID when it is not zero, has participants or a waitlist.
+func hasAttendees(ctx context.Context, tx *sql.Tx, seriesID, eventID int, from time.Time) (bool, error) {
+	var taken bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM event
+			WHERE series_id = $1 AND (id = $2 OR ($2 = 0 AND occurrence >= $3))
+				AND (participants > 0 OR EXISTS (SELECT 1 FROM waitlist WHERE waitlist.event_id = event.id))
+		)
+	`, seriesID, eventID, from).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("cannot check attendees: %w", err)
+	}
+
+	return taken, nil
+}
+
+// CancelOccurrence deletes one event of the series and remembers its
+// occurrence, so the series does not create it again. An occurrence with
+// attendees or a waitlist is a ConflictError.
+func (s *storageData) CancelOccurrence(ctx context.Context, seriesID, eventID int) error {
+	images, err := s.GetImages(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("cannot get images: %w", err)
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var occurrence time.Time
+		err := tx.QueryRowContext(ctx, `
+			SELECT occurrence
+			FROM event
+			WHERE id = $1 AND series_id = $2
+			FOR UPDATE
+		`, eventID, seriesID).Scan(&occurrence)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: errors.New("occurrence not exist")}
+			}
+			return fmt.Errorf("cannot SELECT occurrence: %w", err)
+		}
+
+		taken, err := hasAttendees(ctx, tx, seriesID, eventID, occurrence)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &ConflictError{Err: errAttendees}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO series_exception (series_id, occurrence)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, seriesID, occurrence)
+		if err != nil {
+			return fmt.Errorf("cannot INSERT exception: %w", err)
+		}
+
+		return dellEventRow(ctx, tx, eventID)
+	})
+
+	if err != nil {
+		return fmt.Errorf("cannot cancel occurrence: %w", err)
+	}
+
+	s.removeObjects(ctx, objectNames(images))
+	return nil
+}
+
+// seriesImages returns the images of the events of the series from from on.
+func (s *storageData) seriesImages(ctx context.Context, seriesID int, from time.Time) ([]entity.Image, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id FROM event
+		WHERE series_id = $1 AND occurrence >= $2
+	`, seriesID, from)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get events: %w", err)
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot get events: %w", err)
+	}
+
+	var images []entity.Image
+	for _, id := range ids {
+		eventImages, err := s.GetImages(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, eventImages...)
+	}
+
+	return images, nil
+}
+
+// TruncateSeries ends the series before from: the rule is replaced by rule,
+// which must not produce occurrences from from on, and the events of these
+// occurrences are deleted. A series truncated at its start is deactivated.
+// When one of these events has attendees or a waitlist nothing changes and
+// a ConflictError is returned.
+func (s *storageData) TruncateSeries(ctx context.Context, seriesID int, from time.Time, rule string) error {
+	images, err := s.seriesImages(ctx, seriesID, from)
+	if err != nil {
+		return fmt.Errorf("cannot truncate series: %w", err)
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE series
+				SET rrule = $3, active = active AND $2 > start
+				WHERE id = $1
+		`, seriesID, from, rule)
+		if err != nil {
+			return fmt.Errorf("cannot UPDATE series: %w", err)
+		}
+
+		if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+			return &NotFoundError{Err: errors.New("series not exist")}
+		}
+
+		// registrations lock the event row, so none slips in before the delete
+		_, err = tx.ExecContext(ctx, `
+			SELECT id FROM event
+			WHERE series_id = $1 AND occurrence >= $2
+			FOR UPDATE
+		`, seriesID, from)
+		if err != nil {
+			return fmt.Errorf("cannot lock events: %w", err)
+		}
+
+		taken, err := hasAttendees(ctx, tx, seriesID, 0, from)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &ConflictError{Err: errAttendees}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM event
+			WHERE series_id = $1 AND occurrence >= $2
+		`, seriesID, from)
+		if err != nil {
+			return fmt.Errorf("cannot dell events: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("cannot truncate series: %w", err)
+	}
+
+	s.removeObjects(ctx, objectNames(images))
+	return nil
+}
+
+// SplitSeries applies an edit to the occurrences from from on. The old series
+// ends with rule, next becomes a new series that takes over the events of
+// these occurrences; changes are applied to every event, dates move by shift.
+// It returns the ids of the moved events.
+func (s *storageData) SplitSeries(ctx context.Context, userID, seriesID int, from time.Time, rule string, next *entity.Series, changes []entity.EventChange, shift time.Duration, gen TicketGenerator) ([]int, error) {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot json to byte: %w", err)
+	}
+
+	var ids []int
+
+	err = s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var materializedUntil time.Time
+		err := tx.QueryRowContext(ctx, `
+			SELECT materialized_until
+			FROM series
+			WHERE id = $1
+			FOR UPDATE
+		`, seriesID).Scan(&materializedUntil)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: errors.New("series not exist")}
+			}
+			return fmt.Errorf("cannot lock series: %w", err)
+		}
+
+		var overbooked bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM event
+				WHERE series_id = $1 AND occurrence >= $2 AND participants > $3
+			)
+		`, seriesID, from, next.MaxParticipants).Scan(&overbooked)
+		if err != nil {
+			return fmt.Errorf("cannot check participants: %w", err)
+		}
+		if overbooked {
+			return &ConflictError{Err: fmt.Errorf("max participants %d less than participants", next.MaxParticipants)}
+		}
+
+		next.MaterializedUntil = materializedUntil.Add(shift)
+		next.Active = true
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO series (user_id, title, description, place, max_participants, start, rrule, materialized_until)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, next.UserID, next.Title, next.Description, next.Place, next.MaxParticipants, next.Start, next.RRule, next.MaterializedUntil).Scan(&next.ID)
+		if err != nil {
+			return fmt.Errorf("cannot INSERT series: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE series
+				SET rrule = $3, active = active AND $2 > start, materialized_until = LEAST(materialized_until, $2)
+				WHERE id = $1
+		`, seriesID, from, rule)
+		if err != nil {
+			return fmt.Errorf("cannot UPDATE series: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			WITH moved AS (
+				DELETE FROM series_exception
+				WHERE series_id = $1 AND occurrence >= $2
+				RETURNING occurrence
+			)
+			INSERT INTO series_exception (series_id, occurrence)
+				SELECT $3, occurrence + $4 * interval '1 second'
+				FROM moved
+		`, seriesID, from, next.ID, shift.Seconds())
+		if err != nil {
+			return fmt.Errorf("cannot move exceptions: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			UPDATE event
+				SET series_id = $3, occurrence = occurrence + $4 * interval '1 second', date = date + $4 * interval '1 second'
+				WHERE series_id = $1 AND occurrence >= $2
+				RETURNING id, date
+		`, seriesID, from, next.ID, shift.Seconds())
+		if err != nil || rows.Err() != nil {
+			return fmt.Errorf("cannot move events: %w", err)
+		}
+
+		dates := make(map[int]time.Time)
+		for rows.Next() {
+			var id int
+			var date time.Time
+			if err := rows.Scan(&id, &date); err != nil {
+				rows.Close()
+				return fmt.Errorf("cannot scan: %w", err)
+			}
+			ids = append(ids, id)
+			dates[id] = date
+		}
+		rows.Close()
+
+		for _, id := range ids {
+			if err := applySeriesChanges(ctx, tx, id, next, changes); err != nil {
+				return err
+			}
+
+			if shift != 0 {
+				if err := reissueTickets(ctx, tx, id, dates[id], gen); err != nil {
+					return fmt.Errorf("cannot reissue tickets: %w", err)
+				}
+
+				_, err := tx.ExecContext(ctx, `
+					DELETE FROM reminder_sent WHERE event_id = $1
+				`, id)
+				if err != nil {
+					return fmt.Errorf("cannot reset reminders: %w", err)
+				}
+			}
+
+			for _, change := range changes {
+				_, err := tx.ExecContext(ctx, `
+					INSERT INTO event_audit (event_id, user_id, field, old_value, new_value)
+					VALUES ($1, $2, $3, $4, $5)
+				`, id, userID, change.Field, change.Old, change.New)
+				if err != nil {
+					return fmt.Errorf("cannot INSERT audit: %w", err)
+				}
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO event_change (event_id, user_id, changes)
+					SELECT event_id, user_id, $2
+					FROM record
+					WHERE event_id = $1
+			`, id, body)
+			if err != nil {
+				return fmt.Errorf("cannot INSERT event change: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot split series: %w", err)
+	}
+
+	return ids, nil
+}
+
+// dellEventRow deletes an occurrence; records, tickets and photo rows go with
+// it.
+func dellEventRow(ctx context.Context, tx *sql.Tx, eventID int) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM event WHERE id = $1
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("cannot dell event: %w", err)
+	}
+
+	return nil
+}
+
+// applySeriesChanges copies the changed fields of series to the event, so
+// occurrences edited one by one keep their other fields.
+func applySeriesChanges(ctx context.Context, tx *sql.Tx, eventID int, series *entity.Series, changes []entity.EventChange) error {
+	for _, change := range changes {
+		var query string
+		var value any
+
+		switch change.Field {
+		case "title":
+			query, value = `UPDATE event SET title = $2 WHERE id = $1`, series.Title
+		case "description":
+			query, value = `UPDATE event SET description = $2 WHERE id = $1`, series.Description
+		case "place":
+			query, value = `UPDATE event SET place = $2 WHERE id = $1`, series.Place
+		case "participants":
+			query, value = `UPDATE event SET max_participants = $2 WHERE id = $1`, series.MaxParticipants
+		default:
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, query, eventID, value); err != nil {
+			return fmt.Errorf("cannot UPDATE event %s: %w", change.Field, err)
+		}
+	}
+
+	return nil
+}
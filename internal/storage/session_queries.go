@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"time"
+)
+
+func (s *storageData) CreateSession(ctx context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error) {
+	var id int
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO sessions (user_id, refresh_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, userID, refreshHash, expiresAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("cannot insert session: %w", err)
+	}
+
+	return id, nil
+}
+
+// RefreshSession rotates the refresh token of an active session, so every
+// refresh token can be used only once.
+func (s *storageData) RefreshSession(ctx context.Context, refreshHash, newHash string, expiresAt time.Time) (*entity.Session, error) {
+	session := &entity.Session{ExpiresAt: expiresAt}
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE sessions
+			SET refresh_hash = $2, expires_at = $3
+			FROM users
+			WHERE sessions.refresh_hash = $1
+			AND sessions.revoked = false
+			AND sessions.expires_at > now()
+			AND users.id = sessions.user_id
+		RETURNING sessions.id, sessions.user_id, users.role
+	`, refreshHash, newHash, expiresAt).Scan(&session.ID, &session.UserID, &session.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Err: errors.New("session not found")}
+		}
+		return nil, fmt.Errorf("cannot refresh session: %w", err)
+	}
+
+	return session, nil
+}
+
+func (s *storageData) RevokeSession(ctx context.Context, sessionID int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE sessions
+			SET revoked = true
+			WHERE id = $1
+	`, sessionID)
+	if err != nil {
+		return fmt.Errorf("cannot revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *storageData) RevokeUserSessions(ctx context.Context, userID int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE sessions
+			SET revoked = true
+			WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("cannot revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *storageData) SessionActive(ctx context.Context, sessionID int) (bool, error) {
+	var active bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT NOT revoked AND expires_at > now()
+		FROM sessions
+		WHERE id = $1
+	`, sessionID).Scan(&active)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot get session: %w", err)
+	}
+
+	return active, nil
+}
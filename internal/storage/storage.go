@@ -5,55 +5,111 @@ import (
 	"database/sql"
 	"graduation/internal/entity"
 	"graduation/internal/ostorage"
+	"io"
 
 	"time"
 )
 
 //go:generate mockgen -source=storage.go -destination=mock/mock.go -package=mock
 
+type TicketGenerator interface {
+	Generate(tick *entity.Ticket) error
+}
+
 type UserStorage interface {
-	SetUser(ctx context.Context, login, password, mail string) (int, error)
-	GetUser(ctx context.Context, login, password string) (int, error)
-	AddEventUser(ctx context.Context, tick *entity.Ticket) error
-	DellEventUser(ctx context.Context, eventID, userID int) error
+	SetUser(ctx context.Context, login, password, mail, role string) (int, error)
+	GetUser(ctx context.Context, login string) (*entity.User, error)
+	UpdatePassword(ctx context.Context, userID int, password string) error
+	SetRole(ctx context.Context, login, role string) error
+	GetMail(ctx context.Context, userID int) (string, error)
+	GetLocale(ctx context.Context, userID int) (string, error)
+	SetLocale(ctx context.Context, userID int, locale string) error
+	SetSignupDigest(ctx context.Context, userID int, enabled bool) error
+	MailVerified(ctx context.Context, userID int) (bool, error)
+	VerifyMail(ctx context.Context, userID int, mail string) error
+	CreatePasswordReset(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	ResetPassword(ctx context.Context, tokenHash, password string) error
+	AddEventUser(ctx context.Context, tick *entity.Ticket) (int, error)
+	DellEventUser(ctx context.Context, eventID, userID int, gen TicketGenerator) (*entity.Ticket, error)
 	GetUserEvents(ctx context.Context, userID int) ([]entity.Event, error)
 	UserTickets(ctx context.Context, userID int) ([]entity.Ticket, error)
+	GetTicket(ctx context.Context, userID, eventID int) (*entity.Ticket, error)
+	SetCalendarToken(ctx context.Context, userID int, tokenHash string) error
+	CalendarUser(ctx context.Context, tokenHash string) (int, error)
+	UserReminders(ctx context.Context, eventID, userID int) ([]int, error)
+	SetUserReminders(ctx context.Context, eventID, userID int, minutes []int) error
+	DellUserReminders(ctx context.Context, eventID, userID int) error
+	UserChannels(ctx context.Context, userID int) ([]entity.UserChannel, error)
+	SetUserChannels(ctx context.Context, userID int, channels []entity.UserChannel) error
 }
 
 type EventStorage interface {
 	GetEvent(ctx context.Context, eventID int) (*entity.Event, error)
-	GetEvents(ctx context.Context, from, to time.Time, limit, page int) ([]entity.Event, int, error)
-	GetImage(ctx context.Context, filename string) (string, error)
+	GetEvents(ctx context.Context, filter *entity.EventFilter) ([]entity.Event, int, error)
+	GetImage(ctx context.Context, filename string, expires time.Duration) (string, error)
+	OpenImage(ctx context.Context, filename string) (io.ReadSeekCloser, *ostorage.Object, error)
 	DellEvent(ctx context.Context, userID, eventID int) error
 	CreateEvent(ctx context.Context, e *entity.Event) error
+	AddEventImages(ctx context.Context, eventID int, images []entity.Image) error
+	DellEventImage(ctx context.Context, eventID int, filename string) error
+	ReorderEventImages(ctx context.Context, eventID int, filenames []string) error
+	SetEventCover(ctx context.Context, eventID int, filename string) error
+	CollectImageGarbage(ctx context.Context, uploadedBefore time.Time) (int, error)
+	UpdateEvent(ctx context.Context, userID int, e *entity.Event, changes []entity.EventChange, gen TicketGenerator) error
 	CloseEvent(ctx context.Context, userID, eventID int) error
 	GetDateEvent(ctx context.Context, eventID int) (int, error)
+	RedeemTicket(ctx context.Context, token string, scannerID int) (*entity.Ticket, error)
+	CheckinCount(ctx context.Context, eventID int) (int, error)
+	EventTickets(ctx context.Context, eventID int) ([]entity.Ticket, error)
+	RevokedTickets(ctx context.Context, eventID int) ([]string, error)
+	MergeCheckins(ctx context.Context, eventID, scannerID int, checkins []entity.Checkin) (int, error)
+	EventReminders(ctx context.Context, eventID int) ([]int, error)
+	SetEventReminders(ctx context.Context, eventID int, minutes []int) error
+	CreateSeries(ctx context.Context, series *entity.Series, until time.Time) ([]int, error)
+	GetSeries(ctx context.Context, seriesID int) (*entity.Series, error)
+	CancelOccurrence(ctx context.Context, seriesID, eventID int) error
+	TruncateSeries(ctx context.Context, seriesID int, from time.Time, rule string) error
+	SplitSeries(ctx context.Context, userID, seriesID int, from time.Time, rule string, next *entity.Series, changes []entity.EventChange, shift time.Duration, gen TicketGenerator) ([]int, error)
 }
 
 type NotificationStorage interface {
-	GetMessages(ctx context.Context, date time.Time) ([]entity.Message, error)
-	MessageUpdate(ctx context.Context, eventID, userID int) error
-	EventsToday(ctx context.Context, date time.Time) error
+	CloseFinishedEvents(ctx context.Context, date time.Time) error
+	DueReminders(ctx context.Context, now time.Time, limit int) ([]entity.Reminder, error)
+	NextReminder(ctx context.Context, now time.Time) (time.Time, error)
+	ReminderSent(ctx context.Context, eventID, userID int, minutes []int) error
+	GetChangeNotices(ctx context.Context, limit int) ([]entity.ChangeNotice, error)
+	ChangeNoticeSent(ctx context.Context, noticeID int) error
+	MaterializeSeries(ctx context.Context, until time.Time) error
+	EnqueueMail(ctx context.Context, mails []entity.OutboxMail) error
+	GetRegistrationNotices(ctx context.Context, limit int) ([]entity.RegistrationNotice, error)
+	RegistrationNoticeSent(ctx context.Context, noticeID int) error
+	DueDigests(ctx context.Context, now time.Time, every time.Duration) ([]entity.Digest, error)
+	DigestSent(ctx context.Context, userID int, at time.Time) error
+	ClaimMail(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMail, error)
+	MailSent(ctx context.Context, mailID int) error
+	MailRetry(ctx context.Context, mailID int, reason string, next time.Time) error
+	MailDead(ctx context.Context, mailID int, reason string) error
+	OutboxMails(ctx context.Context, status string, limit, offset int) ([]entity.OutboxMail, int, error)
+	ReplayMail(ctx context.Context, mailID int) error
+}
+
+type SessionStorage interface {
+	CreateSession(ctx context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error)
+	RefreshSession(ctx context.Context, refreshHash, newHash string, expiresAt time.Time) (*entity.Session, error)
+	RevokeSession(ctx context.Context, sessionID int) error
+	RevokeUserSessions(ctx context.Context, userID int) error
+	SessionActive(ctx context.Context, sessionID int) (bool, error)
 }
 
 type Storage interface {
 	UserStorage
+	SessionStorage
 	EventStorage
 	NotificationStorage
+	Close() error
 }
 
 type storageData struct {
 	db  *sql.DB
-	ost *ostorage.Storage
-}
-
-type RepError struct {
-	Err                 error
-	Repetition          bool
-	UniqueViolation     bool
-	ForeignKeyViolation bool
-}
-
-func (e *RepError) Error() string {
-	return e.Err.Error()
+	ost ostorage.BlobStore
 }
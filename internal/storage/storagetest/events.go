@@ -0,0 +1,945 @@
+package storagetest
+
+import (
+	"context"
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var eventTests = []test{
+	{name: "events", run: testEvents},
+	{name: "event listing", run: testEventListing},
+	{name: "registrations", run: testRegistrations},
+	{name: "waitlist promotion", run: testPromotion},
+	{name: "concurrent registrations", run: testConcurrentRegistrations},
+	{name: "delete event", run: testDellEvent},
+	{name: "close event", run: testCloseEvent},
+	{name: "update event", run: testUpdateEvent},
+	{name: "checkins", run: testCheckins},
+	{name: "images", run: testImages},
+	{name: "series", run: testSeries},
+}
+
+func testEvents(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	date := now().Add(10 * time.Hour)
+
+	organizer := newUser(t, st, "organizer")
+	e := newEvent(t, st, organizer, 10, date)
+
+	found, err := st.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, e.ID, found.ID)
+	assert.Equal(t, organizer, found.UserID)
+	assert.Equal(t, "Meetup", found.Title)
+	assert.Equal(t, "Talks and pizza", found.Description)
+	assert.Equal(t, "Moscow", found.Place)
+	assert.Equal(t, 0, found.Participants)
+	assert.Equal(t, 10, found.MaxParticipants)
+	assert.True(t, date.Equal(found.Date))
+	assert.True(t, found.Active)
+	assert.Empty(t, found.Images)
+	assert.Zero(t, found.SeriesID)
+	assert.True(t, date.Equal(found.Occurrence))
+
+	_, err = st.GetEvent(ctx, e.ID+100)
+	assertNotFound(t, err)
+
+	hours, err := st.GetDateEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, hours)
+
+	_, err = st.GetDateEvent(ctx, e.ID+100)
+	assertNotFound(t, err)
+
+	past := newEvent(t, st, organizer, 10, now().Add(-time.Hour))
+	_, err = st.GetDateEvent(ctx, past.ID)
+	assertConflict(t, err)
+}
+
+func testEventListing(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+	date := now().Add(24 * time.Hour)
+
+	organizer := newUser(t, st, "organizer")
+	other := newUser(t, st, "other")
+	attendee := newUser(t, st, "attendee")
+
+	concert := &entity.Event{UserID: organizer, Title: "Jazz concert", Description: "Live music", Place: "Moscow, Red hall", MaxParticipants: 3, Date: date, Active: true}
+	lecture := &entity.Event{UserID: organizer, Title: "Lecture", Description: "About jazz history", Place: "Kazan", MaxParticipants: 10, Date: date.Add(time.Hour), Active: true}
+	game := &entity.Event{UserID: other, Title: "Board games", Description: "Bring friends", Place: "moscow", MaxParticipants: 2, Date: date.Add(2 * time.Hour), Active: true}
+	closed := &entity.Event{UserID: organizer, Title: "Closed jazz", Place: "Moscow", MaxParticipants: 5, Date: date, Active: false}
+	later := &entity.Event{UserID: organizer, Title: "Jazz later", Place: "Moscow", MaxParticipants: 5, Date: date.Add(30 * 24 * time.Hour), Active: true}
+	for _, e := range []*entity.Event{concert, lecture, game, closed, later} {
+		require.NoError(t, st.CreateEvent(ctx, e))
+	}
+	register(t, st, gen, concert, attendee)
+	register(t, st, gen, concert, other)
+	register(t, st, gen, game, attendee)
+
+	ids := func(events []entity.Event) []int {
+		var ids []int
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name   string
+		filter entity.EventFilter
+		want   []int
+		total  int
+	}{
+		{
+			name:   "window",
+			filter: entity.EventFilter{},
+			want:   []int{concert.ID, lecture.ID, game.ID},
+			total:  3,
+		},
+		{
+			name:   "date desc",
+			filter: entity.EventFilter{Sort: entity.SortDateDesc},
+			want:   []int{game.ID, lecture.ID, concert.ID},
+			total:  3,
+		},
+		{
+			name:   "seats",
+			filter: entity.EventFilter{Sort: entity.SortSeats},
+			want:   []int{lecture.ID, concert.ID, game.ID},
+			total:  3,
+		},
+		{
+			name:   "popularity",
+			filter: entity.EventFilter{Sort: entity.SortPopularity},
+			want:   []int{concert.ID, game.ID, lecture.ID},
+			total:  3,
+		},
+		{
+			name:   "organizer",
+			filter: entity.EventFilter{OrganizerID: organizer},
+			want:   []int{concert.ID, lecture.ID},
+			total:  2,
+		},
+		{
+			name:   "place",
+			filter: entity.EventFilter{Place: "MOSCOW"},
+			want:   []int{concert.ID, game.ID},
+			total:  2,
+		},
+		{
+			name:   "min seats",
+			filter: entity.EventFilter{MinSeats: 2},
+			want:   []int{lecture.ID},
+			total:  1,
+		},
+		{
+			name:   "query",
+			filter: entity.EventFilter{Query: "jazz", Sort: entity.SortRelevance},
+			want:   []int{concert.ID, lecture.ID},
+			total:  2,
+		},
+		{
+			name:   "query with or",
+			filter: entity.EventFilter{Query: "lecture or board"},
+			want:   []int{lecture.ID, game.ID},
+			total:  2,
+		},
+		{
+			name:   "query with or and words around",
+			filter: entity.EventFilter{Query: "jazz concert or history"},
+			want:   []int{concert.ID, lecture.ID},
+			total:  2,
+		},
+		{
+			name:   "query without a word",
+			filter: entity.EventFilter{Query: "jazz -music"},
+			want:   []int{lecture.ID},
+			total:  1,
+		},
+		{
+			name:   "page",
+			filter: entity.EventFilter{Limit: 2, Page: 2},
+			want:   []int{game.ID},
+			total:  3,
+		},
+		{
+			name:   "page after the last",
+			filter: entity.EventFilter{Limit: 2, Page: 3},
+			total:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.From = date.Add(-time.Hour)
+			filter.To = date.Add(7 * 24 * time.Hour)
+			if filter.Sort == "" {
+				filter.Sort = entity.SortDate
+			}
+			if filter.Limit == 0 {
+				filter.Limit = 10
+			}
+			if filter.Page == 0 {
+				filter.Page = 1
+			}
+
+			events, total, err := st.GetEvents(ctx, &filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(events))
+			assert.Equal(t, tt.total, total)
+		})
+	}
+}
+
+func testRegistrations(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+
+	organizer := newUser(t, st, "organizer")
+	first := newUser(t, st, "first")
+	second := newUser(t, st, "second")
+	third := newUser(t, st, "third")
+	fourth := newUser(t, st, "fourth")
+	e := newEvent(t, st, organizer, 2, now().Add(48*time.Hour))
+
+	assert.Equal(t, 0, register(t, st, gen, e, first))
+	assert.Equal(t, 0, register(t, st, gen, e, second))
+
+	_, err := st.AddEventUser(ctx, &entity.Ticket{UserID: first, EventID: e.ID, Token: "duplicate"})
+	assertConflict(t, err)
+
+	assert.Equal(t, 1, register(t, st, gen, e, third))
+	assert.Equal(t, 2, register(t, st, gen, e, fourth))
+
+	_, err = st.AddEventUser(ctx, &entity.Ticket{UserID: third, EventID: e.ID, Token: "waiting"})
+	assertConflict(t, err)
+
+	_, err = st.AddEventUser(ctx, &entity.Ticket{UserID: first, EventID: e.ID + 100, Token: "missing"})
+	assertNotFound(t, err)
+
+	assert.Equal(t, 2, participants(t, st, e.ID))
+
+	ticket, err := st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, ticket.UserID)
+	assert.Equal(t, e.ID, ticket.EventID)
+	assert.NotEmpty(t, ticket.Token)
+	assert.True(t, ticket.Status)
+
+	_, err = st.GetTicket(ctx, third, e.ID)
+	assertNotFound(t, err)
+
+	tickets, err := st.UserTickets(ctx, first)
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	assert.Equal(t, ticket.Token, tickets[0].Token)
+	assert.Equal(t, e.ID, tickets[0].EventID)
+
+	events, err := st.GetUserEvents(ctx, first)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, e.ID, events[0].ID)
+
+	events, err = st.GetUserEvents(ctx, third)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// leaving the waitlist frees no seat
+	promoted, err := st.DellEventUser(ctx, e.ID, fourth, gen)
+	require.NoError(t, err)
+	assert.Nil(t, promoted)
+
+	_, err = st.DellEventUser(ctx, e.ID, fourth, gen)
+	assertConflict(t, err)
+
+	_, err = st.DellEventUser(ctx, e.ID+100, first, gen)
+	assertNotFound(t, err)
+}
+
+func testPromotion(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+
+	organizer := newUser(t, st, "organizer")
+	first := newUser(t, st, "first")
+	second := newUser(t, st, "second")
+	e := newEvent(t, st, organizer, 1, now().Add(48*time.Hour))
+
+	register(t, st, gen, e, first)
+	assert.Equal(t, 1, register(t, st, gen, e, second))
+
+	ticket, err := st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+
+	// a promotion that can not sign the ticket keeps the registration
+	_, err = st.DellEventUser(ctx, e.ID, first, failing{})
+	require.Error(t, err)
+	_, err = st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, participants(t, st, e.ID))
+
+	promoted, err := st.DellEventUser(ctx, e.ID, first, gen)
+	require.NoError(t, err)
+	require.NotNil(t, promoted)
+	assert.Equal(t, second, promoted.UserID)
+	assert.Equal(t, e.ID, promoted.EventID)
+	assert.NotEmpty(t, promoted.Token)
+
+	_, err = st.GetTicket(ctx, first, e.ID)
+	assertNotFound(t, err)
+	ticketSecond, err := st.GetTicket(ctx, second, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, promoted.Token, ticketSecond.Token)
+	assert.Equal(t, 1, participants(t, st, e.ID))
+
+	revoked, err := st.RevokedTickets(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ticket.Token}, revoked)
+
+	notices, err := st.GetRegistrationNotices(ctx, 10)
+	require.NoError(t, err)
+	type notice struct {
+		userID int
+		kind   string
+	}
+	var got []notice
+	for _, n := range notices {
+		assert.Equal(t, e.ID, n.EventID)
+		got = append(got, notice{userID: n.UserID, kind: n.Kind})
+	}
+	assert.Equal(t, []notice{
+		{userID: first, kind: entity.NoticeRegistration},
+		{userID: first, kind: entity.NoticeCancellation},
+		{userID: second, kind: entity.NoticePromotion},
+	}, got)
+	assert.Equal(t, promoted.Token, notices[2].Token)
+	assert.Equal(t, "second@mail.ru", notices[2].Mail)
+
+	require.NoError(t, st.RegistrationNoticeSent(ctx, notices[0].ID))
+	notices, err = st.GetRegistrationNotices(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, notices, 1)
+	assert.Equal(t, entity.NoticeCancellation, notices[0].Kind)
+
+	// raising the capacity promotes the waitlist into the new seats
+	third := newUser(t, st, "third")
+	fourth := newUser(t, st, "fourth")
+	fifth := newUser(t, st, "fifth")
+	raised := newEvent(t, st, organizer, 1, now().Add(48*time.Hour))
+	register(t, st, gen, raised, third)
+	register(t, st, gen, raised, fourth)
+	assert.Equal(t, 2, register(t, st, gen, raised, fifth))
+
+	bigger := *raised
+	bigger.MaxParticipants = 2
+	require.NoError(t, st.UpdateEvent(ctx, organizer, &bigger, nil, gen))
+	assert.Equal(t, 2, participants(t, st, raised.ID))
+	_, err = st.GetTicket(ctx, fourth, raised.ID)
+	require.NoError(t, err)
+	_, err = st.GetTicket(ctx, fifth, raised.ID)
+	assertNotFound(t, err)
+
+	bigger.MaxParticipants = 5
+	require.NoError(t, st.UpdateEvent(ctx, organizer, &bigger, nil, gen))
+	assert.Equal(t, 3, participants(t, st, raised.ID))
+	fifthTicket, err := st.GetTicket(ctx, fifth, raised.ID)
+	require.NoError(t, err)
+
+	notices, err = st.GetRegistrationNotices(ctx, 20)
+	require.NoError(t, err)
+	got = nil
+	for _, n := range notices {
+		if n.EventID == raised.ID {
+			got = append(got, notice{userID: n.UserID, kind: n.Kind})
+			if n.UserID == fifth {
+				assert.Equal(t, fifthTicket.Token, n.Token)
+			}
+		}
+	}
+	assert.Equal(t, []notice{
+		{userID: third, kind: entity.NoticeRegistration},
+		{userID: fourth, kind: entity.NoticePromotion},
+		{userID: fifth, kind: entity.NoticePromotion},
+	}, got)
+}
+
+func testConcurrentRegistrations(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	const seats, users = 5, 20
+
+	gen := &generator{}
+	organizer := newUser(t, st, "organizer")
+	e := newEvent(t, st, organizer, seats, now().Add(48*time.Hour))
+
+	ids := make([]int, users)
+	for i := range ids {
+		ids[i] = newUser(t, st, "user"+string(rune('a'+i)))
+	}
+
+	positions := make([]int, users)
+	errs := make([]error, users)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+
+			tick := &entity.Ticket{UserID: id, EventID: e.ID, Exp: 48}
+			if errs[i] = gen.Generate(tick); errs[i] == nil {
+				positions[i], errs[i] = st.AddEventUser(context.Background(), tick)
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	sort.Ints(positions)
+	want := make([]int, users)
+	for i := seats; i < users; i++ {
+		want[i] = i - seats + 1
+	}
+	assert.Equal(t, want, positions)
+	assert.Equal(t, seats, participants(t, st, e.ID))
+}
+
+func testDellEvent(t *testing.T, st storage.Storage, ost ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+
+	organizer := newUser(t, st, "organizer")
+	attendee := newUser(t, st, "attendee")
+	waiting := newUser(t, st, "waiting")
+
+	e := &entity.Event{
+		UserID:          organizer,
+		Title:           "Meetup",
+		MaxParticipants: 1,
+		Date:            now().Add(48 * time.Hour),
+		Active:          true,
+		Images: []entity.Image{{
+			Filename:   "cover.png",
+			Data:       []byte("cover"),
+			MIME:       "image/png",
+			Thumbnails: []entity.Thumbnail{{Filename: "cover_320.png", Data: []byte("thumb"), MIME: "image/png"}},
+		}},
+	}
+	require.NoError(t, st.CreateEvent(ctx, e))
+	register(t, st, gen, e, attendee)
+	register(t, st, gen, e, waiting)
+	require.NoError(t, st.SetEventReminders(ctx, e.ID, []int{60}))
+	require.NoError(t, st.SetUserReminders(ctx, e.ID, attendee, []int{30}))
+
+	assertForbidden(t, st.DellEvent(ctx, attendee, e.ID))
+	assertNotFound(t, st.DellEvent(ctx, organizer, e.ID+100))
+
+	require.NoError(t, st.DellEvent(ctx, organizer, e.ID))
+
+	_, err := st.GetEvent(ctx, e.ID)
+	assertNotFound(t, err)
+	_, err = st.GetTicket(ctx, attendee, e.ID)
+	assertNotFound(t, err)
+
+	tickets, err := st.UserTickets(ctx, attendee)
+	require.NoError(t, err)
+	assert.Empty(t, tickets)
+
+	events, err := st.GetUserEvents(ctx, attendee)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	reminders, err := st.UserReminders(ctx, e.ID, attendee)
+	require.NoError(t, err)
+	assert.Nil(t, reminders)
+
+	_, _, err = st.OpenImage(ctx, "cover.png")
+	assertNotFound(t, err)
+	for _, name := range []string{"cover.png", "cover_320.png"} {
+		_, _, err = ost.Get(ctx, name)
+		assert.ErrorIs(t, err, ostorage.ErrNotExist)
+	}
+
+	// the waitlist went with the event, the user can join a new one
+	other := newEvent(t, st, organizer, 1, now().Add(48*time.Hour))
+	assert.Equal(t, 0, register(t, st, gen, other, waiting))
+}
+
+func testCloseEvent(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+
+	organizer := newUser(t, st, "organizer")
+	attendee := newUser(t, st, "attendee")
+	e := newEvent(t, st, organizer, 10, now().Add(48*time.Hour))
+
+	assertForbidden(t, st.CloseEvent(ctx, attendee, e.ID))
+	assertNotFound(t, st.CloseEvent(ctx, organizer, e.ID+100))
+
+	require.NoError(t, st.CloseEvent(ctx, organizer, e.ID))
+
+	found, err := st.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.False(t, found.Active)
+
+	tick := &entity.Ticket{UserID: attendee, EventID: e.ID, Exp: 48}
+	require.NoError(t, gen.Generate(tick))
+	_, err = st.AddEventUser(ctx, tick)
+	assertConflict(t, err)
+
+	finished := newEvent(t, st, organizer, 10, now().Add(-2*time.Hour))
+	upcoming := newEvent(t, st, organizer, 10, now().Add(2*time.Hour))
+	require.NoError(t, st.CloseFinishedEvents(ctx, now()))
+
+	found, err = st.GetEvent(ctx, finished.ID)
+	require.NoError(t, err)
+	assert.False(t, found.Active)
+
+	found, err = st.GetEvent(ctx, upcoming.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Active)
+}
+
+func testUpdateEvent(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+	date := now().Add(48 * time.Hour)
+
+	organizer := newUser(t, st, "organizer")
+	first := newUser(t, st, "first")
+	second := newUser(t, st, "second")
+	e := newEvent(t, st, organizer, 10, date)
+	register(t, st, gen, e, first)
+	register(t, st, gen, e, second)
+
+	old, err := st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+
+	shrunk := *e
+	shrunk.MaxParticipants = 1
+	err = st.UpdateEvent(ctx, organizer, &shrunk, []entity.EventChange{{Field: "participants", Old: "10", New: "1"}}, gen)
+	assertConflict(t, err)
+
+	missing := *e
+	missing.ID = e.ID + 100
+	assertNotFound(t, st.UpdateEvent(ctx, organizer, &missing, nil, gen))
+
+	renamed := *e
+	renamed.Title = "Renamed"
+	renamed.Place = "Kazan"
+	changes := []entity.EventChange{
+		{Field: "title", Old: "Meetup", New: "Renamed"},
+		{Field: "place", Old: "Moscow", New: "Kazan"},
+	}
+	require.NoError(t, st.UpdateEvent(ctx, organizer, &renamed, changes, gen))
+
+	found, err := st.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", found.Title)
+	assert.Equal(t, "Kazan", found.Place)
+	assert.Equal(t, 2, found.Participants)
+
+	// the date did not change, tickets stay
+	ticket, err := st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, old.Token, ticket.Token)
+
+	notices, err := st.GetChangeNotices(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, notices, 2)
+	var userIDs []int
+	for _, notice := range notices {
+		userIDs = append(userIDs, notice.UserID)
+		assert.Equal(t, e.ID, notice.EventID)
+		assert.Equal(t, changes, notice.Changes)
+	}
+	assert.ElementsMatch(t, []int{first, second}, userIDs)
+
+	for _, notice := range notices {
+		require.NoError(t, st.ChangeNoticeSent(ctx, notice.ID))
+	}
+	notices, err = st.GetChangeNotices(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, notices)
+
+	moved := renamed
+	moved.Date = date.Add(24 * time.Hour)
+	require.NoError(t, st.UpdateEvent(ctx, organizer, &moved, []entity.EventChange{{Field: "date"}}, gen))
+
+	found, err = st.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, moved.Date.Equal(found.Date))
+
+	ticket, err = st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, old.Token, ticket.Token)
+
+	revoked, err := st.RevokedTickets(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Contains(t, revoked, old.Token)
+	assert.Len(t, revoked, 2)
+
+	notices, err = st.GetChangeNotices(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, notices, 2)
+	for _, notice := range notices {
+		if notice.UserID == first {
+			assert.Equal(t, ticket.Token, notice.Token)
+		}
+	}
+
+	// a failed reissue leaves the event where it was
+	failed := moved
+	failed.Date = date.Add(72 * time.Hour)
+	require.Error(t, st.UpdateEvent(ctx, organizer, &failed, nil, failing{}))
+	found, err = st.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, moved.Date.Equal(found.Date))
+}
+
+func testCheckins(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+
+	organizer := newUser(t, st, "organizer")
+	first := newUser(t, st, "first")
+	second := newUser(t, st, "second")
+	e := newEvent(t, st, organizer, 10, now().Add(48*time.Hour))
+	register(t, st, gen, e, first)
+	register(t, st, gen, e, second)
+
+	ticketFirst, err := st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+	ticketSecond, err := st.GetTicket(ctx, second, e.ID)
+	require.NoError(t, err)
+
+	redeemed, err := st.RedeemTicket(ctx, ticketFirst.Token, organizer)
+	require.NoError(t, err)
+	assert.Equal(t, first, redeemed.UserID)
+	assert.Equal(t, e.ID, redeemed.EventID)
+	assert.Equal(t, organizer, redeemed.RedeemedBy)
+	assert.False(t, redeemed.RedeemedAt.IsZero())
+
+	_, err = st.RedeemTicket(ctx, ticketFirst.Token, organizer)
+	assertConflict(t, err)
+	_, err = st.RedeemTicket(ctx, "unknown", organizer)
+	assertNotFound(t, err)
+
+	count, err := st.CheckinCount(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	scannedAt := now().Add(-time.Hour)
+	merged, err := st.MergeCheckins(ctx, e.ID, organizer, []entity.Checkin{
+		{Token: ticketSecond.Token, ScannedAt: scannedAt},
+		{Token: "unknown", ScannedAt: scannedAt},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, merged)
+
+	// a later scan does not move the check-in
+	merged, err = st.MergeCheckins(ctx, e.ID, organizer, []entity.Checkin{
+		{Token: ticketSecond.Token, ScannedAt: scannedAt.Add(time.Minute)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, merged)
+
+	tickets, err := st.EventTickets(ctx, e.ID)
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	for _, ticket := range tickets {
+		assert.True(t, ticket.Status)
+		if ticket.UserID == second {
+			assert.True(t, scannedAt.Equal(ticket.RedeemedAt))
+		}
+	}
+
+	count, err = st.CheckinCount(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func testImages(t *testing.T, st storage.Storage, ost ostorage.BlobStore) {
+	ctx := context.Background()
+
+	organizer := newUser(t, st, "organizer")
+	e := newEvent(t, st, organizer, 10, now().Add(48*time.Hour))
+
+	image := func(name string) entity.Image {
+		return entity.Image{
+			Filename: name + ".png",
+			Data:     []byte(name),
+			MIME:     "image/png",
+			Width:    640,
+			Height:   480,
+			Thumbnails: []entity.Thumbnail{
+				{Filename: name + "_320.png", Data: []byte(name + " thumb"), MIME: "image/png", Width: 320, Height: 240},
+			},
+		}
+	}
+	names := func() []string {
+		t.Helper()
+
+		found, err := st.GetEvent(ctx, e.ID)
+		require.NoError(t, err)
+
+		var names []string
+		for _, image := range found.Images {
+			name := image.Filename
+			if image.Cover {
+				name += "*"
+			}
+			names = append(names, name)
+		}
+		return names
+	}
+
+	assertNotFound(t, st.AddEventImages(ctx, e.ID+100, []entity.Image{image("lost")}))
+
+	require.NoError(t, st.AddEventImages(ctx, e.ID, []entity.Image{image("a"), image("b")}))
+	require.NoError(t, st.AddEventImages(ctx, e.ID, []entity.Image{image("c")}))
+	assert.Equal(t, []string{"a.png*", "b.png", "c.png"}, names())
+
+	found, err := st.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Images[0].Thumbnails, 1)
+	assert.Equal(t, "a_320.png", found.Images[0].Thumbnails[0].Filename)
+	assert.Equal(t, 320, found.Images[0].Thumbnails[0].Width)
+	assert.Equal(t, 640, found.Images[0].Width)
+	assert.Equal(t, "image/png", found.Images[0].MIME)
+
+	content, object, err := st.OpenImage(ctx, "b_320.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	assert.Equal(t, "b thumb", string(data))
+	assert.Equal(t, "image/png", object.ContentType)
+
+	_, _, err = st.OpenImage(ctx, "unknown.png")
+	assertNotFound(t, err)
+	_, err = st.GetImage(ctx, "unknown.png", time.Minute)
+	assertNotFound(t, err)
+
+	require.NoError(t, st.ReorderEventImages(ctx, e.ID, []string{"c.png", "a.png", "b.png"}))
+	assert.Equal(t, []string{"c.png", "a.png*", "b.png"}, names())
+	assertConflict(t, st.ReorderEventImages(ctx, e.ID, []string{"a.png"}))
+	assertNotFound(t, st.ReorderEventImages(ctx, e.ID, []string{"a.png", "b.png", "d.png"}))
+
+	require.NoError(t, st.SetEventCover(ctx, e.ID, "b.png"))
+	assert.Equal(t, []string{"c.png", "a.png", "b.png*"}, names())
+	assertNotFound(t, st.SetEventCover(ctx, e.ID, "d.png"))
+
+	// deleting the cover makes the first image the cover
+	require.NoError(t, st.DellEventImage(ctx, e.ID, "b.png"))
+	assert.Equal(t, []string{"c.png*", "a.png"}, names())
+	assertNotFound(t, st.DellEventImage(ctx, e.ID, "b.png"))
+	for _, name := range []string{"b.png", "b_320.png"} {
+		_, _, err = ost.Get(ctx, name)
+		assert.ErrorIs(t, err, ostorage.ErrNotExist)
+	}
+
+	require.NoError(t, ost.Put(ctx, "orphan.png", []byte("orphan"), "image/png"))
+	removed, err := st.CollectImageGarbage(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	objects, err := ost.List(ctx)
+	require.NoError(t, err)
+	var stored []string
+	for _, object := range objects {
+		stored = append(stored, object.Name)
+	}
+	assert.ElementsMatch(t, []string{"a.png", "a_320.png", "c.png", "c_320.png"}, stored)
+}
+
+func testSeries(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+	day := 24 * time.Hour
+	start := now().Add(day)
+
+	organizer := newUser(t, st, "organizer")
+	attendee := newUser(t, st, "attendee")
+
+	series := &entity.Series{
+		UserID:          organizer,
+		Title:           "Yoga",
+		Description:     "Morning yoga",
+		Place:           "Park",
+		MaxParticipants: 5,
+		Start:           start,
+		RRule:           "FREQ=DAILY",
+	}
+	ids, err := st.CreateSeries(ctx, series, start.Add(4*day))
+	require.NoError(t, err)
+	require.Len(t, ids, 5)
+	require.NotZero(t, series.ID)
+
+	_, err = st.CreateSeries(ctx, &entity.Series{UserID: organizer, Start: start, RRule: "FREQ=NEVER"}, start.Add(day))
+	assert.Error(t, err)
+
+	found, err := st.GetSeries(ctx, series.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Active)
+	assert.Equal(t, "FREQ=DAILY", found.RRule)
+	assert.True(t, start.Add(4*day).Equal(found.MaterializedUntil))
+
+	_, err = st.GetSeries(ctx, series.ID+100)
+	assertNotFound(t, err)
+
+	occurrence, err := st.GetEvent(ctx, ids[3])
+	require.NoError(t, err)
+	assert.Equal(t, series.ID, occurrence.SeriesID)
+	assert.Equal(t, "Yoga", occurrence.Title)
+	assert.Equal(t, 5, occurrence.MaxParticipants)
+	assert.True(t, start.Add(3*day).Equal(occurrence.Date))
+	assert.True(t, start.Add(3*day).Equal(occurrence.Occurrence))
+
+	require.NoError(t, st.MaterializeSeries(ctx, start.Add(6*day)))
+	listing := func() int {
+		t.Helper()
+
+		_, total, err := st.GetEvents(ctx, &entity.EventFilter{
+			From:        start.Add(-time.Hour),
+			To:          start.Add(30 * day),
+			OrganizerID: organizer,
+			Sort:        entity.SortDate,
+			Limit:       50,
+			Page:        1,
+		})
+		require.NoError(t, err)
+		return total
+	}
+	assert.Equal(t, 7, listing())
+
+	// materializing again creates nothing twice
+	require.NoError(t, st.MaterializeSeries(ctx, start.Add(6*day)))
+	assert.Equal(t, 7, listing())
+
+	assertNotFound(t, st.CancelOccurrence(ctx, series.ID+100, ids[1]))
+	require.NoError(t, st.CancelOccurrence(ctx, series.ID, ids[1]))
+	_, err = st.GetEvent(ctx, ids[1])
+	assertNotFound(t, err)
+	assert.Equal(t, 6, listing())
+
+	// occurrences past the created events are listed without being created
+	events, total, err := st.GetEvents(ctx, &entity.EventFilter{
+		From:        start.Add(-time.Hour),
+		To:          start.Add(30 * day),
+		OrganizerID: organizer,
+		Sort:        entity.SortDate,
+		Limit:       50,
+		Page:        1,
+		SeriesUntil: start.Add(9 * day),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9, total)
+	require.Len(t, events, 9)
+	for i, e := range events[6:] {
+		assert.Zero(t, e.ID)
+		assert.Equal(t, series.ID, e.SeriesID)
+		assert.Equal(t, "Yoga", e.Title)
+		assert.True(t, start.Add(time.Duration(7+i)*day).Equal(e.Date))
+	}
+	assert.Equal(t, 6, listing())
+	found, err = st.GetSeries(ctx, series.ID)
+	require.NoError(t, err)
+	assert.True(t, start.Add(6*day).Equal(found.MaterializedUntil))
+
+	register(t, st, gen, occurrence, attendee)
+	old, err := st.GetTicket(ctx, attendee, occurrence.ID)
+	require.NoError(t, err)
+
+	from := start.Add(3 * day)
+	next := &entity.Series{
+		UserID:          organizer,
+		Title:           "Evening yoga",
+		Description:     "Morning yoga",
+		Place:           "Park",
+		MaxParticipants: 0,
+		Start:           from.Add(time.Hour),
+		RRule:           "FREQ=DAILY",
+	}
+	changes := []entity.EventChange{{Field: "title", Old: "Yoga", New: "Evening yoga"}}
+
+	// an occurrence has more attendees than the new series allows
+	_, err = st.SplitSeries(ctx, organizer, series.ID, from, "FREQ=DAILY;COUNT=3", next, changes, time.Hour, gen)
+	assertConflict(t, err)
+
+	next.MaxParticipants = 5
+	moved, err := st.SplitSeries(ctx, organizer, series.ID, from, "FREQ=DAILY;COUNT=3", next, changes, time.Hour, gen)
+	require.NoError(t, err)
+	assert.Len(t, moved, 4)
+	assert.Contains(t, moved, occurrence.ID)
+	require.NotZero(t, next.ID)
+
+	found, err = st.GetSeries(ctx, series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY;COUNT=3", found.RRule)
+	assert.True(t, found.Active)
+	assert.True(t, from.Equal(found.MaterializedUntil))
+
+	split, err := st.GetSeries(ctx, next.ID)
+	require.NoError(t, err)
+	assert.True(t, split.Active)
+	assert.Equal(t, "Evening yoga", split.Title)
+	assert.True(t, start.Add(6*day+time.Hour).Equal(split.MaterializedUntil))
+
+	occurrence, err = st.GetEvent(ctx, occurrence.ID)
+	require.NoError(t, err)
+	assert.Equal(t, next.ID, occurrence.SeriesID)
+	assert.Equal(t, "Evening yoga", occurrence.Title)
+	assert.True(t, from.Add(time.Hour).Equal(occurrence.Date))
+	assert.True(t, from.Add(time.Hour).Equal(occurrence.Occurrence))
+
+	ticket, err := st.GetTicket(ctx, attendee, occurrence.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, old.Token, ticket.Token)
+	revoked, err := st.RevokedTickets(ctx, occurrence.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{old.Token}, revoked)
+
+	notices, err := st.GetChangeNotices(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, notices, 1)
+	assert.Equal(t, attendee, notices[0].UserID)
+	assert.Equal(t, changes, notices[0].Changes)
+
+	// occurrences somebody registered for are not deleted behind their back
+	assertConflict(t, st.CancelOccurrence(ctx, next.ID, occurrence.ID))
+	assertConflict(t, st.TruncateSeries(ctx, next.ID, next.Start, "FREQ=DAILY;COUNT=1"))
+	split, err = st.GetSeries(ctx, next.ID)
+	require.NoError(t, err)
+	assert.True(t, split.Active)
+	assert.Equal(t, "FREQ=DAILY", split.RRule)
+	_, err = st.GetTicket(ctx, attendee, occurrence.ID)
+	require.NoError(t, err)
+
+	_, err = st.DellEventUser(ctx, occurrence.ID, attendee, gen)
+	require.NoError(t, err)
+
+	// truncating at the start ends the series
+	require.NoError(t, st.TruncateSeries(ctx, next.ID, next.Start, "FREQ=DAILY;COUNT=1"))
+	split, err = st.GetSeries(ctx, next.ID)
+	require.NoError(t, err)
+	assert.False(t, split.Active)
+	for _, id := range moved {
+		_, err = st.GetEvent(ctx, id)
+		assertNotFound(t, err)
+	}
+	assert.Equal(t, 2, listing())
+
+	assertNotFound(t, st.TruncateSeries(ctx, next.ID+100, next.Start, "FREQ=DAILY"))
+}
@@ -0,0 +1,292 @@
+package storagetest
+
+import (
+	"context"
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var notificationTests = []test{
+	{name: "reminders", run: testReminders},
+	{name: "outbox", run: testOutbox},
+	{name: "digests", run: testDigests},
+}
+
+func testReminders(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+	at := now()
+	date := at.Add(2 * time.Hour)
+
+	organizer := newUser(t, st, "organizer")
+	first := newUser(t, st, "first")
+	second := newUser(t, st, "second")
+	e := newEvent(t, st, organizer, 10, date)
+	register(t, st, gen, e, first)
+	register(t, st, gen, e, second)
+
+	minutes, err := st.EventReminders(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DefaultReminders, minutes)
+
+	assertNotFound(t, st.SetEventReminders(ctx, e.ID+100, []int{60}))
+	require.NoError(t, st.SetEventReminders(ctx, e.ID, []int{180, 60}))
+	minutes, err = st.EventReminders(ctx, e.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{180, 60}, minutes)
+
+	minutes, err = st.UserReminders(ctx, e.ID, second)
+	require.NoError(t, err)
+	assert.Nil(t, minutes)
+
+	assertNotFound(t, st.SetUserReminders(ctx, e.ID, organizer, []int{10}))
+	require.NoError(t, st.SetUserReminders(ctx, e.ID, second, []int{10}))
+	minutes, err = st.UserReminders(ctx, e.ID, second)
+	require.NoError(t, err)
+	assert.Equal(t, []int{10}, minutes)
+
+	reminders, err := st.DueReminders(ctx, at, 10)
+	require.NoError(t, err)
+	ticket, err := st.GetTicket(ctx, first, e.ID)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, first, reminders[0].UserID)
+	assert.Equal(t, e.ID, reminders[0].EventID)
+	assert.Equal(t, 180, reminders[0].Minutes)
+	assert.True(t, date.Equal(reminders[0].Date))
+	assert.Equal(t, "first@mail.ru", reminders[0].Mail)
+	assert.Equal(t, ticket.Token, reminders[0].Token)
+	assert.NotZero(t, reminders[0].RecordID)
+	record := reminders[0].RecordID
+
+	next, err := st.NextReminder(ctx, at)
+	require.NoError(t, err)
+	assert.True(t, date.Add(-180*time.Minute).Equal(next))
+
+	require.NoError(t, st.ReminderSent(ctx, e.ID, first, []int{180}))
+	assert.Error(t, st.ReminderSent(ctx, e.ID, organizer, []int{180}))
+
+	reminders, err = st.DueReminders(ctx, at, 10)
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	next, err = st.NextReminder(ctx, at)
+	require.NoError(t, err)
+	assert.True(t, date.Add(-60*time.Minute).Equal(next))
+
+	reminders, err = st.DueReminders(ctx, date.Add(-5*time.Minute), 10)
+	require.NoError(t, err)
+	type due struct {
+		userID  int
+		minutes int
+	}
+	var got []due
+	for _, reminder := range reminders {
+		got = append(got, due{userID: reminder.UserID, minutes: reminder.Minutes})
+	}
+	assert.Equal(t, []due{{userID: first, minutes: 60}, {userID: second, minutes: 10}}, got)
+
+	// an empty schedule opts out
+	require.NoError(t, st.SetUserReminders(ctx, e.ID, second, []int{}))
+	minutes, err = st.UserReminders(ctx, e.ID, second)
+	require.NoError(t, err)
+	assert.Empty(t, minutes)
+	reminders, err = st.DueReminders(ctx, date.Add(-5*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, first, reminders[0].UserID)
+
+	require.NoError(t, st.DellUserReminders(ctx, e.ID, second))
+	minutes, err = st.UserReminders(ctx, e.ID, second)
+	require.NoError(t, err)
+	assert.Nil(t, minutes)
+
+	// moving the event sends its reminders again
+	moved := *e
+	moved.Date = date.Add(time.Hour)
+	require.NoError(t, st.UpdateEvent(ctx, organizer, &moved, nil, gen))
+	reminders, err = st.DueReminders(ctx, moved.Date.Add(-5*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Len(t, reminders, 4)
+
+	// a cancelled registration takes its reminders along
+	_, err = st.DellEventUser(ctx, e.ID, first, gen)
+	require.NoError(t, err)
+	reminders, err = st.DueReminders(ctx, moved.Date.Add(-5*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Len(t, reminders, 2)
+
+	// registering again is a new registration
+	register(t, st, gen, e, first)
+	reminders, err = st.DueReminders(ctx, moved.Date.Add(-5*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, reminders, 4)
+	assert.Equal(t, first, reminders[0].UserID)
+	assert.NotEqual(t, record, reminders[0].RecordID)
+
+	next, err = st.NextReminder(ctx, moved.Date.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, next.IsZero())
+}
+
+func testOutbox(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	at := now()
+
+	userID := newUser(t, st, "ivan")
+	mail := func(key, channel string) entity.OutboxMail {
+		return entity.OutboxMail{
+			Key:     key,
+			UserID:  userID,
+			EventID: 1,
+			Channel: channel,
+			Address: "ivan@mail.ru",
+			Subject: "Subject " + key,
+			Body:    "<p>Body</p>",
+			Text:    "Body",
+		}
+	}
+
+	require.NoError(t, st.EnqueueMail(ctx, []entity.OutboxMail{
+		mail("first", entity.ChannelEmail),
+		mail("first", entity.ChannelWebhook),
+		mail("second", entity.ChannelEmail),
+	}))
+	// a notification is queued once per channel
+	require.NoError(t, st.EnqueueMail(ctx, []entity.OutboxMail{mail("first", entity.ChannelEmail)}))
+
+	mails, total, err := st.OutboxMails(ctx, entity.OutboxPending, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, mails, 3)
+	assert.Equal(t, "second", mails[0].Key)
+
+	claimed, err := st.ClaimMail(ctx, 10, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, claimed, 3)
+	for _, mail := range claimed {
+		assert.Equal(t, entity.OutboxPending, mail.Status)
+		assert.Equal(t, "ivan@mail.ru", mail.Address)
+		assert.Equal(t, "Body", mail.Text)
+	}
+
+	// claimed emails are leased
+	again, err := st.ClaimMail(ctx, 10, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	ids := make(map[string]int)
+	for _, mail := range claimed {
+		ids[mail.Key+"/"+mail.Channel] = mail.ID
+	}
+
+	require.NoError(t, st.MailSent(ctx, ids["first/email"]))
+	require.NoError(t, st.MailRetry(ctx, ids["first/webhook"], "timeout", at.Add(48*time.Hour)))
+	require.NoError(t, st.MailDead(ctx, ids["second/email"], "bounced"))
+
+	mails, total, err = st.OutboxMails(ctx, entity.OutboxSent, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, mails, 1)
+	assert.Equal(t, 1, mails[0].Attempts)
+
+	mails, total, err = st.OutboxMails(ctx, entity.OutboxPending, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, mails, 1)
+	assert.Equal(t, "timeout", mails[0].LastError)
+	assert.Equal(t, 1, mails[0].Attempts)
+	assert.True(t, at.Add(48*time.Hour).Equal(mails[0].NextAttemptAt))
+
+	mails, total, err = st.OutboxMails(ctx, entity.OutboxDead, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, mails, 1)
+	assert.Equal(t, "bounced", mails[0].LastError)
+
+	mails, total, err = st.OutboxMails(ctx, entity.OutboxPending, 10, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Empty(t, mails)
+
+	assertConflict(t, st.ReplayMail(ctx, ids["first/email"]))
+	assertNotFound(t, st.ReplayMail(ctx, ids["second/email"]+100))
+	require.NoError(t, st.ReplayMail(ctx, ids["second/email"]))
+
+	claimed, err = st.ClaimMail(ctx, 10, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, ids["second/email"], claimed[0].ID)
+	assert.Equal(t, 0, claimed[0].Attempts)
+}
+
+func testDigests(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	gen := &generator{}
+	at := now()
+	every := time.Hour
+
+	organizer := newUser(t, st, "organizer")
+	silent := newUser(t, st, "silent")
+	first := newUser(t, st, "first")
+	second := newUser(t, st, "second")
+
+	require.NoError(t, st.SetSignupDigest(ctx, organizer, true))
+	early := newEvent(t, st, organizer, 10, at.Add(24*time.Hour))
+	late := newEvent(t, st, organizer, 1, at.Add(48*time.Hour))
+	quiet := newEvent(t, st, silent, 10, at.Add(24*time.Hour))
+
+	register(t, st, gen, late, first)
+	register(t, st, gen, late, second)
+	register(t, st, gen, early, first)
+	register(t, st, gen, early, second)
+	register(t, st, gen, quiet, first)
+
+	// a cancellation is no signup, the promotion is
+	_, err := st.DellEventUser(ctx, late.ID, first, gen)
+	require.NoError(t, err)
+
+	digests, err := st.DueDigests(ctx, at.Add(48*time.Hour), every)
+	require.NoError(t, err)
+	require.Len(t, digests, 1)
+	digest := digests[0]
+	assert.Equal(t, organizer, digest.UserID)
+	assert.Equal(t, "organizer@mail.ru", digest.Mail)
+	assert.Equal(t, entity.DefaultLocale, digest.Locale)
+	assert.Equal(t, []entity.DigestEvent{
+		{
+			EventID:         early.ID,
+			Title:           "Meetup",
+			Date:            digest.Events[0].Date,
+			Signups:         2,
+			Participants:    2,
+			MaxParticipants: 10,
+		},
+		{
+			EventID:         late.ID,
+			Title:           "Meetup",
+			Date:            digest.Events[1].Date,
+			Signups:         2,
+			Participants:    1,
+			MaxParticipants: 1,
+		},
+	}, digest.Events)
+	assert.True(t, early.Date.Equal(digest.Events[0].Date))
+
+	require.NoError(t, st.DigestSent(ctx, organizer, at.Add(48*time.Hour)))
+	digests, err = st.DueDigests(ctx, at.Add(96*time.Hour), every)
+	require.NoError(t, err)
+	assert.Empty(t, digests)
+
+	// a digest turned on later starts from that moment
+	require.NoError(t, st.SetSignupDigest(ctx, silent, true))
+	digests, err = st.DueDigests(ctx, at.Add(96*time.Hour), every)
+	require.NoError(t, err)
+	assert.Empty(t, digests)
+}
@@ -0,0 +1,148 @@
+// Package storagetest is the conformance suite of storage.Storage. Every
+// implementation must pass it, so the in-memory storage keeps the semantics
+// of Postgres: unique registrations, capacity limits, cascade deletes and
+// the typed errors of package storage.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Opener returns an empty storage that keeps images in ost. It is called
+// once per test.
+type Opener func(t *testing.T, ost ostorage.BlobStore) storage.Storage
+
+type test struct {
+	name string
+	run  func(t *testing.T, st storage.Storage, ost ostorage.BlobStore)
+}
+
+// Run runs the suite against the storages returned by open. Times are
+// compared with a margin of a day where the storage takes the current time
+// itself, so the time zone of a database server does not matter.
+func Run(t *testing.T, open Opener) {
+	var tests []test
+	tests = append(tests, userTests...)
+	tests = append(tests, eventTests...)
+	tests = append(tests, notificationTests...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ost := ostorage.NewMemory()
+			st := open(t, ost)
+			t.Cleanup(func() { assert.NoError(t, st.Close()) })
+
+			tt.run(t, st, ost)
+		})
+	}
+}
+
+// generator signs tickets with unique tokens.
+type generator struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (g *generator) Generate(tick *entity.Ticket) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.n++
+	tick.Token = fmt.Sprintf("token-%d-%d-%d", tick.EventID, tick.UserID, g.n)
+	return nil
+}
+
+// failing is a generator that can not sign tickets.
+type failing struct{}
+
+func (failing) Generate(*entity.Ticket) error {
+	return errors.New("cannot sign ticket")
+}
+
+// now is the current time with the precision every storage keeps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func newUser(t *testing.T, st storage.Storage, login string) int {
+	t.Helper()
+
+	id, err := st.SetUser(context.Background(), login, "hash", login+"@mail.ru", entity.RoleAttendee)
+	require.NoError(t, err)
+	require.NotZero(t, id)
+
+	return id
+}
+
+func newEvent(t *testing.T, st storage.Storage, userID, maxParticipants int, date time.Time) *entity.Event {
+	t.Helper()
+
+	e := &entity.Event{
+		UserID:          userID,
+		Title:           "Meetup",
+		Description:     "Talks and pizza",
+		Place:           "Moscow",
+		MaxParticipants: maxParticipants,
+		Date:            date,
+		Active:          true,
+	}
+	require.NoError(t, st.CreateEvent(context.Background(), e))
+	require.NotZero(t, e.ID)
+
+	return e
+}
+
+// register adds the user to the event, it returns the position on the
+// waitlist, zero when the user got a seat.
+func register(t *testing.T, st storage.Storage, gen storage.TicketGenerator, e *entity.Event, userID int) int {
+	t.Helper()
+
+	tick := &entity.Ticket{UserID: userID, EventID: e.ID, Exp: int(time.Until(e.Date).Hours())}
+	require.NoError(t, gen.Generate(tick))
+
+	position, err := st.AddEventUser(context.Background(), tick)
+	require.NoError(t, err)
+
+	return position
+}
+
+func participants(t *testing.T, st storage.Storage, eventID int) int {
+	t.Helper()
+
+	e, err := st.GetEvent(context.Background(), eventID)
+	require.NoError(t, err)
+
+	return e.Participants
+}
+
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+
+	var notFound *storage.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func assertConflict(t *testing.T, err error) {
+	t.Helper()
+
+	var conflict *storage.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+}
+
+func assertForbidden(t *testing.T, err error) {
+	t.Helper()
+
+	var forbidden *storage.ForbiddenError
+	assert.ErrorAs(t, err, &forbidden)
+}
@@ -0,0 +1,236 @@
+package storagetest
+
+import (
+	"context"
+	"graduation/internal/entity"
+	"graduation/internal/ostorage"
+	"graduation/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userTests = []test{
+	{name: "users", run: testUsers},
+	{name: "password reset", run: testPasswordReset},
+	{name: "sessions", run: testSessions},
+	{name: "calendar and channels", run: testCalendarChannels},
+}
+
+func testUsers(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+
+	id := newUser(t, st, "ivan")
+
+	_, err := st.SetUser(ctx, "ivan", "other", "other@mail.ru", entity.RoleAttendee)
+	assertConflict(t, err)
+
+	user, err := st.GetUser(ctx, "ivan")
+	require.NoError(t, err)
+	assert.Equal(t, &entity.User{
+		ID:       id,
+		Login:    "ivan",
+		Password: "hash",
+		Mail:     "ivan@mail.ru",
+		Role:     entity.RoleAttendee,
+	}, user)
+
+	_, err = st.GetUser(ctx, "petr")
+	assertNotFound(t, err)
+
+	sessionID, err := st.CreateSession(ctx, id, "refresh", now().Add(24*time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, st.UpdatePassword(ctx, id, "new"))
+	require.NoError(t, st.SetRole(ctx, "ivan", entity.RoleOrganizer))
+	assertNotFound(t, st.SetRole(ctx, "petr", entity.RoleOrganizer))
+
+	// tokens with the old role stop working
+	active, err := st.SessionActive(ctx, sessionID)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	user, err = st.GetUser(ctx, "ivan")
+	require.NoError(t, err)
+	assert.Equal(t, "new", user.Password)
+	assert.Equal(t, entity.RoleOrganizer, user.Role)
+
+	mail, err := st.GetMail(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "ivan@mail.ru", mail)
+
+	locale, err := st.GetLocale(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DefaultLocale, locale)
+
+	require.NoError(t, st.SetLocale(ctx, id, entity.LocaleRU))
+	locale, err = st.GetLocale(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, entity.LocaleRU, locale)
+
+	assertNotFound(t, st.VerifyMail(ctx, id, "old@mail.ru"))
+	verified, err := st.MailVerified(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, verified)
+
+	require.NoError(t, st.VerifyMail(ctx, id, "ivan@mail.ru"))
+	verified, err = st.MailVerified(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, verified)
+}
+
+func testPasswordReset(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	at := now()
+
+	id := newUser(t, st, "ivan")
+	sessionID, err := st.CreateSession(ctx, id, "refresh", at.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, st.CreatePasswordReset(ctx, id, "first", at.Add(48*time.Hour)))
+	require.NoError(t, st.CreatePasswordReset(ctx, id, "second", at.Add(48*time.Hour)))
+	require.NoError(t, st.CreatePasswordReset(ctx, id, "expired", at.Add(-48*time.Hour)))
+
+	assertNotFound(t, st.ResetPassword(ctx, "expired", "new"))
+	assertNotFound(t, st.ResetPassword(ctx, "unknown", "new"))
+
+	require.NoError(t, st.ResetPassword(ctx, "first", "new"))
+
+	user, err := st.GetUser(ctx, "ivan")
+	require.NoError(t, err)
+	assert.Equal(t, "new", user.Password)
+	assert.True(t, user.MailVerified)
+
+	active, err := st.SessionActive(ctx, sessionID)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	// a reset uses up every token of the user
+	assertNotFound(t, st.ResetPassword(ctx, "first", "again"))
+	assertNotFound(t, st.ResetPassword(ctx, "second", "again"))
+}
+
+func testSessions(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+	at := now()
+
+	id := newUser(t, st, "ivan")
+
+	sessionID, err := st.CreateSession(ctx, id, "first", at.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	active, err := st.SessionActive(ctx, sessionID)
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	session, err := st.RefreshSession(ctx, "first", "second", at.Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, session.ID)
+	assert.Equal(t, id, session.UserID)
+	assert.Equal(t, entity.RoleAttendee, session.Role)
+	assert.True(t, at.Add(48*time.Hour).Equal(session.ExpiresAt))
+
+	// refresh tokens are used once
+	_, err = st.RefreshSession(ctx, "first", "third", at.Add(48*time.Hour))
+	assertNotFound(t, err)
+
+	require.NoError(t, st.RevokeSession(ctx, sessionID))
+	active, err = st.SessionActive(ctx, sessionID)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	_, err = st.RefreshSession(ctx, "second", "third", at.Add(48*time.Hour))
+	assertNotFound(t, err)
+
+	expiredID, err := st.CreateSession(ctx, id, "expired", at.Add(-24*time.Hour))
+	require.NoError(t, err)
+	active, err = st.SessionActive(ctx, expiredID)
+	require.NoError(t, err)
+	assert.False(t, active)
+	_, err = st.RefreshSession(ctx, "expired", "third", at.Add(48*time.Hour))
+	assertNotFound(t, err)
+
+	active, err = st.SessionActive(ctx, expiredID+100)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	first, err := st.CreateSession(ctx, id, "a", at.Add(24*time.Hour))
+	require.NoError(t, err)
+	second, err := st.CreateSession(ctx, id, "b", at.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, st.RevokeUserSessions(ctx, id))
+	for _, sessionID := range []int{first, second} {
+		active, err := st.SessionActive(ctx, sessionID)
+		require.NoError(t, err)
+		assert.False(t, active)
+	}
+}
+
+func testCalendarChannels(t *testing.T, st storage.Storage, _ ostorage.BlobStore) {
+	ctx := context.Background()
+
+	id := newUser(t, st, "ivan")
+
+	_, err := st.CalendarUser(ctx, "calendar")
+	assertNotFound(t, err)
+
+	require.NoError(t, st.SetCalendarToken(ctx, id, "calendar"))
+	userID, err := st.CalendarUser(ctx, "calendar")
+	require.NoError(t, err)
+	assert.Equal(t, id, userID)
+
+	// a new token replaces the old one
+	require.NoError(t, st.SetCalendarToken(ctx, id, "rotated"))
+	_, err = st.CalendarUser(ctx, "calendar")
+	assertNotFound(t, err)
+
+	channels, err := st.UserChannels(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+
+	require.NoError(t, st.SetUserChannels(ctx, id, []entity.UserChannel{
+		{Channel: entity.ChannelWebhook, Address: "https://example.com/hook"},
+		{Channel: entity.ChannelEmail},
+	}))
+	require.NoError(t, st.SetUserChannels(ctx, id, []entity.UserChannel{
+		{Channel: entity.ChannelWebhook, Address: "https://example.com/new"},
+		{Channel: entity.ChannelBot, Address: "42"},
+	}))
+
+	channels, err = st.UserChannels(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.UserChannel{
+		{Channel: entity.ChannelBot, Address: "42"},
+		{Channel: entity.ChannelWebhook, Address: "https://example.com/new"},
+	}, channels)
+
+	// an email address of its own waits for the verification link
+	work := []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "work@mail.ru"}}
+	require.NoError(t, st.SetUserChannels(ctx, id, work))
+	channels, err = st.UserChannels(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assert.True(t, channels[0].Unverified())
+
+	assertNotFound(t, st.VerifyMail(ctx, id, "other@mail.ru"))
+	require.NoError(t, st.VerifyMail(ctx, id, "work@mail.ru"))
+	verified, err := st.MailVerified(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, verified)
+
+	// the address stays verified while it is kept
+	require.NoError(t, st.SetUserChannels(ctx, id, append(work, entity.UserChannel{Channel: entity.ChannelBot, Address: "42"})))
+	channels, err = st.UserChannels(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.UserChannel{
+		{Channel: entity.ChannelBot, Address: "42"},
+		{Channel: entity.ChannelEmail, Address: "work@mail.ru", Verified: true},
+	}, channels)
+
+	require.NoError(t, st.SetUserChannels(ctx, id, []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "home@mail.ru"}}))
+	channels, err = st.UserChannels(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.UserChannel{{Channel: entity.ChannelEmail, Address: "home@mail.ru"}}, channels)
+}
@@ -38,11 +38,11 @@ func addRecord(ctx context.Context, tx *sql.Tx, eventID, userID int) error {
 			switch pgErr.Code {
 			case pgerrcode.UniqueViolation:
 				if pgErr.ConstraintName == "record_event_id_user_id_key" {
-					return &RepError{Err: err, UniqueViolation: true}
+					return &ConflictError{Err: fmt.Errorf("user %d already registered for event %d", userID, eventID)}
 				}
 			case pgerrcode.ForeignKeyViolation:
 				if pgErr.ConstraintName == "record_event_id_fkey" {
-					return &RepError{Err: err, ForeignKeyViolation: true}
+					return &NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
 				}
 			default:
 				return fmt.Errorf("cannot INSERT record: %w", err)
@@ -72,8 +72,26 @@ func addCountUser(ctx context.Context, tx *sql.Tx, eventID int) error {
 	return nil
 }
 
-func (s *storageData) AddEventUser(ctx context.Context, tick *entity.Ticket) error {
+func (s *storageData) AddEventUser(ctx context.Context, tick *entity.Ticket) (int, error) {
+	var position int
 	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		full, err := lockEvent(ctx, tx, tick.EventID)
+		if err != nil {
+			return fmt.Errorf("cannot lockEvent: %w", err)
+		}
+
+		if err := checkRecord(ctx, tx, tick.EventID, tick.UserID); err != nil {
+			return fmt.Errorf("cannot checkRecord: %w", err)
+		}
+
+		if full {
+			position, err = addWaitlist(ctx, tx, tick.EventID, tick.UserID)
+			if err != nil {
+				return fmt.Errorf("cannot addWaitlist: %w", err)
+			}
+			return nil
+		}
+
 		if err := addRecord(ctx, tx, tick.EventID, tick.UserID); err != nil {
 			return fmt.Errorf("cannot addRecord: %w", err)
 		}
@@ -86,12 +104,12 @@ func (s *storageData) AddEventUser(ctx context.Context, tick *entity.Ticket) err
 			return fmt.Errorf("cannot creatTicket: %w", err)
 		}
 
-		return nil
+		return addNotice(ctx, tx, tick.EventID, tick.UserID, entity.NoticeRegistration)
 	})
 
 	if err != nil {
-		return fmt.Errorf("cannot add evnt user: %w", err)
+		return 0, fmt.Errorf("cannot add evnt user: %w", err)
 	}
 
-	return nil
+	return position, nil
 }
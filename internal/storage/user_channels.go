@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graduation/internal/entity"
+	"strings"
+)
+
+// UserChannels returns the channels the user picked, none when the user
+// never set them.
+func (s *storageData) UserChannels(ctx context.Context, userID int) ([]entity.UserChannel, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT channel, address, verified
+		FROM user_channel
+		WHERE user_id = $1
+		ORDER BY channel
+	`, userID)
+	if err != nil || rows.Err() != nil {
+		return nil, fmt.Errorf("cannot get user channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []entity.UserChannel
+	for rows.Next() {
+		var channel entity.UserChannel
+		if err := rows.Scan(&channel.Channel, &channel.Address, &channel.Verified); err != nil {
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+// SetUserChannels replaces the channels of the user. A channel that keeps
+// its address stays verified, a new address has to be verified again.
+func (s *storageData) SetUserChannels(ctx context.Context, userID int, channels []entity.UserChannel) error {
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		names = append(names, channel.Channel)
+	}
+
+	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM user_channel
+			WHERE user_id = $1 AND channel <> ALL(string_to_array($2, ','))
+		`, userID, strings.Join(names, ","))
+		if err != nil {
+			return fmt.Errorf("cannot dell user channels: %w", err)
+		}
+
+		for _, channel := range channels {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO user_channel (user_id, channel, address)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (user_id, channel) DO UPDATE
+					SET address = EXCLUDED.address,
+						verified = user_channel.verified AND user_channel.address = EXCLUDED.address
+			`, userID, channel.Channel, channel.Address)
+			if err != nil {
+				return fmt.Errorf("cannot INSERT user channel: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("cannot set user channels: %w", err)
+	}
+
+	return nil
+}
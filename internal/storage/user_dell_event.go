@@ -3,13 +3,21 @@ package storage
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"graduation/internal/entity"
 )
 
+// dellTicket keeps the token of the deleted ticket in ticket_revocation, so
+// offline bundles can tell scanners the ticket is no longer valid.
 func dellTicket(ctx context.Context, tx *sql.Tx, userID, eventID int) error {
 	_, err := tx.ExecContext(ctx, `
-		DELETE FROM ticket WHERE user_id = $1 AND event_id = $2
+		WITH deleted AS (
+			DELETE FROM ticket WHERE user_id = $1 AND event_id = $2
+			RETURNING token, event_id
+		)
+		INSERT INTO ticket_revocation (token, event_id)
+			SELECT token, event_id FROM deleted
+		ON CONFLICT (token) DO NOTHING
 	`, userID, eventID)
 	if err != nil {
 		return fmt.Errorf("cannot dell ticket: %w", err)
@@ -39,7 +47,7 @@ func dellRecoed(ctx context.Context, tx *sql.Tx, userID, eventID int) error {
 				WHERE id = $1
 			`, eventID).Scan(&flag)
 		if err != nil {
-			return &RepError{Err: fmt.Errorf("cannot SELECT event: %w", err), ForeignKeyViolation: true}
+			return &NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
 		}
 
 		err = tx.QueryRowContext(ctx, `
@@ -47,10 +55,10 @@ func dellRecoed(ctx context.Context, tx *sql.Tx, userID, eventID int) error {
 				WHERE user_id = $1 AND event_id = $2
 			`, userID, eventID).Scan(&flag)
 		if err != nil {
-			return &RepError{Err: fmt.Errorf("cannot SELECT record: %w", err), UniqueViolation: true}
+			return &ConflictError{Err: fmt.Errorf("user %d not registered for event %d", userID, eventID)}
 		}
 
-		return errors.New("0 DELETE")
+		return &ConflictError{Err: fmt.Errorf("user %d not registered for event %d", userID, eventID)}
 	}
 
 	return nil
@@ -75,8 +83,16 @@ func dellCountUser(ctx context.Context, tx *sql.Tx, eventID int) error {
 	return nil
 }
 
-func (s *storageData) DellEventUser(ctx context.Context, eventID, userID int) error {
+func (s *storageData) DellEventUser(ctx context.Context, eventID, userID int, gen TicketGenerator) (*entity.Ticket, error) {
+	var promoted *entity.Ticket
 	err := s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		waiting, err := dellWaitlist(ctx, tx, eventID, userID)
+		if err != nil {
+			return fmt.Errorf("cannot dell waitlist: %w", err)
+		}
+		if waiting {
+			return nil
+		}
 
 		if err := dellRecoed(ctx, tx, userID, eventID); err != nil {
 			return fmt.Errorf("cannot dell record: %w", err)
@@ -90,12 +106,27 @@ func (s *storageData) DellEventUser(ctx context.Context, eventID, userID int) er
 			return fmt.Errorf("cannot dell ticket: %w", err)
 		}
 
+		if err := addNotice(ctx, tx, eventID, userID, entity.NoticeCancellation); err != nil {
+			return err
+		}
+
+		promoted, err = promoteWaitlist(ctx, tx, eventID, gen)
+		if err != nil {
+			return fmt.Errorf("cannot promote waitlist: %w", err)
+		}
+
+		if promoted != nil {
+			if err := addNotice(ctx, tx, eventID, promoted.UserID, entity.NoticePromotion); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 
 	if err != nil {
-		return fmt.Errorf("cannot dell: %w", err)
+		return nil, fmt.Errorf("cannot dell: %w", err)
 	}
 
-	return nil
+	return promoted, nil
 }
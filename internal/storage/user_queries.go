@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"graduation/internal/entity"
@@ -10,18 +11,18 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (s *storageData) SetUser(ctx context.Context, login, password, mail string) (int, error) {
+func (s *storageData) SetUser(ctx context.Context, login, password, mail, role string) (int, error) {
 	var id int
 	err := s.db.QueryRowContext(ctx, `
-		INSERT INTO users (login, password, mail)
-		VALUES ($1, $2, $3)
+		INSERT INTO users (login, password, mail, role)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id
-	`, login, password, mail).Scan(&id)
+	`, login, password, mail, role).Scan(&id)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgerrcode.IsIntegrityConstraintViolation(pgErr.Code) {
-			return 0, &RepError{Err: err, Repetition: true}
+			return 0, &ConflictError{Err: fmt.Errorf("user %s already exists", login)}
 		}
 		return 0, fmt.Errorf("cannot set database: %w", err)
 	}
@@ -29,19 +30,66 @@ func (s *storageData) SetUser(ctx context.Context, login, password, mail string)
 	return id, nil
 }
 
-func (s *storageData) GetUser(ctx context.Context, login, password string) (int, error) {
+func (s *storageData) GetUser(ctx context.Context, login string) (*entity.User, error) {
 	row := s.db.QueryRowContext(ctx, `
-		SELECT id 
-		FROM users WHERE login = $1 AND password = $2;
-	`, login, password)
+		SELECT id, login, password, mail, role, mail_verified
+		FROM users WHERE login = $1;
+	`, login)
 
-	var id int
-	err := row.Scan(&id)
+	user := &entity.User{}
+	err := row.Scan(&user.ID, &user.Login, &user.Password, &user.Mail, &user.Role, &user.MailVerified)
 	if err != nil {
-		return 0, fmt.Errorf("cannot scan: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Err: fmt.Errorf("user %s not exist", login)}
+		}
+		return nil, fmt.Errorf("cannot scan: %w", err)
 	}
 
-	return id, nil
+	return user, nil
+}
+
+func (s *storageData) UpdatePassword(ctx context.Context, userID int, password string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE users
+			SET password = $1
+			WHERE id = $2
+	`, password, userID)
+	if err != nil {
+		return fmt.Errorf("cannot update password: %w", err)
+	}
+
+	return nil
+}
+
+// SetRole changes the role of the user and revokes every session, access
+// tokens carry the role and must not outlive it.
+func (s *storageData) SetRole(ctx context.Context, login, role string) error {
+	return s.inTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var userID int
+		err := tx.QueryRowContext(ctx, `
+			UPDATE users
+				SET role = $1
+				WHERE login = $2
+			RETURNING id
+		`, role, login).Scan(&userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &NotFoundError{Err: fmt.Errorf("user %s not exist", login)}
+			}
+			return fmt.Errorf("cannot update role: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE sessions
+				SET revoked = true
+				WHERE user_id = $1
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("cannot revoke sessions: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func (s *storageData) GetUserEvents(ctx context.Context, userID int) ([]entity.Event, error) {
@@ -74,13 +122,10 @@ func (s *storageData) GetUserEvents(ctx context.Context, userID int) ([]entity.E
 			return nil, fmt.Errorf("cannot scan: %w", err)
 		}
 
-		urls, err := s.GetImages(ctx, event.ID)
+		event.Images, err = s.GetImages(ctx, event.ID)
 		if err != nil {
 			return nil, fmt.Errorf("cannot get images: %w", err)
 		}
-		for _, url := range urls {
-			event.Images = append(event.Images, entity.Image{Filename: url})
-		}
 
 		events = append(events, event)
 	}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graduation/internal/entity"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// lockEvent takes the event row lock for the rest of the transaction, so the
+// capacity check and the seat update can not race with other registrations.
+func lockEvent(ctx context.Context, tx *sql.Tx, eventID int) (bool, error) {
+	var participants, maxParticipants int
+	var active bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT participants, max_participants, active
+		FROM event
+		WHERE id = $1
+		FOR UPDATE
+	`, eventID).Scan(&participants, &maxParticipants, &active)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, &NotFoundError{Err: fmt.Errorf("event %d not exist", eventID)}
+		}
+		return false, fmt.Errorf("cannot SELECT event: %w", err)
+	}
+
+	if !active {
+		return false, &ConflictError{Err: fmt.Errorf("event %d is closed", eventID)}
+	}
+
+	return participants >= maxParticipants, nil
+}
+
+func checkRecord(ctx context.Context, tx *sql.Tx, eventID, userID int) error {
+	var flag bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT 1 FROM record
+		WHERE event_id = $1 AND user_id = $2
+	`, eventID, userID).Scan(&flag)
+	if err == nil {
+		return &ConflictError{Err: fmt.Errorf("user %d already registered for event %d", userID, eventID)}
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("cannot SELECT record: %w", err)
+	}
+
+	return nil
+}
+
+func addWaitlist(ctx context.Context, tx *sql.Tx, eventID, userID int) (int, error) {
+	var id int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO waitlist (event_id, user_id)
+		VALUES ($1, $2)
+		RETURNING id
+	`, eventID, userID).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return 0, &ConflictError{Err: fmt.Errorf("user %d already on the waitlist of event %d", userID, eventID)}
+		}
+		return 0, fmt.Errorf("cannot INSERT waitlist: %w", err)
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM waitlist
+		WHERE event_id = $1 AND id <= $2
+	`, eventID, id).Scan(&position)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get position: %w", err)
+	}
+
+	return position, nil
+}
+
+func dellWaitlist(ctx context.Context, tx *sql.Tx, eventID, userID int) (bool, error) {
+	rows, err := tx.ExecContext(ctx, `
+		DELETE FROM waitlist WHERE event_id = $1 AND user_id = $2
+	`, eventID, userID)
+	if err != nil {
+		return false, fmt.Errorf("cannot dell waitlist: %w", err)
+	}
+
+	rowsAffected, err := rows.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cannot get rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// promoteWaitlist moves the first user of the waitlist into the freed seat and
+// issues the ticket for him. It returns nil when nobody is waiting.
+func promoteWaitlist(ctx context.Context, tx *sql.Tx, eventID int, gen TicketGenerator) (*entity.Ticket, error) {
+	var id, userID int
+	var date time.Time
+	err := tx.QueryRowContext(ctx, `
+		SELECT waitlist.id, waitlist.user_id, event.date
+		FROM waitlist
+		JOIN event ON event.id = waitlist.event_id
+		WHERE waitlist.event_id = $1
+		AND event.active = true
+		AND event.participants < event.max_participants
+		ORDER BY waitlist.id
+		LIMIT 1
+		FOR UPDATE OF waitlist
+	`, eventID).Scan(&id, &userID, &date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot SELECT waitlist: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM waitlist WHERE id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("cannot dell waitlist: %w", err)
+	}
+
+	if err := addRecord(ctx, tx, eventID, userID); err != nil {
+		return nil, fmt.Errorf("cannot addRecord: %w", err)
+	}
+
+	if err := addCountUser(ctx, tx, eventID); err != nil {
+		return nil, fmt.Errorf("cannot addCountUser: %w", err)
+	}
+
+	tick := &entity.Ticket{
+		UserID:  userID,
+		EventID: eventID,
+		Exp:     int(time.Until(date).Hours() + 0.5),
+	}
+
+	if err := gen.Generate(tick); err != nil {
+		return nil, fmt.Errorf("cannot generate ticket: %w", err)
+	}
+
+	if err := creatTicket(ctx, tx, tick); err != nil {
+		return nil, fmt.Errorf("cannot creatTicket: %w", err)
+	}
+
+	return tick, nil
+}
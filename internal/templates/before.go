@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"fmt"
+	"graduation/internal/entity"
+)
+
+// units are the time units of a reminder offset, largest first, with their
+// names: English singular and plural, Russian one, few and many.
+var units = []struct {
+	minutes int
+	en      [2]string
+	ru      [3]string
+}{
+	{7 * 24 * 60, [2]string{"week", "weeks"}, [3]string{"неделю", "недели", "недель"}},
+	{24 * 60, [2]string{"day", "days"}, [3]string{"день", "дня", "дней"}},
+	{60, [2]string{"hour", "hours"}, [3]string{"час", "часа", "часов"}},
+	{1, [2]string{"minute", "minutes"}, [3]string{"минуту", "минуты", "минут"}},
+}
+
+// before names an offset of minutes in the largest unit it is a whole
+// number of: "3 hours", "1 день".
+func before(locale string, minutes int) string {
+	for _, unit := range units {
+		if minutes%unit.minutes != 0 {
+			continue
+		}
+
+		count := minutes / unit.minutes
+		if locale == entity.LocaleRU {
+			return fmt.Sprintf("%d %s", count, pluralRU(count, unit.ru))
+		}
+
+		if count == 1 {
+			return fmt.Sprintf("%d %s", count, unit.en[0])
+		}
+		return fmt.Sprintf("%d %s", count, unit.en[1])
+	}
+
+	return fmt.Sprintf("%d", minutes)
+}
+
+func pluralRU(n int, forms [3]string) string {
+	n10, n100 := n%10, n%100
+	switch {
+	case n10 == 1 && n100 != 11:
+		return forms[0]
+	case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+		return forms[1]
+	}
+	return forms[2]
+}
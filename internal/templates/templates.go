@@ -0,0 +1,180 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Names of the notification templates. Each one is three files in a locale
+// directory: <name>.subject, <name>.html and <name>.txt.
+const (
+	Reminder     = "reminder"
+	Registration = "registration"
+	Cancellation = "cancellation"
+	Changed      = "changed"
+	Promotion    = "promotion"
+	Digest       = "digest"
+	Verify       = "verify"
+	Reset        = "reset"
+)
+
+var names = []string{Reminder, Registration, Cancellation, Changed, Promotion, Digest, Verify, Reset}
+
+//go:embed default
+var defaults embed.FS
+
+// Data is what templates get: the event and, depending on the template,
+// how many minutes before it a reminder is, the changed fields and the
+// ticket. The digest gets only Digest, account mails get Link and the
+// password reset also Token.
+type Data struct {
+	Event   *entity.Event
+	Minutes int
+	Changes []entity.EventChange
+	Token   string
+	Digest  *entity.Digest
+	Link    string
+}
+
+// Message is a rendered template, Text carries the same message as HTML.
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+type set struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+// Registry holds the templates of every locale.
+type Registry struct {
+	sets map[string]set
+}
+
+func Init(conf *config.Templates) (*Registry, error) {
+	return New(conf.TemplatesDir)
+}
+
+// New loads the built-in templates, a file with the same path in dir
+// replaces the built-in one. dir may be empty.
+func New(dir string) (*Registry, error) {
+	builtin, err := fs.Sub(defaults, "default")
+	if err != nil {
+		return nil, fmt.Errorf("cannot open default templates: %w", err)
+	}
+
+	var override fs.FS
+	if dir != "" {
+		override = os.DirFS(dir)
+	}
+
+	r := &Registry{sets: make(map[string]set)}
+	for _, locale := range entity.Locales {
+		funcs := funcMap(locale)
+		for _, name := range names {
+			read := func(ext string) (string, error) {
+				return readTemplate(builtin, override, path.Join(locale, name+ext))
+			}
+
+			subject, err := read(".subject")
+			if err != nil {
+				return nil, err
+			}
+			html, err := read(".html")
+			if err != nil {
+				return nil, err
+			}
+			text, err := read(".txt")
+			if err != nil {
+				return nil, err
+			}
+
+			var s set
+			if s.subject, err = texttemplate.New(name).Funcs(funcs).Parse(subject); err != nil {
+				return nil, fmt.Errorf("cannot parse %s/%s subject: %w", locale, name, err)
+			}
+			if s.html, err = htmltemplate.New(name).Funcs(funcs).Parse(html); err != nil {
+				return nil, fmt.Errorf("cannot parse %s/%s html: %w", locale, name, err)
+			}
+			if s.text, err = texttemplate.New(name).Funcs(funcs).Parse(text); err != nil {
+				return nil, fmt.Errorf("cannot parse %s/%s text: %w", locale, name, err)
+			}
+			r.sets[locale+"/"+name] = s
+		}
+	}
+
+	return r, nil
+}
+
+func readTemplate(builtin, override fs.FS, name string) (string, error) {
+	if override != nil {
+		data, err := fs.ReadFile(override, name)
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("cannot read template %s: %w", name, err)
+		}
+	}
+
+	data, err := fs.ReadFile(builtin, name)
+	if err != nil {
+		return "", fmt.Errorf("cannot read template %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// Render renders the template name in locale, an unknown locale falls back
+// to entity.DefaultLocale.
+func (r *Registry) Render(locale, name string, data Data) (*Message, error) {
+	if !entity.ValidLocale(locale) {
+		locale = entity.DefaultLocale
+	}
+
+	s, ok := r.sets[locale+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template: %s", name)
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := s.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("cannot execute %s subject: %w", name, err)
+	}
+	if err := s.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("cannot execute %s html: %w", name, err)
+	}
+	if err := s.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("cannot execute %s text: %w", name, err)
+	}
+
+	return &Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
+
+func funcMap(locale string) map[string]any {
+	return map[string]any{
+		"date": func(date time.Time) string {
+			return date.Format("2006-01-02 15:04")
+		},
+		"before": func(minutes int) string {
+			return before(locale, minutes)
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package templates_test
+
+import (
+	"graduation/internal/entity"
+	"graduation/internal/templates"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var event = &entity.Event{
+	ID:              1,
+	Title:           "Jazz <night>",
+	Description:     "Live music",
+	Place:           "Hall 2",
+	Date:            time.Date(2024, 4, 10, 19, 30, 0, 0, time.UTC),
+	Participants:    3,
+	MaxParticipants: 10,
+}
+
+func TestRenderReminder(t *testing.T) {
+	r, err := templates.New("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		locale  string
+		minutes int
+		subject string
+	}{
+		{entity.LocaleEN, 180, "Event in 3 hours: Jazz <night>"},
+		{entity.LocaleEN, 60, "Event in 1 hour: Jazz <night>"},
+		{entity.LocaleEN, 10080, "Event in 1 week: Jazz <night>"},
+		{entity.LocaleRU, 180, "Мероприятие через 3 часа: Jazz <night>"},
+		{entity.LocaleRU, 1440, "Мероприятие через 1 день: Jazz <night>"},
+		{entity.LocaleRU, 5 * 24 * 60, "Мероприятие через 5 дней: Jazz <night>"},
+		{entity.LocaleRU, 21, "Мероприятие через 21 минуту: Jazz <night>"},
+		{entity.LocaleRU, 11 * 60, "Мероприятие через 11 часов: Jazz <night>"},
+		{"de", 90, "Event in 90 minutes: Jazz <night>"},
+	}
+
+	for _, test := range tests {
+		msg, err := r.Render(test.locale, templates.Reminder, templates.Data{Event: event, Minutes: test.minutes})
+		require.NoError(t, err)
+		assert.Equal(t, test.subject, msg.Subject)
+	}
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	r, err := templates.New("")
+	require.NoError(t, err)
+
+	msg, err := r.Render(entity.LocaleEN, templates.Changed, templates.Data{
+		Event:   event,
+		Changes: []entity.EventChange{{Field: "place", Old: "Hall 1", New: "Hall 2"}},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, msg.HTML, "<h1>Jazz &lt;night&gt;</h1>")
+	assert.Contains(t, msg.HTML, `src="cid:ticket-qr.png"`)
+	assert.Contains(t, msg.Text, "Jazz <night>")
+	assert.Contains(t, msg.Text, "place: Hall 1 -> Hall 2")
+	assert.Contains(t, msg.Text, "Date: 2024-04-10 19:30")
+}
+
+func TestRenderAllTemplates(t *testing.T) {
+	r, err := templates.New("")
+	require.NoError(t, err)
+
+	names := []string{templates.Reminder, templates.Registration, templates.Cancellation, templates.Changed, templates.Promotion}
+	for _, locale := range entity.Locales {
+		for _, name := range names {
+			msg, err := r.Render(locale, name, templates.Data{Event: event, Minutes: 60, Token: "token"})
+			require.NoError(t, err, "%s/%s", locale, name)
+			assert.NotEmpty(t, msg.Subject, "%s/%s", locale, name)
+			assert.NotEmpty(t, msg.HTML, "%s/%s", locale, name)
+			assert.NotEmpty(t, msg.Text, "%s/%s", locale, name)
+		}
+	}
+
+	_, err = r.Render(entity.LocaleEN, "unknown", templates.Data{Event: event})
+	assert.Error(t, err)
+}
+
+func TestRenderDigest(t *testing.T) {
+	r, err := templates.New("")
+	require.NoError(t, err)
+
+	digest := &entity.Digest{
+		Since: time.Date(2024, 4, 9, 12, 0, 0, 0, time.UTC),
+		Events: []entity.DigestEvent{
+			{EventID: 1, Title: "Jazz <night>", Date: event.Date, Signups: 2, Participants: 3, MaxParticipants: 10},
+		},
+	}
+
+	msg, err := r.Render(entity.LocaleRU, templates.Digest, templates.Data{Digest: digest})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Новые записи на ваши мероприятия", msg.Subject)
+	assert.Contains(t, msg.HTML, "<td>Jazz &lt;night&gt;</td>")
+	assert.Contains(t, msg.Text, "Jazz <night> (2024-04-10 19:30): +2, 3 / 10")
+}
+
+func TestRenderAccount(t *testing.T) {
+	r, err := templates.New("")
+	require.NoError(t, err)
+
+	for _, locale := range entity.Locales {
+		msg, err := r.Render(locale, templates.Verify, templates.Data{Link: "https://event.ne/api/user/verify?token=a&b"})
+		require.NoError(t, err, locale)
+		assert.Contains(t, msg.HTML, `href="https://event.ne/api/user/verify?token=a&amp;b"`, locale)
+		assert.Contains(t, msg.Text, "https://event.ne/api/user/verify?token=a&b", locale)
+
+		msg, err = r.Render(locale, templates.Reset, templates.Data{Link: "https://event.ne/password/reset?token=code", Token: "code"})
+		require.NoError(t, err, locale)
+		assert.NotEmpty(t, msg.Subject, locale)
+		assert.Contains(t, msg.HTML, "<code>code</code>", locale)
+		assert.Contains(t, msg.Text, "https://event.ne/password/reset?token=code", locale)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, entity.LocaleRU), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, entity.LocaleRU, "reminder.subject"), []byte("Скоро: {{.Event.Title}}\n"), 0o644))
+
+	r, err := templates.New(dir)
+	require.NoError(t, err)
+
+	msg, err := r.Render(entity.LocaleRU, templates.Reminder, templates.Data{Event: event, Minutes: 60})
+	require.NoError(t, err)
+	assert.Equal(t, "Скоро: Jazz <night>", msg.Subject)
+	assert.Contains(t, msg.Text, "через 1 час.")
+
+	msg, err = r.Render(entity.LocaleEN, templates.Reminder, templates.Data{Event: event, Minutes: 60})
+	require.NoError(t, err)
+	assert.Equal(t, "Event in 1 hour: Jazz <night>", msg.Subject)
+}
+
+func TestOverrideNotParsed(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, entity.LocaleEN), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, entity.LocaleEN, "changed.html"), []byte("{{.Event.Title"), 0o644))
+
+	_, err := templates.New(dir)
+	assert.Error(t, err)
+}
@@ -1,24 +1,39 @@
 package ticket
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"graduation/internal/entity"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// expireGrace keeps a ticket valid for a while after the event starts, so
+// late guests can still be checked in.
+const expireGrace = time.Hour * 24
+
 func (t *TicketToken) Generate(tick *entity.Ticket) error {
+	now := time.Now()
 	claims := TicketClaims{
 		UserID:  tick.UserID,
 		EventID: tick.EventID,
-		Exp:     time.Now().Add(time.Hour * time.Duration(tick.Exp)),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(tick.Exp))),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour*time.Duration(tick.Exp) + expireGrace)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(t.secretKey))
+	var (
+		signedToken string
+		err         error
+	)
+	if t.keyring == nil {
+		signedToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(t.secretKey))
+	} else {
+		signedToken, err = t.Sign(claims)
+	}
 	if err != nil {
 		return err
 	}
@@ -27,3 +42,24 @@ func (t *TicketToken) Generate(tick *entity.Ticket) error {
 
 	return nil
 }
+
+// Sign signs any claims with the keyring signing key. Unlike Generate it
+// never falls back to HS256, the result is meant to be checked by third
+// parties that only have the public keys.
+func (t *TicketToken) Sign(claims jwt.Claims) (string, error) {
+	if t.keyring == nil || t.keyring.Signing() == nil {
+		return "", errors.New("signing key not configured")
+	}
+
+	key := t.keyring.Signing()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
+}
+
+// ID identifies a ticket without revealing its token.
+func ID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,23 +1,42 @@
 package ticket
 
 import (
+	"fmt"
 	"graduation/internal/config"
-	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
 type TicketToken struct {
 	secretKey string
+	keyring   *Keyring
 }
 
 type TicketClaims struct {
-	UserID  int       `json:"userID"`
-	EventID int       `json:"eventID"`
-	Exp     time.Time `json:"exp"`
+	UserID  int `json:"userID"`
+	EventID int `json:"eventID"`
 	jwt.RegisteredClaims
 }
 
-func Init(conf *config.TicketKey) *TicketToken {
-	return &TicketToken{secretKey: conf.TicketSecretKey}
+// Init loads the keyring from conf.TicketKeysPath. Without a keyring tickets
+// are signed with HS256 and SECRET_KEY_TICKET as before; with a keyring the
+// secret is only used to verify tickets issued before the migration.
+func Init(conf *config.TicketKey) (*TicketToken, error) {
+	tick := TicketToken{secretKey: conf.TicketSecretKey}
+
+	if conf.TicketKeysPath == "" {
+		return &tick, nil
+	}
+
+	keyring, err := LoadKeyring(conf.TicketKeysPath, conf.TicketSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load keyring: %w", err)
+	}
+	tick.keyring = keyring
+
+	return &tick, nil
+}
+
+func NewWithKeyring(secretKey string, keyring *Keyring) *TicketToken {
+	return &TicketToken{secretKey: secretKey, keyring: keyring}
 }
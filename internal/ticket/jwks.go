@@ -0,0 +1,110 @@
+package ticket
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the public half of every key in the keyring, so door apps
+// can verify tickets offline. Without a keyring the set is empty.
+func (t *TicketToken) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	if t.keyring == nil {
+		return jwks
+	}
+
+	for _, key := range t.keyring.Keys() {
+		jwk := JWK{Kid: key.ID, Alg: key.Method.Alg(), Use: "sig"}
+
+		switch public := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *ecdsa.PublicKey:
+			jwk.Kty = "EC"
+			jwk.Crv = "P-256"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public.X.FillBytes(make([]byte, 32)))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(public.Y.FillBytes(make([]byte, 32)))
+		default:
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode x: %w", err)
+	}
+
+	switch {
+	case j.Kty == "OKP" && j.Crv == "Ed25519":
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("bad Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	case j.Kty == "EC" && j.Crv == "P-256":
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode y: %w", err)
+		}
+		public := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !public.Curve.IsOnCurve(public.X, public.Y) {
+			return nil, errors.New("point not on curve")
+		}
+		return public, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key: %s %s", j.Kty, j.Crv)
+}
+
+// NewVerifier builds a TicketToken that validates tickets against a
+// published key set and cannot issue new ones.
+func NewVerifier(jwks JWKS) (*TicketToken, error) {
+	keys := make([]*Key, 0, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		public, err := jwk.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse key %s: %w", jwk.Kid, err)
+		}
+
+		method, err := methodFor(public)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &Key{ID: jwk.Kid, Method: method, Public: public})
+	}
+
+	keyring, err := NewVerifyKeyring(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TicketToken{keyring: keyring}, nil
+}
@@ -0,0 +1,170 @@
+package ticket
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// Keyring holds one signing key and every key that tickets may still be
+// signed with. Retired keys stay in the keyring as public keys until the
+// last ticket signed with them has expired.
+type Keyring struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+func NewKeyring(signing *Key, keys ...*Key) (*Keyring, error) {
+	if signing == nil || signing.Private == nil {
+		return nil, errors.New("signing key must have a private key")
+	}
+
+	keyring := Keyring{signing: signing, keys: map[string]*Key{signing.ID: signing}}
+	for _, key := range keys {
+		if _, ok := keyring.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate kid: %s", key.ID)
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	return &keyring, nil
+}
+
+// LoadKeyring reads every <kid>.pem file from dir. A file holds either a
+// PKCS#8 private key or a PKIX public key, Ed25519 or ECDSA P-256.
+func LoadKeyring(dir, signingKID string) (*Keyring, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list keys: %w", err)
+	}
+
+	var signing *Key
+	keys := make([]*Key, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", file, err)
+		}
+
+		key, err := ParseKey(strings.TrimSuffix(filepath.Base(file), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", file, err)
+		}
+
+		if key.ID == signingKID {
+			signing = key
+		} else {
+			keys = append(keys, key)
+		}
+	}
+
+	if signing == nil {
+		return nil, fmt.Errorf("signing key %q not found in %s", signingKID, dir)
+	}
+
+	return NewKeyring(signing, keys...)
+}
+
+func ParseKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	key := Key{ID: kid}
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse private key: %w", err)
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("private key cannot sign")
+		}
+		key.Private = signer
+		key.Public = signer.Public()
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse public key: %w", err)
+		}
+		key.Public = public
+	default:
+		return nil, fmt.Errorf("unsupported PEM block: %s", block.Type)
+	}
+
+	method, err := methodFor(key.Public)
+	if err != nil {
+		return nil, err
+	}
+	key.Method = method
+
+	return &key, nil
+}
+
+func methodFor(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch public := public.(type) {
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	case *ecdsa.PublicKey:
+		if public.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 curve is supported")
+		}
+		return jwt.SigningMethodES256, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type: %T", public)
+}
+
+// NewVerifyKeyring builds a keyring without a signing key, used where tickets
+// are only checked, like the offline scanner.
+func NewVerifyKeyring(keys ...*Key) (*Keyring, error) {
+	keyring := Keyring{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, ok := keyring.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate kid: %s", key.ID)
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	return &keyring, nil
+}
+
+func (k *Keyring) Signing() *Key {
+	return k.signing
+}
+
+func (k *Keyring) Key(kid string) (*Key, bool) {
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// Keys returns all keys sorted by kid, the signing key included.
+func (k *Keyring) Keys() []*Key {
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys
+}
@@ -0,0 +1,151 @@
+package ticket_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"graduation/internal/config"
+	"graduation/internal/entity"
+	"graduation/internal/ticket"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func privatePEM(t *testing.T, private interface{}) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicPEM(t *testing.T, public interface{}) []byte {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestTicketToken(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  interface{}
+		alg  string
+	}{
+		{
+			name: "EdDSA",
+			key:  edKey,
+			alg:  "EdDSA",
+		},
+		{
+			name: "ES256",
+			key:  ecKey,
+			alg:  "ES256",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "k1.pem"), privatePEM(t, test.key), 0600))
+
+			tick, err := ticket.Init(&config.TicketKey{TicketKeysPath: dir, TicketSigningKey: "k1"})
+			require.NoError(t, err)
+
+			issued := entity.Ticket{UserID: 2, EventID: 3, Exp: 1}
+			require.NoError(t, tick.Generate(&issued))
+
+			checked := entity.Ticket{Token: issued.Token}
+			assert.NoError(t, tick.Validate(&checked))
+			assert.Equal(t, 2, checked.UserID)
+			assert.Equal(t, 3, checked.EventID)
+
+			jwks := tick.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "k1", jwks.Keys[0].Kid)
+			assert.Equal(t, test.alg, jwks.Keys[0].Alg)
+		})
+	}
+
+	t.Run("rotation", func(t *testing.T) {
+		_, newKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "old.pem"), privatePEM(t, ecKey), 0600))
+
+		old, err := ticket.Init(&config.TicketKey{TicketKeysPath: dir, TicketSigningKey: "old"})
+		require.NoError(t, err)
+
+		issued := entity.Ticket{UserID: 1, EventID: 1, Exp: 1}
+		require.NoError(t, old.Generate(&issued))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "old.pem"), publicPEM(t, ecKey.Public()), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "new.pem"), privatePEM(t, newKey), 0600))
+
+		rotated, err := ticket.Init(&config.TicketKey{TicketKeysPath: dir, TicketSigningKey: "new"})
+		require.NoError(t, err)
+
+		assert.NoError(t, rotated.Validate(&entity.Ticket{Token: issued.Token}))
+		assert.Len(t, rotated.JWKS().Keys, 2)
+
+		_, err = ticket.Init(&config.TicketKey{TicketKeysPath: dir, TicketSigningKey: "old"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown kid", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "k1.pem"), privatePEM(t, edKey), 0600))
+		other := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(other, "k2.pem"), privatePEM(t, ecKey), 0600))
+
+		tick, err := ticket.Init(&config.TicketKey{TicketKeysPath: dir, TicketSigningKey: "k1"})
+		require.NoError(t, err)
+		foreign, err := ticket.Init(&config.TicketKey{TicketKeysPath: other, TicketSigningKey: "k2"})
+		require.NoError(t, err)
+
+		issued := entity.Ticket{UserID: 1, EventID: 1, Exp: 1}
+		require.NoError(t, foreign.Generate(&issued))
+
+		assert.Error(t, tick.Validate(&entity.Ticket{Token: issued.Token}))
+	})
+
+	t.Run("legacy HS256", func(t *testing.T) {
+		legacy, err := ticket.Init(&config.TicketKey{TicketSecretKey: "123"})
+		require.NoError(t, err)
+		assert.Empty(t, legacy.JWKS().Keys)
+
+		issued := entity.Ticket{UserID: 1, EventID: 1, Exp: 1}
+		require.NoError(t, legacy.Generate(&issued))
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "k1.pem"), privatePEM(t, edKey), 0600))
+
+		migrated, err := ticket.Init(&config.TicketKey{TicketSecretKey: "123", TicketKeysPath: dir, TicketSigningKey: "k1"})
+		require.NoError(t, err)
+		assert.NoError(t, migrated.Validate(&entity.Ticket{Token: issued.Token}))
+
+		withoutSecret, err := ticket.Init(&config.TicketKey{TicketKeysPath: dir, TicketSigningKey: "k1"})
+		require.NoError(t, err)
+		assert.Error(t, withoutSecret.Validate(&entity.Ticket{Token: issued.Token}))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		tick, err := ticket.Init(&config.TicketKey{TicketSecretKey: "123"})
+		require.NoError(t, err)
+
+		issued := entity.Ticket{UserID: 1, EventID: 1, Exp: -48}
+		require.NoError(t, tick.Generate(&issued))
+
+		assert.Error(t, tick.Validate(&entity.Ticket{Token: issued.Token}))
+	})
+}
@@ -2,16 +2,14 @@ package ticket
 
 import (
 	"errors"
+	"fmt"
 	"graduation/internal/entity"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
 func (t *TicketToken) Validate(tick *entity.Ticket) error {
-	token, err := jwt.ParseWithClaims(tick.Token, &TicketClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(t.secretKey), nil
-	})
-
+	token, err := jwt.ParseWithClaims(tick.Token, &TicketClaims{}, t.keyFunc)
 	if err != nil {
 		return err
 	}
@@ -26,3 +24,28 @@ func (t *TicketToken) Validate(tick *entity.Ticket) error {
 
 	return nil
 }
+
+func (t *TicketToken) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || t.secretKey == "" {
+			return nil, errors.New("token without kid")
+		}
+		return []byte(t.secretKey), nil
+	}
+
+	if t.keyring == nil {
+		return nil, errors.New("keyring not configured")
+	}
+
+	key, ok := t.keyring.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+
+	return key.Public, nil
+}
@@ -0,0 +1,108 @@
+// Package validation checks request bodies field by field and collects
+// every problem instead of stopping at the first one.
+package validation
+
+import (
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError is a problem with one field of the request, Field is the JSON
+// name of the field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is returned by Validator.Err when at least one check failed.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Validator records failed checks. Only the first failure of a field is
+// kept, later checks of the same field are skipped.
+type Validator struct {
+	errs Errors
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+func (v *Validator) failed(field string) bool {
+	for _, fe := range v.errs {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Check adds message for field when ok is false.
+func (v *Validator) Check(ok bool, field, message string) {
+	if ok || v.failed(field) {
+		return
+	}
+	v.errs = append(v.errs, FieldError{Field: field, Message: message})
+}
+
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+// MaxLength counts characters, not bytes.
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters", max))
+}
+
+// Mail accepts a bare address like user@mail.ru, without a display name.
+func (v *Validator) Mail(field, value string) {
+	addr, err := netmail.ParseAddress(value)
+	v.Check(err == nil && addr.Address == value, field, "must be an email address")
+}
+
+func (v *Validator) Positive(field string, value int) {
+	v.Check(value > 0, field, "must be positive")
+}
+
+func (v *Validator) Future(field string, value, now time.Time) {
+	v.Check(value.After(now), field, "must be in the future")
+}
+
+// Date parses value with layout, the zero time and false are returned when
+// it does not parse.
+func (v *Validator) Date(field, value, layout string) (time.Time, bool) {
+	date, err := time.Parse(layout, value)
+	v.Check(err == nil, field, "must be a date like "+layout)
+	return date, err == nil
+}
+
+func (v *Validator) OneOf(field, value string, values ...string) {
+	for _, allowed := range values {
+		if value == allowed {
+			return
+		}
+	}
+	v.Check(false, field, "must be one of: "+strings.Join(values, ", "))
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Err returns the collected Errors or nil.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
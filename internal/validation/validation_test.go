@@ -0,0 +1,54 @@
+package validation_test
+
+import (
+	"graduation/internal/validation"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator(t *testing.T) {
+	now := time.Date(2024, 4, 29, 12, 0, 0, 0, time.UTC)
+
+	v := validation.New()
+	v.Required("login", " ")
+	v.MaxLength("login", strings.Repeat("a", 100), 64)
+	v.MaxLength("title", strings.Repeat("я", 200), 200)
+	v.Mail("mail", "User <user@mail.ru>")
+	v.Positive("participants", 0)
+	v.Future("date", now.Add(-time.Minute), now)
+	v.OneOf("locale", "de", "en", "ru")
+
+	_, ok := v.Date("until", "29.04.2024", "2006-01-02")
+	assert.False(t, ok)
+
+	require.False(t, v.Valid())
+	errs, ok := v.Err().(validation.Errors)
+	require.True(t, ok)
+	assert.Equal(t, validation.Errors{
+		{Field: "login", Message: "is required"},
+		{Field: "mail", Message: "must be an email address"},
+		{Field: "participants", Message: "must be positive"},
+		{Field: "date", Message: "must be in the future"},
+		{Field: "locale", Message: "must be one of: en, ru"},
+		{Field: "until", Message: "must be a date like 2006-01-02"},
+	}, errs)
+	assert.Contains(t, errs.Error(), "login: is required")
+}
+
+func TestValidatorValid(t *testing.T) {
+	v := validation.New()
+	v.Required("login", "user_1")
+	v.Mail("mail", "user@mail.ru")
+	v.Positive("participants", 10)
+
+	date, ok := v.Date("date", "2030-03-04", "2006-01-02")
+	assert.True(t, ok)
+	assert.Equal(t, 2030, date.Year())
+
+	assert.True(t, v.Valid())
+	assert.NoError(t, v.Err())
+}